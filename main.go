@@ -11,8 +11,11 @@ import (
 	"perfectOddsBot/services"
 	cardService "perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/interactionService"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/teamService"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -99,12 +102,20 @@ func runApp() {
 		}(sqlDB)
 	}(db)
 
+	err = services.RunFixedPointPointsMigration(db)
+	if err != nil {
+		log.Fatalf("Error running fixed-point points migration: %v", err)
+	}
+
 	err = db.AutoMigrate(
 		&models.Migration{}, &models.Guild{}, &models.User{},
 		&models.CardRarity{}, &models.Card{}, &models.CardOption{},
-		&models.Bet{}, &models.BetEntry{}, &models.BetMessage{},
+		&models.Bet{}, &models.BetOption{}, &models.BetEntry{}, &models.BetMessage{},
 		&models.Parlay{}, &models.ParlayEntry{}, &models.UserInventory{},
 		&models.ErrorLog{}, &models.CardPlayHistory{},
+		&models.SettlementJournal{}, &models.SettlementJournalEntry{},
+		&models.PointsLedgerEntry{}, &models.LineHistory{}, &models.GuildSubscription{},
+		&models.TeamAlias{},
 	)
 	if err != nil {
 		log.Fatalf("Error migrating database: %v", err)
@@ -130,6 +141,36 @@ func runApp() {
 		log.Fatalf("Error running user inventory card code backfill: %v", err)
 	}
 
+	err = services.RunBetSportBackfill(db)
+	if err != nil {
+		log.Fatalf("Error running bet sport backfill: %v", err)
+	}
+
+	err = services.RunBetEntryOddsBackfill(db)
+	if err != nil {
+		log.Fatalf("Error running bet entry odds backfill: %v", err)
+	}
+
+	err = services.RunParlayEntryOddsBackfill(db)
+	if err != nil {
+		log.Fatalf("Error running parlay entry odds backfill: %v", err)
+	}
+
+	err = services.RunGuildSubscriptionBackfill(db)
+	if err != nil {
+		log.Fatalf("Error running guild subscription backfill: %v", err)
+	}
+
+	if fixturesDir := os.Getenv("SPORTS_FIXTURES_DIR"); fixturesDir != "" {
+		log.Printf("Replaying sports data from fixtures in %s", fixturesDir)
+		extService.UseProviders(extService.FixtureProviders(fixturesDir))
+	}
+
+	err = teamService.RefreshTeams()
+	if err != nil {
+		log.Printf("Error loading the team registry: %v", err)
+	}
+
 	token := os.Getenv("DISCORD_BOT_TOKEN")
 	if token == "" {
 		log.Fatalf("DISCORD_BOT_TOKEN not set in environment variables")
@@ -221,7 +262,9 @@ func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 		common.SendError(s, nil, msg, db)
 	}
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			log.Printf("Error setting starting points: %v", err)
+		}
 	}
 	now := time.Now()
 	user.LastActiveAt = &now
@@ -229,8 +272,11 @@ func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 	username := common.GetUsernameFromUser(m.Author)
 	common.UpdateUserUsername(db, &user, username)
 
-	user.Points += guild.PointsPerMessage
-	db.Save(&user)
+	if err := ledgerService.Apply(db, &user, guild.PointsPerMessage, ledgerService.Entry{Reason: ledgerService.ReasonMessage}); err != nil {
+		log.Printf("Error awarding message points: %v", err)
+		return
+	}
+	db.Model(&user).Updates(map[string]interface{}{"last_active_at": now})
 }
 
 func interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -279,7 +325,10 @@ func messageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 	}
 
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			log.Printf("Error setting starting points: %v", err)
+			return
+		}
 	}
 
 	totalReactions := 0
@@ -292,7 +341,10 @@ func messageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 	if totalReactions > 0 {
 		multiplier := math.Pow(2, float64(totalReactions-1))
 		bonusPoints := guild.PointsPerMessage * multiplier
-		user.Points += bonusPoints
+		if err := ledgerService.Apply(db, &user, bonusPoints, ledgerService.Entry{Reason: ledgerService.ReasonReaction}); err != nil {
+			log.Printf("Error awarding reaction points: %v", err)
+			return
+		}
 	}
 
 	if user.Username == nil || *user.Username != m.Author.Username {
@@ -300,5 +352,5 @@ func messageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 		user.Username = &username
 	}
 
-	db.Save(&user)
+	db.Omit("points").Save(&user)
 }
@@ -5,6 +5,23 @@ import (
 	"time"
 )
 
+// Sports a bet can belong to. Custom bets have no sport.
+const (
+	SportCFB = "cfb"
+	SportCBB = "cbb"
+	SportNFL = "nfl"
+	SportNBA = "nba"
+)
+
+// Outcomes a custom bet can be linked to a game for. Every linked bet locks at
+// kickoff; a manual link is still resolved by an admin, while the others are
+// graded from the final score with option 1 as the outcome happening.
+const (
+	LinkManual    = "manual"
+	LinkTeamWins  = "team_wins"
+	LinkTotalOver = "total_over"
+)
+
 type Bet struct {
 	gorm.Model
 	ID            uint `gorm:"primaryKey"`
@@ -15,6 +32,7 @@ type Bet struct {
 	Odds2         int
 	Active        bool
 	Paid          bool `gorm:"default:false"`
+	Voided        bool `gorm:"default:false"`
 	GuildID       string
 	BetsOption1   int
 	BetsOption2   int
@@ -22,7 +40,17 @@ type Bet struct {
 	ChannelID     string
 	CfbdID        *string
 	EspnID        *string
+	Sport         string `gorm:"size:16;index"`
 	GameStartDate *time.Time
 	AdminCreated  bool
 	Spread        *float64
+	Total         *float64
+	Options       []BetOption `gorm:"foreignKey:BetID"`
+	// LockAt is when a custom bet stops taking bets.
+	LockAt *time.Time `gorm:"index"`
+	// Link is the outcome a custom bet linked to a game is graded on. It is
+	// empty for game bets and unlinked custom bets.
+	Link string `gorm:"size:16;not null;default:''"`
+	// LinkTeam is the team a team_wins link is graded on.
+	LinkTeam string
 }
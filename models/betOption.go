@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+type BetOption struct {
+	gorm.Model
+	ID           uint `gorm:"primaryKey"`
+	BetID        uint `gorm:"index"`
+	OptionNumber int
+	Name         string
+	Odds         int
+	BetCount     int `gorm:"default:0"`
+}
@@ -4,13 +4,19 @@ import "gorm.io/gorm"
 
 type BetEntry struct {
 	gorm.Model
-	ID           uint `gorm:"primaryKey"`
-	User         User `gorm:"foreignKey:UserID"`
-	UserID       uint
-	BetID        uint
-	Bet          Bet `gorm:"foreignKey:BetID"`
-	Option       int
-	Amount       int
-	Spread       *float64
-	AutoCloseWin bool
+	ID            uint `gorm:"primaryKey"`
+	User          User `gorm:"foreignKey:UserID"`
+	UserID        uint
+	BetID         uint
+	Bet           Bet `gorm:"foreignKey:BetID"`
+	Option        int
+	Amount        int
+	Spread        *float64
+	Total         *float64
+	Odds          *int
+	AutoCloseWin  bool
+	AutoClosePush bool
+	// CashOut is what the entry was cashed out for. Cashed out entries are
+	// deleted so settlement skips them.
+	CashOut *float64
 }
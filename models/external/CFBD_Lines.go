@@ -10,10 +10,12 @@ type CFBD_BettingLines struct {
 	SeasonType         string      `json:"seasonType"`
 	Week               int         `json:"week"`
 	StartDate          time.Time   `json:"startDate"`
+	HomeTeamID         int         `json:"homeTeamId"`
 	HomeTeam           string      `json:"homeTeam"`
 	HomeConference     string      `json:"homeConference"`
 	HomeClassification string      `json:"homeClassification"`
 	HomeScore          *int        `json:"homeScore"`
+	AwayTeamID         int         `json:"awayTeamId"`
 	AwayTeam           string      `json:"awayTeam"`
 	AwayConference     string      `json:"awayConference"`
 	AwayClassification string      `json:"awayClassification"`
@@ -0,0 +1,17 @@
+package external
+
+type CFBD_Rankings []struct {
+	Season     int         `json:"season"`
+	SeasonType string      `json:"seasonType"`
+	Week       int         `json:"week"`
+	Polls      []CFBD_Poll `json:"polls"`
+}
+
+type CFBD_Poll struct {
+	Poll  string `json:"poll"`
+	Ranks []struct {
+		Rank       int    `json:"rank"`
+		School     string `json:"school"`
+		Conference string `json:"conference"`
+	} `json:"ranks"`
+}
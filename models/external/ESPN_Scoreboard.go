@@ -1,5 +1,7 @@
 package external
 
+import "encoding/json"
+
 type ESPN_Scoreboard struct {
 	Leagues []struct {
 		ID           string `json:"id"`
@@ -28,11 +30,13 @@ type ESPN_Scoreboard struct {
 			Rel         []string `json:"rel"`
 			LastUpdated string   `json:"lastUpdated"`
 		} `json:"logos"`
-		CalendarType        string   `json:"calendarType"`
-		CalendarIsWhitelist bool     `json:"calendarIsWhitelist"`
-		CalendarStartDate   string   `json:"calendarStartDate"`
-		CalendarEndDate     string   `json:"calendarEndDate"`
-		Calendar            []string `json:"calendar"`
+		CalendarType        string `json:"calendarType"`
+		CalendarIsWhitelist bool   `json:"calendarIsWhitelist"`
+		CalendarStartDate   string `json:"calendarStartDate"`
+		CalendarEndDate     string `json:"calendarEndDate"`
+		// Calendar is a list of dates for day leagues and a list of weeks for
+		// football, so it is left undecoded.
+		Calendar json.RawMessage `json:"calendar"`
 	} `json:"leagues"`
 	Day struct {
 		Date string `json:"date"`
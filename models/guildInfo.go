@@ -14,12 +14,11 @@ type Guild struct {
 	GuildID                 string
 	GuildName               string
 	BetChannelID            string
-	PointsPerMessage        float64
-	StartingPoints          float64
+	PointsPerMessage        float64 `gorm:"type:decimal(20,2)"`
+	StartingPoints          float64 `gorm:"type:decimal(20,2)"`
 	PremiumEnabled          bool
-	SubscribedTeam          *string
-	Pool                    float64 `gorm:"default:0"`
-	CardDrawCost            float64 `gorm:"default:10"`
+	Pool                    float64 `gorm:"type:decimal(20,2);default:0"`
+	CardDrawCost            float64 `gorm:"type:decimal(20,2);default:10"`
 	CardDrawCooldownMinutes int     `gorm:"default:60"`
 	CardDrawingEnabled      bool    `gorm:"default:true"`
 	RestrictedDrawEnabled   bool    `gorm:"default:false"`
@@ -28,9 +27,19 @@ type Guild struct {
 	PoolDrainUntil          *time.Time
 	EmperorActiveUntil      *time.Time
 	EmperorHolderDiscordID  *string
-	TotalCardDraws          int `gorm:"default:0"`
-	LastEpicDrawAt          int `gorm:"default:0"`
-	LastMythicDrawAt        int `gorm:"default:0"`
+	TotalCardDraws          int     `gorm:"default:0"`
+	LastEpicDrawAt          int     `gorm:"default:0"`
+	LastMythicDrawAt        int     `gorm:"default:0"`
+	LineAlertThreshold      float64 `gorm:"type:decimal(5,1);default:1.5"`
+	LineAlertOddsThreshold  int     `gorm:"default:25"`
+	Timezone                string  `gorm:"size:64;default:America/New_York"`
+
+	// Bet limits. MaxStakePercent is a percent of the bettor's balance and
+	// the others are in points. A limit of zero is off.
+	MaxStake        int     `gorm:"default:0"`
+	MaxStakePercent float64 `gorm:"type:decimal(5,1);default:0"`
+	MaxExposure     int     `gorm:"default:0"`
+	MaxSideStake    int     `gorm:"default:0"`
 
 	// Expansions
 	TarotExpansion      bool `gorm:"default:true"`
@@ -0,0 +1,43 @@
+package models
+
+import "gorm.io/gorm"
+
+// Kinds of guild subscription.
+const (
+	SubscriptionTeam       = "team"
+	SubscriptionConference = "conference"
+	SubscriptionRanked     = "ranked"
+
+	// HoursBeforeGameDay posts a game's bet the morning of game day, at
+	// GameDayPostHour in the guild's timezone, instead of a number of hours
+	// before it starts.
+	HoursBeforeGameDay = -1
+	GameDayPostHour    = 9
+)
+
+// Bet types a subscription can auto-create.
+const (
+	BetTypeSpread    = "ats"
+	BetTypeMoneyline = "ml"
+	BetTypeTotal     = "total"
+)
+
+// GuildSubscription auto-creates bets in a guild for games that match it: every
+// game a team plays, every game involving a conference's teams, or every game
+// between two ranked teams.
+type GuildSubscription struct {
+	gorm.Model
+	ID      uint   `gorm:"primaryKey"`
+	GuildID string `gorm:"index;not null"`
+	Sport   string `gorm:"size:16;not null"`
+	Kind    string `gorm:"size:16;not null"`
+	// Target is the team or conference name. Ranked subscriptions have none.
+	Target string
+	// ChannelID is where bets are posted. Empty means the guild's bet channel.
+	ChannelID string
+	BetType   string `gorm:"size:16;default:ats"`
+	// HoursBefore holds a game's bet back until it starts within this many
+	// hours. Zero posts it as soon as the game has a line, and
+	// HoursBeforeGameDay posts it the morning of game day.
+	HoursBefore int `gorm:"default:0"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// LineHistory is one observation of a game bet's line. A row is written when
+// the bet is created and whenever a refresh sees the line change, so the first
+// row is the opening line and the last before the game starts is the closing
+// line.
+type LineHistory struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index:idx_line_history_bet_created,priority:2"`
+	BetID     uint      `gorm:"index:idx_line_history_bet_created,priority:1;not null"`
+	Spread    *float64
+	Total     *float64
+	Odds1     int
+	Odds2     int
+	// Alerted is set on the row a line movement alert was posted for. Later
+	// movement is measured from it.
+	Alerted bool `gorm:"default:false"`
+}
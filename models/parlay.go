@@ -2,15 +2,35 @@ package models
 
 import "gorm.io/gorm"
 
+// Kinds of parlay.
+const (
+	// ParlayStandard pays out only if every leg hits.
+	ParlayStandard = "standard"
+	// ParlayRoundRobin is one of the parlays a round robin ticket splits its
+	// stake across, one for every combination of its legs.
+	ParlayRoundRobin = "round_robin"
+	// ParlayTeaser moves every spread leg toward the bettor at reduced odds.
+	ParlayTeaser = "teaser"
+)
+
 type Parlay struct {
 	gorm.Model
-	ID            uint `gorm:"primaryKey"`
-	UserID        uint
-	User          User `gorm:"foreignKey:UserID"`
-	GuildID       string
-	Amount        int
-	TotalOdds     float64
-	Status        string
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint
+	User      User `gorm:"foreignKey:UserID"`
+	GuildID   string
+	Amount    int
+	TotalOdds float64
+	Status    string
+	Type      string `gorm:"size:16;default:standard"`
+	// TicketID groups the parlays of one round robin ticket. Other parlays
+	// have none.
+	TicketID string `gorm:"size:32;index"`
+	// TeaserPoints is how far a teaser moved each spread leg.
+	TeaserPoints float64
+	// CashOut is what the parlay was cashed out for. Its unresolved legs are
+	// marked resolved so settlement skips them.
+	CashOut       *float64
 	ParlayEntries []ParlayEntry
 }
 
@@ -23,6 +43,10 @@ type ParlayEntry struct {
 	Bet            Bet `gorm:"foreignKey:BetID"`
 	SelectedOption int
 	Spread         *float64
+	Total          *float64
+	Odds           *int
 	Resolved       bool `gorm:"default:false"`
 	Won            *bool
+	Push           bool `gorm:"default:false"`
+	Void           bool `gorm:"default:false"`
 }
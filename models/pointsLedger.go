@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PointsLedgerEntry is one movement of a user's points. Rows are only ever
+// inserted, so the ledger has no UpdatedAt or DeletedAt.
+type PointsLedgerEntry struct {
+	ID           uint      `gorm:"primaryKey"`
+	CreatedAt    time.Time `gorm:"index:idx_points_ledger_user_created,priority:2"`
+	UserID       uint      `gorm:"index:idx_points_ledger_user_created,priority:1; not null"`
+	GuildID      string    `gorm:"index; size:64; not null"`
+	Delta        float64   `gorm:"type:decimal(20,2)"`
+	BalanceAfter float64   `gorm:"type:decimal(20,2)"`
+	Reason       string    `gorm:"index; size:32; not null"`
+	BetID        *uint     `gorm:"index"`
+	ParlayID     *uint
+	CardID       *uint
+	Job          string `gorm:"size:64"`
+	Note         string `gorm:"size:255"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of SettlementJournalEntry. Inventory lines are a card consumed during
+// the settlement; created and updated inventory lines are a card added, or
+// changed in place, such as its expiry or use count.
+const (
+	JournalEntryUser             = "user"
+	JournalEntryInventory        = "inventory"
+	JournalEntryInventoryCreated = "inventory_created"
+	JournalEntryInventoryUpdated = "inventory_updated"
+	JournalEntryParlay           = "parlay"
+	JournalEntryParlayEntry      = "parlay_entry"
+	JournalEntryBetEntry         = "bet_entry"
+)
+
+// SettlementJournal records everything a bet's settlement changed so the
+// settlement can be reversed.
+type SettlementJournal struct {
+	gorm.Model
+	ID            uint   `gorm:"primaryKey"`
+	BetID         uint   `gorm:"index"`
+	GuildID       string `gorm:"index; size:64"`
+	WinningOption int
+	ScoreDiff     int
+	TotalScore    int
+	Scored        bool
+	BetWasActive  bool
+	PoolDelta     float64 `gorm:"type:decimal(20,2)"`
+	ReversedAt    *time.Time
+	Entries       []SettlementJournalEntry `gorm:"foreignKey:JournalID"`
+}
+
+// SettlementJournalEntry is one change made by a settlement. User lines hold the
+// deltas that were applied; inventory lines name a card that was consumed,
+// created or changed, with the card's state from before the settlement; parlay,
+// parlay entry and bet entry lines hold the state from before the settlement.
+type SettlementJournalEntry struct {
+	gorm.Model
+	ID        uint   `gorm:"primaryKey"`
+	JournalID uint   `gorm:"index"`
+	Kind      string `gorm:"size:32"`
+
+	UserID               *uint
+	PointsDelta          float64 `gorm:"type:decimal(20,2)"`
+	TotalBetsWonDelta    int
+	TotalBetsLostDelta   int
+	TotalPointsWonDelta  float64 `gorm:"type:decimal(20,2)"`
+	TotalPointsLostDelta float64 `gorm:"type:decimal(20,2)"`
+
+	InventoryID           *uint
+	InventoryTargetBetID  *uint
+	InventoryTargetUserID *string `gorm:"size:64"`
+	InventoryBetAmount    float64 `gorm:"type:decimal(20,2)"`
+	InventoryTimesApplied int
+	InventoryExpiresAt    *time.Time
+
+	ParlayID        *uint
+	ParlayStatus    string
+	ParlayTotalOdds float64
+
+	ParlayEntryID *uint
+	Resolved      bool
+	Won           *bool
+	Push          bool
+
+	BetEntryID    *uint
+	AutoCloseWin  bool
+	AutoClosePush bool
+}
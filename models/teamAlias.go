@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TeamAlias is another spelling of a team that a guild's admins have taught the
+// bot, such as a nickname or a data source's abbreviation.
+type TeamAlias struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	GuildID   string `gorm:"size:32;uniqueIndex:idx_team_alias_guild_alias;not null"`
+	// Alias is stored as typed. Lookups normalize it.
+	Alias string `gorm:"size:100;uniqueIndex:idx_team_alias_guild_alias;not null"`
+	// Team is the canonical name the alias resolves to.
+	Team      string `gorm:"size:100;not null"`
+	CreatedBy string
+}
@@ -15,8 +15,8 @@ type UserInventory struct {
 	// Card         Card   `gorm:"foreignKey:CardID"`
 	TargetBetID  *uint
 	TargetUserID *string `gorm:"size:64"`
-	BetAmount    float64
-	User         User `gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE"`
-	TimesApplied int  `gorm:"not null;default:0"`
+	BetAmount    float64 `gorm:"type:decimal(20,2)"`
+	User         User    `gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE"`
+	TimesApplied int     `gorm:"not null;default:0"`
 	ExpiresAt    *time.Time
 }
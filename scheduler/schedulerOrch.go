@@ -5,6 +5,8 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/scheduler/scheduler_jobs"
 	scheduled_cards "perfectOddsBot/scheduler/scheduler_jobs/scheduled_cards"
+	"perfectOddsBot/services/teamService"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
@@ -14,71 +16,24 @@ import (
 func SetupCron(s *discordgo.Session, db *gorm.DB) {
 	cronService := cron.New(cron.WithSeconds())
 
-	_, err := cronService.AddFunc("0 0 */1 * 8-12 *", func() {
-		// // Every hour, August through December
-		err := scheduler_jobs.CheckGameEnd(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-	})
-	_, err = cronService.AddFunc("0 0 */1 * 1-5 *", func() {
-		// // Every hour, January through May
-		err := scheduler_jobs.CheckGameEnd(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-	})
-
-	_, err = cronService.AddFunc("0 0 9 * 8-12 *", func() {
-		// // At 9am every day, August through December
-		err := scheduler_jobs.CheckCFBLines(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		err = scheduler_jobs.CheckSubscribedCFBTeam(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-	})
-	_, err = cronService.AddFunc("0 0 9 * 1-2 *", func() {
-		// // At 9am every day, January through February
-		err := scheduler_jobs.CheckCFBLines(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		err = scheduler_jobs.CheckSubscribedCFBTeam(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
+	// Sports jobs poll at a rate set by each sport's season calendar. The
+	// schedule ticks every minute and runs whichever jobs are due.
+	_, err := cronService.AddFunc("0 * * * * *", func() {
+		schedule.tick(s, db, time.Now())
 	})
 
-	_, err = cronService.AddFunc("0 */5 * * 8-12 *", func() {
-		// // Every 5 minutes, August through December
-		err := scheduler_jobs.CheckGameStart(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-	})
-	_, err = cronService.AddFunc("0 */5 * * 1-5 *", func() {
-		// // Every 5 minutes, January through May
-		err := scheduler_jobs.CheckGameStart(s, db)
+	// Custom bets lock at the time they were created with, so check every
+	// minute.
+	_, err = cronService.AddFunc("0 * * * * *", func() {
+		err := scheduler_jobs.CheckBetLocks(s, db)
 		if err != nil {
 			fmt.Println(err)
 		}
 	})
 
-	_, err = cronService.AddFunc("0 0 */1 * 10-12 *", func() {
-		// // Every hour, October through December
-		err := scheduler_jobs.CheckSubscribedCBBTeam(s, db)
-		if err != nil {
-			fmt.Println(err)
-		}
-	})
-	_, err = cronService.AddFunc("0 0 */1 * 1-5 *", func() {
-		// // Every hour, January through May
-		err := scheduler_jobs.CheckSubscribedCBBTeam(s, db)
+	// Reload the team registry daily so new schools and renames are picked up.
+	_, err = cronService.AddFunc("0 0 6 * * *", func() {
+		err := teamService.RefreshTeams()
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -0,0 +1,113 @@
+package scheduler_jobs
+
+import (
+	"fmt"
+	"log"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/messageService"
+	"runtime/debug"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// CheckBetLocks closes the open custom bets whose lock time has passed. Like a
+// bet locked by hand, the bet's message keeps its resolve and void buttons.
+func CheckBetLocks(s *discordgo.Session, db *gorm.DB) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered in CheckBetLocks", r)
+			debug.PrintStack()
+			err = fmt.Errorf("panic recovered in CheckBetLocks: %v", r)
+		}
+	}()
+
+	var betList []models.Bet
+
+	result := db.Preload("Options").Where("paid = 0 AND active = 1 AND lock_at IS NOT NULL AND lock_at <= ? AND deleted_at IS NULL", time.Now()).Find(&betList)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, bet := range betList {
+		result := db.Model(&models.Bet{}).Where("id = ? AND active = 1", bet.ID).Update("active", false)
+		if result.Error != nil {
+			log.Printf("Error locking bet %d: %v\n", bet.ID, result.Error)
+			continue
+		}
+		// Locked by an admin in the meantime.
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		embed := lockedBetEmbed(bet)
+		if bet.MessageID != nil {
+			_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ID:      *bet.MessageID,
+				Channel: bet.ChannelID,
+				Embeds:  &[]*discordgo.MessageEmbed{embed},
+				Components: &[]discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{messageService.GetResolveButton(bet.ID), messageService.GetVoidButton(bet.ID)},
+					},
+				},
+			})
+			if err != nil {
+				log.Printf("Error updating locked message for bet %d: %v\n", bet.ID, err)
+			}
+		}
+
+		var secondaryMsgs []models.BetMessage
+		if err := db.Where("active = 1 AND bet_id = ?", bet.ID).Find(&secondaryMsgs).Error; err != nil {
+			log.Printf("Error finding secondary messages for bet %d: %v\n", bet.ID, err)
+		}
+		for _, msg := range secondaryMsgs {
+			msg.Active = false
+			db.Save(&msg)
+			if msg.MessageID == nil {
+				continue
+			}
+			_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ID:         *msg.MessageID,
+				Channel:    msg.ChannelID,
+				Embeds:     &[]*discordgo.MessageEmbed{embed},
+				Components: &[]discordgo.MessageComponent{},
+			})
+			if err != nil {
+				log.Printf("Error updating locked secondary message for bet %d: %v\n", bet.ID, err)
+			}
+		}
+
+		_, err := s.ChannelMessageSend(bet.ChannelID, fmt.Sprintf("Bet '%s' has been locked and is no longer accepting new bets.", bet.Description))
+		if err != nil {
+			log.Printf("Error announcing lock of bet %d: %v\n", bet.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// lockedBetEmbed shows a custom bet that has stopped taking bets.
+func lockedBetEmbed(bet models.Bet) *discordgo.MessageEmbed {
+	title := "🔒 Bet has been LOCKED"
+	if bet.Link == models.LinkTeamWins || bet.Link == models.LinkTotalOver {
+		title = "🔒 Bet has been LOCKED (Will Auto Resolve)"
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, option := range common.GetBetOptions(bet) {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s", common.GetOptionEmoji(option.OptionNumber), option.Name),
+			Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(option.Odds))),
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: bet.Description,
+		Fields:      fields,
+		Color:       0x3498db,
+	}
+}
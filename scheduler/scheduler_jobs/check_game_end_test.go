@@ -1,6 +1,8 @@
 package scheduler_jobs
 
 import (
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/betService"
 	"perfectOddsBot/services/common"
 	"testing"
 )
@@ -277,3 +279,124 @@ func TestCalculateBetEntryWin(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateTotalEntryWin(t *testing.T) {
+	tests := []struct {
+		name       string
+		option     int
+		totalScore int
+		total      float64
+		expected   bool
+		scenario   string
+	}{
+		{
+			name:       "Over wins",
+			option:     1,
+			totalScore: 52,
+			total:      48.5,
+			expected:   true,
+			scenario:   "Teams combine for 52, over 48.5 wins",
+		},
+		{
+			name:       "Over loses",
+			option:     1,
+			totalScore: 45,
+			total:      48.5,
+			expected:   false,
+			scenario:   "Teams combine for 45, over 48.5 loses",
+		},
+		{
+			name:       "Under wins",
+			option:     2,
+			totalScore: 45,
+			total:      48.5,
+			expected:   true,
+			scenario:   "Teams combine for 45, under 48.5 wins",
+		},
+		{
+			name:       "Under loses",
+			option:     2,
+			totalScore: 49,
+			total:      48.5,
+			expected:   false,
+			scenario:   "Teams combine for 49, under 48.5 loses",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := common.CalculateTotalEntryWin(tt.option, tt.totalScore, tt.total)
+			if result != tt.expected {
+				t.Errorf("CalculateTotalEntryWin(option=%d, totalScore=%d, total=%.1f) = %v, want %v\nScenario: %s",
+					tt.option, tt.totalScore, tt.total, result, tt.expected, tt.scenario)
+			}
+		})
+	}
+}
+
+func TestCalculateBetEntryPush(t *testing.T) {
+	tests := []struct {
+		name      string
+		scoreDiff int
+		spread    float64
+		expected  bool
+	}{
+		{name: "Home favored lands on the number", scoreDiff: 7, spread: -7, expected: true},
+		{name: "Away favored lands on the number", scoreDiff: -3, spread: 3, expected: true},
+		{name: "Pick'em tie", scoreDiff: 0, spread: 0, expected: true},
+		{name: "Half point line never pushes", scoreDiff: 7, spread: -7.5, expected: false},
+		{name: "Home covers", scoreDiff: 10, spread: -7, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := common.CalculateBetEntryPush(tt.scoreDiff, tt.spread)
+			if result != tt.expected {
+				t.Errorf("CalculateBetEntryPush(scoreDiff=%d, spread=%.1f) = %v, want %v",
+					tt.scoreDiff, tt.spread, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculateTotalEntryPush(t *testing.T) {
+	if !common.CalculateTotalEntryPush(48, 48) {
+		t.Errorf("CalculateTotalEntryPush(48, 48) = false, want true")
+	}
+	if common.CalculateTotalEntryPush(48, 48.5) {
+		t.Errorf("CalculateTotalEntryPush(48, 48.5) = true, want false")
+	}
+}
+
+func TestLinkedScores(t *testing.T) {
+	total := 44.5
+	tests := []struct {
+		name    string
+		bet     models.Bet
+		winning int
+		matched bool
+	}{
+		{"Game bet keeps the home team first", models.Bet{Option1: "Bears", Option2: "Packers"}, 2, true},
+		{"Linked home team wins", models.Bet{Link: models.LinkTeamWins, LinkTeam: "Bears"}, 2, true},
+		{"Linked away team wins", models.Bet{Link: models.LinkTeamWins, LinkTeam: "Packers"}, 1, true},
+		{"Linked total goes over", models.Bet{Link: models.LinkTotalOver, Total: &total}, 1, true},
+		{"Linked team isn't playing", models.Bet{Link: models.LinkTeamWins, LinkTeam: "Lions"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Packers win 27-20 at the Bears.
+			score1, score2, matched := linkedScores(tt.bet, "Bears", 20, "Packers", 27)
+			if matched != tt.matched {
+				t.Fatalf("linkedScores matched = %v, want %v", matched, tt.matched)
+			}
+			if !matched {
+				return
+			}
+			winning := betService.ScoreResult(tt.bet, score1-score2, score1+score2).WinningOption
+			if winning != tt.winning {
+				t.Errorf("winning option = %d, want %d", winning, tt.winning)
+			}
+		})
+	}
+}
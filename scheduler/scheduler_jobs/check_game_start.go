@@ -7,13 +7,14 @@ import (
 	"perfectOddsBot/services/common"
 	"runtime/debug"
 	"time"
-	_ "time/tzdata"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
 )
 
-func CheckGameStart(s *discordgo.Session, db *gorm.DB) (err error) {
+// CheckGameStart closes the open game bets of the given sports once their games
+// have started.
+func CheckGameStart(s *discordgo.Session, db *gorm.DB, sports []string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Println("Recovered in CheckGameStart", r)
@@ -24,29 +25,20 @@ func CheckGameStart(s *discordgo.Session, db *gorm.DB) (err error) {
 
 	var betList []models.Bet
 
-	result := db.Where("paid = 0 AND active = 1 AND (cfbd_id IS NOT NULL OR espn_id IS NOT NULL)").Find(&betList)
+	result := db.Where("paid = 0 AND active = 1 AND (cfbd_id IS NOT NULL OR espn_id IS NOT NULL) AND link = '' AND sport IN ?", sports).Find(&betList)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	for _, bet := range betList {
-		est, err := time.LoadLocation("America/New_York")
-		if err != nil {
-			return err
-		}
-
-		currentTimeEST := time.Now().In(est)
-
 		if bet.GameStartDate != nil {
-			t := bet.GameStartDate.In(est)
-
-			if currentTimeEST.After(t) {
+			if time.Now().After(*bet.GameStartDate) {
 				bet.Active = false
 				db.Save(&bet)
 
 				embed := &discordgo.MessageEmbed{
 					Title:       "📢 Bet has been CLOSED (Will Auto Resolve)",
-					Description: bet.Description,
+					Description: common.GameBetDescription(bet),
 					Fields: []*discordgo.MessageEmbedField{
 						{
 							Name:  fmt.Sprintf("1️⃣ %s", bet.Option1),
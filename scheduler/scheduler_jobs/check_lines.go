@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"log"
 	"perfectOddsBot/models"
-	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/betService"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/messageService"
 	"runtime/debug"
 	"strconv"
@@ -17,120 +18,199 @@ import (
 	"gorm.io/gorm"
 )
 
-func CheckCFBLines(s *discordgo.Session, db *gorm.DB) (err error) {
+// CheckLines refreshes the line on every open game bet of the given sports,
+// records each change in the bet's line history, updates the bet's messages and
+// alerts the guild's bet channel when a line moves past the guild's threshold.
+func CheckLines(s *discordgo.Session, db *gorm.DB, sports []string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Println("Recovered in CheckCFBLines", r)
+			log.Println("Recovered in CheckLines", r)
 			debug.PrintStack()
-			err = fmt.Errorf("panic recovered in CheckCFBLines: %v", r)
+			err = fmt.Errorf("panic recovered in CheckLines: %v", r)
 		}
 	}()
 
 	var betList []models.Bet
 
-	result := db.Where("paid = 0 AND active = 1 AND cfbd_id IS NOT NULL AND spread IS NOT NULL").Find(&betList)
+	result := db.Where("paid = 0 AND active = 1 AND (cfbd_id IS NOT NULL OR espn_id IS NOT NULL) AND link = '' AND sport IN ? AND deleted_at IS NULL", sports).Find(&betList)
 	if result.Error != nil {
 		return result.Error
 	}
-
-	est, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		return err
+	if len(betList) == 0 {
+		return nil
 	}
 
-	currentTimeEST := time.Now().In(est)
-	formattedTime := currentTimeEST.Format("Mon 03:04 pm MST")
-
-	cfbdList, err := extService.GetCFBGames()
-	if err != nil {
-		return err
-	}
-
-	betMap := make(map[int]external.CFBD_BettingLines)
-	for _, obj := range cfbdList {
-		betMap[obj.ID] = obj
-	}
+	quotes := lineQuotes(betList)
+	guilds := make(map[string]*models.Guild)
 
 	for _, bet := range betList {
-		betCfbdId, _ := strconv.Atoi(*bet.CfbdID)
-		if obj, found := betMap[betCfbdId]; found {
-			line, lineErr := common.PickLine(obj.Lines)
-			if lineErr != nil {
-				fmt.Println(lineErr)
-				continue
-			}
+		quote, found := quotes[lineQuoteKey(bet)]
+		if !found {
+			continue
+		}
 
-			// if no spread on the bet, don't update the bet because it's an ML bet
-			if bet.Spread == nil {
+		guild, found := guilds[bet.GuildID]
+		if !found {
+			guild, err = guildService.GetGuildInfo(s, db, bet.GuildID, bet.ChannelID)
+			if err != nil {
+				log.Printf("Error loading guild %s for bet %d: %v\n", bet.GuildID, bet.ID, err)
 				continue
 			}
+			guilds[bet.GuildID] = guild
+		}
 
-			var lineValue float64
-			if line.Spread != nil {
-				lineValue = *line.Spread
-			} else {
-				common.SendError(s, nil, err, db)
-				return err
-			}
+		changed, move, err := betService.RefreshLine(db, &bet, quote, *guild)
+		if err != nil {
+			log.Printf("Error refreshing line for bet %d: %v\n", bet.ID, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
 
-			bet.Option1 = fmt.Sprintf("%s %s", obj.HomeTeam, common.FormatOdds(lineValue))
-			bet.Option2 = fmt.Sprintf("%s %s", obj.AwayTeam, common.FormatOdds(lineValue*-1))
-			bet.Spread = &lineValue
-			db.Save(&bet)
-
-			buttons := messageService.GetBetOnlyButtonsList(bet.Option1, bet.Option2, bet.ID)
-			embed := &discordgo.MessageEmbed{
-				Title:       fmt.Sprintf("📢 Bet Lines Updated %s (Will Auto Close & Resolve)", formattedTime),
-				Description: bet.Description,
-				Fields: []*discordgo.MessageEmbedField{
-					{
-						Name:  fmt.Sprintf("1️⃣ %s", bet.Option1),
-						Value: fmt.Sprintf("Odds: %s", common.FormatOdds(-110)),
-					},
-					{
-						Name:  fmt.Sprintf("2️⃣ %s", bet.Option2),
-						Value: fmt.Sprintf("Odds: %s", common.FormatOdds(-110)),
-					},
-				},
-				Color: 0x3498db,
-			}
+		updateBetMessages(s, db, bet, common.FormatGameTime(time.Now(), common.GuildLocation(*guild)))
 
-			_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-				ID:      *bet.MessageID,
-				Channel: bet.ChannelID,
-				Embeds:  &[]*discordgo.MessageEmbed{embed},
-				Components: &[]discordgo.MessageComponent{
-					discordgo.ActionsRow{
-						Components: buttons,
-					},
-				},
-			})
-
-			var secondaryMsgs []models.BetMessage
-			secondaryResult := db.Where("active = 1 AND bet_id = ?", bet.ID).Find(&secondaryMsgs)
-			if secondaryResult.Error != nil {
-				return fmt.Errorf("Error finding secondary messageService: %v", secondaryResult.Error)
+		if move != nil && guild.BetChannelID != "" {
+			since := "it opened"
+			if move.From.Alerted {
+				since = "the last alert"
 			}
-			if len(secondaryMsgs) > 0 {
-				for _, msg := range secondaryMsgs {
-					_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-						ID:      *msg.MessageID,
-						Channel: msg.ChannelID,
-						Embeds:  &[]*discordgo.MessageEmbed{embed},
-						Components: &[]discordgo.MessageComponent{
-							discordgo.ActionsRow{
-								Components: buttons,
-							},
-						},
-					})
-				}
+			embed := messageService.BuildLineMovementEmbed(bet.Description, betService.FormatLine(bet, move.From), betService.FormatLine(bet, move.To), since)
+			if _, err := s.ChannelMessageSendEmbed(guild.BetChannelID, embed); err != nil {
+				log.Printf("Error sending line alert for bet %d: %v\n", bet.ID, err)
 			}
+		}
+	}
 
-			if err != nil {
+	return nil
+}
+
+// lineQuoteKey identifies the game a bet was created from.
+func lineQuoteKey(bet models.Bet) string {
+	if bet.CfbdID != nil {
+		return models.SportCFB + ":" + *bet.CfbdID
+	}
+	if bet.EspnID != nil {
+		sport, err := extService.ESPNSport(bet.Sport)
+		if err != nil {
+			return ""
+		}
+		return sport.Key + ":" + *bet.EspnID
+	}
+	return ""
+}
+
+// lineQuotes fetches the current line for every game the bets were created
+// from, keyed by lineQuoteKey. A game whose line can't be fetched is left out.
+func lineQuotes(betList []models.Bet) map[string]betService.LineQuote {
+	quotes := make(map[string]betService.LineQuote)
+
+	needCFB := false
+	for _, bet := range betList {
+		if bet.CfbdID != nil {
+			needCFB = true
+			break
+		}
+	}
+	if needCFB {
+		cfbdList, err := extService.GetCFBGames()
+		if err != nil {
+			log.Printf("Error fetching CFB lines: %v\n", err)
+		}
+		for _, game := range cfbdList {
+			line, lineErr := common.PickLine(game.Lines)
+			if lineErr != nil {
 				continue
 			}
+			quotes[models.SportCFB+":"+strconv.Itoa(game.ID)] = betService.CFBDQuote(line)
 		}
 	}
 
-	return nil
+	for _, bet := range betList {
+		if bet.EspnID == nil {
+			continue
+		}
+		key := lineQuoteKey(bet)
+		if key == "" {
+			continue
+		}
+		if _, found := quotes[key]; found {
+			continue
+		}
+		sport, _ := extService.ESPNSport(bet.Sport)
+		eventID, err := strconv.Atoi(*bet.EspnID)
+		if err != nil {
+			continue
+		}
+		lines, err := extService.GetESPNLines(sport, eventID)
+		if err != nil {
+			log.Printf("Error fetching %s lines for event %d: %v\n", sport.Name, eventID, err)
+			continue
+		}
+		line, err := common.PickESPNLine(lines)
+		if err != nil {
+			continue
+		}
+		quotes[key] = betService.ESPNQuote(line)
+	}
+
+	return quotes
+}
+
+// updateBetMessages edits the bet's message, and any copies posted to other
+// channels, to show the refreshed line.
+func updateBetMessages(s *discordgo.Session, db *gorm.DB, bet models.Bet, formattedTime string) {
+	buttons := messageService.GetBetOnlyButtonsList(bet.Option1, bet.Option2, bet.ID)
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📢 Bet Lines Updated %s (Will Auto Close & Resolve)", formattedTime),
+		Description: common.GameBetDescription(bet),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  fmt.Sprintf("1️⃣ %s", bet.Option1),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds1))),
+			},
+			{
+				Name:  fmt.Sprintf("2️⃣ %s", bet.Option2),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds2))),
+			},
+		},
+		Color: 0x3498db,
+	}
+	components := &[]discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: buttons,
+		},
+	}
+
+	if bet.MessageID != nil {
+		_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *bet.MessageID,
+			Channel:    bet.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: components,
+		})
+		if err != nil {
+			log.Printf("Error updating message for bet %d: %v\n", bet.ID, err)
+		}
+	}
+
+	var secondaryMsgs []models.BetMessage
+	if err := db.Where("active = 1 AND bet_id = ?", bet.ID).Find(&secondaryMsgs).Error; err != nil {
+		log.Printf("Error finding secondary messages for bet %d: %v\n", bet.ID, err)
+		return
+	}
+	for _, msg := range secondaryMsgs {
+		if msg.MessageID == nil {
+			continue
+		}
+		_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *msg.MessageID,
+			Channel:    msg.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: components,
+		})
+		if err != nil {
+			log.Printf("Error updating secondary message for bet %d: %v\n", bet.ID, err)
+		}
+	}
 }
@@ -0,0 +1,346 @@
+package scheduler_jobs
+
+import (
+	"fmt"
+	"log"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/betService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/messageService"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// liveGame is the state of a game in progress, with scores in the order of a
+// bet's options.
+type liveGame struct {
+	// Score reads like "Michigan 14 @ Ohio State 21".
+	Score string
+	// Status is the period and clock, such as "5:32 - 3rd".
+	Status     string
+	Score1     int
+	Score2     int
+	InProgress bool
+	Final      bool
+	// Remaining is the share of regulation left to play.
+	Remaining float64
+}
+
+// liveScoresMaxAge is how old a cached scoreboard can be when tracking live
+// games or paying out finished ones. It is under the tracker's two-minute poll,
+// so every poll fetches new scores while jobs that run together share them.
+const liveScoresMaxAge = time.Minute
+
+// overtimeRemaining is the share of a game treated as left during overtime.
+const overtimeRemaining = 0.05
+
+// gamePeriods is the number of regulation periods and their length in seconds
+// by sport. Sports that aren't listed play football's.
+var gamePeriods = map[string][2]int{
+	models.SportCFB: {4, 15 * 60},
+	models.SportNFL: {4, 15 * 60},
+	models.SportNBA: {4, 12 * 60},
+	models.SportCBB: {2, 20 * 60},
+}
+
+// liveRendered remembers what each bet's message last showed, so unchanged
+// games aren't edited again.
+var (
+	liveRendered   = make(map[uint]string)
+	liveRenderedMu sync.Mutex
+)
+
+// CheckLiveGames edits the messages of closed game bets of the given sports
+// with their game's live score, period, clock and which side is covering. A bet
+// whose game has gone final is settled straight away.
+func CheckLiveGames(s *discordgo.Session, db *gorm.DB, sports []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered in CheckLiveGames", r)
+			debug.PrintStack()
+			err = fmt.Errorf("panic recovered in CheckLiveGames: %v", r)
+		}
+	}()
+
+	var betList []models.Bet
+
+	result := db.Where("paid = 0 AND active = 0 AND (cfbd_id IS NOT NULL OR espn_id IS NOT NULL) AND link = '' AND sport IN ? AND game_start_date <= ? AND deleted_at IS NULL", sports, time.Now()).Find(&betList)
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(betList) == 0 {
+		return nil
+	}
+
+	cfbGames, espnGames := liveScoreboards(betList)
+
+	for _, bet := range betList {
+		var game liveGame
+		var found bool
+		if bet.CfbdID != nil {
+			id, _ := strconv.Atoi(*bet.CfbdID)
+			game, found = cfbGames[id]
+		} else if bet.EspnID != nil {
+			sport, _ := extService.ESPNSport(bet.Sport)
+			var event external.ESPN_Event
+			event, found = espnGames[sport.Key][*bet.EspnID]
+			if found {
+				game = espnLiveGame(bet, event)
+			}
+		}
+		if !found || (!game.InProgress && !game.Final) {
+			continue
+		}
+		if game.InProgress {
+			betService.SetLiveState(bet.ID, betService.LiveState{
+				Score1:    game.Score1,
+				Score2:    game.Score2,
+				Remaining: game.Remaining,
+				UpdatedAt: time.Now(),
+			})
+		}
+
+		covering := liveCovering(bet, game)
+		rendered := strings.Join([]string{game.Score, game.Status, covering}, "|")
+
+		liveRenderedMu.Lock()
+		unchanged := liveRendered[bet.ID] == rendered
+		liveRenderedMu.Unlock()
+
+		if !unchanged {
+			embed := messageService.BuildLiveGameEmbed(bet, game.Score, game.Status, covering, game.Final)
+			editClosedBetMessages(s, db, bet, embed)
+
+			liveRenderedMu.Lock()
+			liveRendered[bet.ID] = rendered
+			liveRenderedMu.Unlock()
+		}
+
+		if game.Final {
+			err := ResolveCFBBBet(s, bet, db, game.Score1-game.Score2, game.Score1+game.Score2)
+			if err != nil {
+				log.Printf("Error settling bet %d on its final score: %v\n", bet.ID, err)
+				continue
+			}
+			liveRenderedMu.Lock()
+			delete(liveRendered, bet.ID)
+			liveRenderedMu.Unlock()
+			betService.ClearLiveState(bet.ID)
+		}
+	}
+
+	return nil
+}
+
+// liveScoreboards fetches the scoreboards for the games the bets are on: CFB
+// games by CFBD id, and ESPN events by sport and event id.
+func liveScoreboards(betList []models.Bet) (map[int]liveGame, map[string]map[string]external.ESPN_Event) {
+	cfbGames := make(map[int]liveGame)
+	espnGames := make(map[string]map[string]external.ESPN_Event)
+
+	needCFB := false
+	espnSports := make(map[string]extService.Sport)
+	for _, bet := range betList {
+		if bet.CfbdID != nil {
+			needCFB = true
+		}
+		if bet.EspnID != nil {
+			sport, err := extService.ESPNSport(bet.Sport)
+			if err != nil {
+				continue
+			}
+			espnSports[sport.Key] = sport
+		}
+	}
+
+	if needCFB {
+		scoreboard, err := extService.GetCFBScoreboardWithin(liveScoresMaxAge)
+		if err != nil {
+			log.Printf("Error fetching CFB scoreboard: %v\n", err)
+		}
+		for _, game := range scoreboard {
+			cfbGames[game.ID] = cfbLiveGame(game.Status, game.Period, game.Clock, game.HomeTeam, game.AwayTeam)
+		}
+	}
+
+	for key, sport := range espnSports {
+		events, err := extService.GetESPNGamesWithin(sport, liveScoresMaxAge)
+		if err != nil {
+			log.Printf("Error fetching %s games: %v\n", sport.Name, err)
+			continue
+		}
+		espnGames[key] = make(map[string]external.ESPN_Event)
+		for _, event := range events {
+			espnGames[key][event.ID] = event
+		}
+	}
+
+	return cfbGames, espnGames
+}
+
+// cfbLiveGame reads a CFBD scoreboard game. CFB bets always have the home team
+// as their first option.
+func cfbLiveGame(status string, period *int, clock *string, home external.CFBD_ScoreboardTeam, away external.CFBD_ScoreboardTeam) liveGame {
+	game := liveGame{
+		InProgress: status == "in_progress",
+		Final:      status == "completed",
+	}
+	if home.Points != nil {
+		game.Score1 = *home.Points
+	}
+	if away.Points != nil {
+		game.Score2 = *away.Points
+	}
+	game.Score = fmt.Sprintf("%s %d @ %s %d", away.Name, game.Score2, home.Name, game.Score1)
+
+	switch {
+	case game.Final:
+		game.Status = "Final"
+	case period != nil:
+		game.Status = fmt.Sprintf("Q%d", *period)
+		clockText := ""
+		if clock != nil {
+			clockText = *clock
+			game.Status += " " + strings.TrimPrefix(*clock, "00:")
+		}
+		game.Remaining = gameRemaining(models.SportCFB, *period, clockText)
+	default:
+		game.Status = "In progress"
+		// Without a period, assume half the game is left.
+		game.Remaining = 0.5
+	}
+	return game
+}
+
+// espnLiveGame reads an ESPN event, with scores in the order of the bet's
+// options.
+func espnLiveGame(bet models.Bet, event external.ESPN_Event) liveGame {
+	game := liveGame{
+		InProgress: event.Status.Type.State == "in",
+		Final:      event.Status.Type.Name == "STATUS_FINAL",
+		Status:     event.Status.Type.ShortDetail,
+	}
+	game.Score1, game.Score2 = espnOptionScores(bet, event)
+
+	if len(event.Competitions) > 0 {
+		var home, away external.ESPN_Competitor
+		for _, comp := range event.Competitions[0].Competitors {
+			if comp.HomeAway == "home" {
+				home = comp
+			} else {
+				away = comp
+			}
+		}
+		game.Score = fmt.Sprintf("%s %s @ %s %s", away.Team.ShortDisplayName, away.Score, home.Team.ShortDisplayName, home.Score)
+	}
+	if game.Status == "" {
+		game.Status = fmt.Sprintf("Period %d %s", event.Status.Period, event.Status.DisplayClock)
+	}
+	game.Remaining = gameRemaining(bet.Sport, event.Status.Period, event.Status.DisplayClock)
+	return game
+}
+
+// gameRemaining works out the share of regulation left from the period and a
+// clock like "5:32" or "00:05:32". Overtime counts as overtimeRemaining, since
+// its clock isn't comparable across sports.
+func gameRemaining(sport string, period int, clock string) float64 {
+	periods, found := gamePeriods[sport]
+	if !found {
+		periods = gamePeriods[models.SportNFL]
+	}
+	count, length := periods[0], periods[1]
+	if period < 1 {
+		return 1
+	}
+	if period > count {
+		return overtimeRemaining
+	}
+
+	clockSeconds := length
+	if clock != "" {
+		clockSeconds = 0
+		for _, part := range strings.Split(clock, ":") {
+			value, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				clockSeconds = length
+				break
+			}
+			clockSeconds = clockSeconds*60 + int(value)
+		}
+	}
+	if clockSeconds > length {
+		clockSeconds = length
+	}
+
+	remaining := (count-period)*length + clockSeconds
+	return float64(remaining) / float64(count*length)
+}
+
+// liveCovering says which option would win if the game ended now, or that the
+// line would push when the score lands exactly on it.
+func liveCovering(bet models.Bet, game liveGame) string {
+	scoreDiff := game.Score1 - game.Score2
+	totalScore := game.Score1 + game.Score2
+	winning := betService.ScoreResult(bet, scoreDiff, totalScore).WinningOption
+
+	switch {
+	case bet.Total != nil:
+		if !common.CalculateTotalEntryWin(1, totalScore, *bet.Total) && !common.CalculateTotalEntryWin(2, totalScore, *bet.Total) {
+			return fmt.Sprintf("**Push** with %d points scored", totalScore)
+		}
+		return fmt.Sprintf("**%s** is winning with %d points scored", common.GetOptionName(bet, winning), totalScore)
+	case bet.Spread != nil:
+		if !common.CalculateBetEntryWin(1, scoreDiff, *bet.Spread) && !common.CalculateBetEntryWin(2, scoreDiff, *bet.Spread) {
+			return "**Push**"
+		}
+		return fmt.Sprintf("**%s** is covering", common.GetOptionName(bet, winning))
+	case winning == 0:
+		return "Tied"
+	}
+	return fmt.Sprintf("**%s** is leading", common.GetOptionName(bet, winning))
+}
+
+// editClosedBetMessages edits a closed bet's message, and every copy posted to
+// other channels, to show embed.
+func editClosedBetMessages(s *discordgo.Session, db *gorm.DB, bet models.Bet, embed *discordgo.MessageEmbed) {
+	if bet.MessageID != nil {
+		_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *bet.MessageID,
+			Channel:    bet.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			log.Printf("Error updating live message for bet %d: %v\n", bet.ID, err)
+		}
+	}
+
+	// Copies are marked inactive when the bet closes, so every copy is edited.
+	var secondaryMsgs []models.BetMessage
+	if err := db.Where("bet_id = ?", bet.ID).Find(&secondaryMsgs).Error; err != nil {
+		log.Printf("Error finding secondary messages for bet %d: %v\n", bet.ID, err)
+		return
+	}
+	for _, msg := range secondaryMsgs {
+		if msg.MessageID == nil {
+			continue
+		}
+		_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *msg.MessageID,
+			Channel:    msg.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			log.Printf("Error updating live secondary message for bet %d: %v\n", bet.ID, err)
+		}
+	}
+}
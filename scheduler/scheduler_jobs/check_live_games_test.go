@@ -0,0 +1,150 @@
+package scheduler_jobs
+
+import (
+	"encoding/json"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"testing"
+)
+
+func TestCFBLiveGame(t *testing.T) {
+	homePoints, awayPoints := 21, 14
+	period := 3
+	clock := "00:05:32"
+	home := external.CFBD_ScoreboardTeam{Name: "Ohio State", Points: &homePoints}
+	away := external.CFBD_ScoreboardTeam{Name: "Michigan", Points: &awayPoints}
+
+	game := cfbLiveGame("in_progress", &period, &clock, home, away)
+	if !game.InProgress || game.Final {
+		t.Errorf("expected an in-progress game, got %+v", game)
+	}
+	if game.Score1 != 21 || game.Score2 != 14 {
+		t.Errorf("expected home score first, got %d-%d", game.Score1, game.Score2)
+	}
+	if game.Score != "Michigan 14 @ Ohio State 21" {
+		t.Errorf("unexpected score %q", game.Score)
+	}
+	if game.Status != "Q3 05:32" {
+		t.Errorf("unexpected status %q", game.Status)
+	}
+
+	final := cfbLiveGame("completed", &period, &clock, home, away)
+	if !final.Final || final.Status != "Final" {
+		t.Errorf("expected a final game, got %+v", final)
+	}
+}
+
+func TestESPNLiveGame(t *testing.T) {
+	var event external.ESPN_Event
+	body := `{"id":"401","status":{"period":2,"displayClock":"12:34","type":{"name":"STATUS_IN_PROGRESS","state":"in","shortDetail":"12:34 - 2nd Half"}},
+		"competitions":[{"competitors":[
+			{"homeAway":"home","score":"40","team":{"shortDisplayName":"Duke"}},
+			{"homeAway":"away","score":"44","team":{"shortDisplayName":"UNC"}}]}]}`
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+
+	// The bet lists the away team first, so its scores follow the options.
+	bet := models.Bet{Option1: "UNC", Option2: "Duke"}
+	game := espnLiveGame(bet, event)
+	if !game.InProgress || game.Final {
+		t.Errorf("expected an in-progress game, got %+v", game)
+	}
+	if game.Score1 != 44 || game.Score2 != 40 {
+		t.Errorf("expected option scores 44-40, got %d-%d", game.Score1, game.Score2)
+	}
+	if game.Score != "UNC 44 @ Duke 40" || game.Status != "12:34 - 2nd Half" {
+		t.Errorf("unexpected score line %q / %q", game.Score, game.Status)
+	}
+}
+
+func TestLiveCovering(t *testing.T) {
+	spread := -7.5
+	total := 48.5
+	wholeSpread := -7.0
+	wholeTotal := 48.0
+
+	tests := []struct {
+		name     string
+		bet      models.Bet
+		game     liveGame
+		expected string
+	}{
+		{
+			name:     "Favorite covering",
+			bet:      models.Bet{Option1: "Ohio State -7.5", Option2: "Michigan +7.5", Spread: &spread},
+			game:     liveGame{Score1: 21, Score2: 10},
+			expected: "**Ohio State -7.5** is covering",
+		},
+		{
+			name:     "Underdog covering while losing",
+			bet:      models.Bet{Option1: "Ohio State -7.5", Option2: "Michigan +7.5", Spread: &spread},
+			game:     liveGame{Score1: 21, Score2: 14},
+			expected: "**Michigan +7.5** is covering",
+		},
+		{
+			name:     "Total on pace for the over",
+			bet:      models.Bet{Option1: "Over 48.5", Option2: "Under 48.5", Total: &total},
+			game:     liveGame{Score1: 28, Score2: 24},
+			expected: "**Over 48.5** is winning with 52 points scored",
+		},
+		{
+			name:     "Spread on the number",
+			bet:      models.Bet{Option1: "Ohio State -7", Option2: "Michigan +7", Spread: &wholeSpread},
+			game:     liveGame{Score1: 21, Score2: 14},
+			expected: "**Push**",
+		},
+		{
+			name:     "Total on the number",
+			bet:      models.Bet{Option1: "Over 48", Option2: "Under 48", Total: &wholeTotal},
+			game:     liveGame{Score1: 24, Score2: 24},
+			expected: "**Push** with 48 points scored",
+		},
+		{
+			name:     "Moneyline tied",
+			bet:      models.Bet{Option1: "Ohio State", Option2: "Michigan"},
+			game:     liveGame{Score1: 7, Score2: 7},
+			expected: "Tied",
+		},
+		{
+			name:     "Moneyline leader",
+			bet:      models.Bet{Option1: "Ohio State", Option2: "Michigan"},
+			game:     liveGame{Score1: 3, Score2: 7},
+			expected: "**Michigan** is leading",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := liveCovering(tt.bet, tt.game); got != tt.expected {
+				t.Errorf("liveCovering() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGameRemaining(t *testing.T) {
+	tests := []struct {
+		name     string
+		sport    string
+		period   int
+		clock    string
+		expected float64
+	}{
+		{"Kickoff", models.SportNFL, 1, "15:00", 1},
+		{"Halftime", models.SportCFB, 2, "00:00:00", 0.5},
+		{"CFBD clock", models.SportCFB, 3, "00:07:30", 0.375},
+		{"College basketball halves", models.SportCBB, 2, "10:00", 0.25},
+		{"NBA quarters", models.SportNBA, 4, "6:00", 0.125},
+		{"Overtime", models.SportNBA, 5, "2:00", overtimeRemaining},
+		{"No clock", models.SportNFL, 2, "", 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gameRemaining(tt.sport, tt.period, tt.clock); got != tt.expected {
+				t.Errorf("expected %v of the game left, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package scheduler_jobs
+
+import (
+	"fmt"
+	"log"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/betService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/teamService"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// subscriptionGame is the part of a scheduled game a subscription matches on.
+// Teams are named as the team registry names them.
+type subscriptionGame struct {
+	ID        string
+	StartTime time.Time
+	HomeTeam  string
+	AwayTeam  string
+	HomeConf  string
+	AwayConf  string
+	// BothRanked is set when both teams are ranked.
+	BothRanked bool
+}
+
+// CheckSubscriptions auto-creates bets for every upcoming game of the given
+// sports that matches one of a guild's subscriptions, once the game is inside
+// the subscription's posting window.
+func CheckSubscriptions(s *discordgo.Session, db *gorm.DB, sports []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered in CheckSubscriptions", r)
+			debug.PrintStack()
+			err = fmt.Errorf("panic recovered in CheckSubscriptions: %v", r)
+		}
+	}()
+
+	var subscriptions []models.GuildSubscription
+	if err := db.Where("sport IN ?", sports).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	bySport := make(map[string][]models.GuildSubscription)
+	for _, sub := range subscriptions {
+		bySport[sub.Sport] = append(bySport[sub.Sport], sub)
+	}
+
+	guilds := make(map[string]*models.Guild)
+	resolvers := make(map[string]*teamService.Resolver)
+	now := time.Now().UTC()
+
+	for sportKey, sportSubs := range bySport {
+		games, err := subscriptionGames(sportKey)
+		if err != nil {
+			log.Printf("Error fetching %s games for subscriptions: %v\n", sportKey, err)
+			continue
+		}
+
+		for _, sub := range sportSubs {
+			guild, found := guilds[sub.GuildID]
+			if !found {
+				guild, err = guildService.GetGuildInfo(s, db, sub.GuildID, "")
+				if err != nil {
+					log.Printf("Error loading guild %s for subscription %d: %v\n", sub.GuildID, sub.ID, err)
+					continue
+				}
+				guilds[sub.GuildID] = guild
+			}
+			if !guild.PremiumEnabled {
+				continue
+			}
+
+			channelID := sub.ChannelID
+			if channelID == "" {
+				channelID = guild.BetChannelID
+			}
+			if channelID == "" {
+				continue
+			}
+
+			loc := common.GuildLocation(*guild)
+			teams, found := resolvers[sub.GuildID]
+			if !found {
+				teams = teamService.ForGuild(db, sub.GuildID)
+				resolvers[sub.GuildID] = teams
+			}
+			for _, game := range games {
+				if !subscriptionMatches(teams, sub, game) || !subscriptionDue(sub, game, now, loc) {
+					continue
+				}
+
+				if sportKey == models.SportCFB {
+					err = betService.AutoCreateCFBBet(s, db, sub.GuildID, channelID, game.ID, sub.BetType)
+				} else {
+					sport, sportErr := extService.ESPNSport(sportKey)
+					if sportErr != nil {
+						continue
+					}
+					err = betService.AutoCreateESPNBet(s, db, sport, sub.GuildID, channelID, game.ID, sub.BetType)
+				}
+				if err != nil {
+					log.Printf("Error creating bet on game %s for subscription %d: %v\n", game.ID, sub.ID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// subscriptionMatches reports whether a game falls under a subscription, with
+// team names compared through the guild's team resolver.
+func subscriptionMatches(teams *teamService.Resolver, sub models.GuildSubscription, game subscriptionGame) bool {
+	switch sub.Kind {
+	case models.SubscriptionTeam:
+		return teams.Same(game.HomeTeam, sub.Target) || teams.Same(game.AwayTeam, sub.Target)
+	case models.SubscriptionConference:
+		return game.HomeConf == sub.Target || game.AwayConf == sub.Target
+	case models.SubscriptionRanked:
+		return game.BothRanked
+	}
+	return false
+}
+
+// subscriptionDue reports whether an upcoming game is inside a subscription's
+// posting window, with game days counted in the guild's timezone loc.
+func subscriptionDue(sub models.GuildSubscription, game subscriptionGame, now time.Time, loc *time.Location) bool {
+	if !game.StartTime.After(now) {
+		return false
+	}
+	switch {
+	case sub.HoursBefore == models.HoursBeforeGameDay:
+		local := game.StartTime.In(loc)
+		postAt := time.Date(local.Year(), local.Month(), local.Day(), models.GameDayPostHour, 0, 0, 0, loc)
+		return !now.Before(postAt)
+	case sub.HoursBefore > 0:
+		return !game.StartTime.After(now.Add(time.Duration(sub.HoursBefore) * time.Hour))
+	}
+	return true
+}
+
+// subscriptionGames returns the upcoming games for a sport with what
+// subscriptions match on.
+func subscriptionGames(sportKey string) ([]subscriptionGame, error) {
+	if sportKey == models.SportCFB {
+		return cfbSubscriptionGames()
+	}
+
+	sport, err := extService.ESPNSport(sportKey)
+	if err != nil {
+		return nil, err
+	}
+	events, err := extService.GetESPNGames(sport)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := teamService.Shared()
+
+	var games []subscriptionGame
+	for _, event := range events {
+		if event.Status.Type.Name == "STATUS_FINAL" || len(event.Competitions) == 0 {
+			continue
+		}
+		startTime, err := betService.ParseESPNGameStartTime(event.Date)
+		if err != nil {
+			continue
+		}
+		game := subscriptionGame{ID: event.ID, StartTime: startTime, BothRanked: true}
+		for _, competitor := range event.Competitions[0].Competitors {
+			name := teams.ESPN(sportKey, competitor.Team)
+			rank := competitor.CuratedRank.Current
+			if rank < 1 || rank > 25 {
+				game.BothRanked = false
+			}
+			if competitor.HomeAway == "home" {
+				game.HomeTeam = name
+				game.HomeConf = teams.Conference(name)
+			} else {
+				game.AwayTeam = name
+				game.AwayConf = teams.Conference(name)
+			}
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+func cfbSubscriptionGames() ([]subscriptionGame, error) {
+	lines, err := extService.GetCFBGames()
+	if err != nil {
+		return nil, err
+	}
+
+	ranked, err := extService.GetCFBRankedTeams()
+	if err != nil {
+		log.Printf("Error fetching CFB rankings: %v\n", err)
+		ranked = map[string]bool{}
+	}
+
+	var games []subscriptionGame
+	for _, line := range lines {
+		if line.HomeScore != nil && line.AwayScore != nil {
+			continue
+		}
+		games = append(games, cfbSubscriptionGame(teamService.Shared(), line, ranked))
+	}
+	return games, nil
+}
+
+func cfbSubscriptionGame(teams *teamService.Resolver, line external.CFBD_BettingLines, ranked map[string]bool) subscriptionGame {
+	return subscriptionGame{
+		ID:         strconv.Itoa(line.ID),
+		StartTime:  line.StartDate,
+		HomeTeam:   teams.CFBD(line.HomeTeamID, line.HomeTeam),
+		AwayTeam:   teams.CFBD(line.AwayTeamID, line.AwayTeam),
+		HomeConf:   line.HomeConference,
+		AwayConf:   line.AwayConference,
+		BothRanked: ranked[line.HomeTeam] && ranked[line.AwayTeam],
+	}
+}
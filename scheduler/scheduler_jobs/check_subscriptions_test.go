@@ -0,0 +1,85 @@
+package scheduler_jobs
+
+import (
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/teamService"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionMatches(t *testing.T) {
+	game := subscriptionGame{
+		HomeTeam: "Michigan",
+		AwayTeam: "Ohio State",
+		HomeConf: "Big Ten",
+		AwayConf: "Big Ten",
+	}
+	rankedGame := game
+	rankedGame.BothRanked = true
+
+	tests := []struct {
+		name     string
+		sub      models.GuildSubscription
+		game     subscriptionGame
+		expected bool
+	}{
+		{"Team plays at home", models.GuildSubscription{Kind: models.SubscriptionTeam, Target: "Michigan"}, game, true},
+		{"Team plays away", models.GuildSubscription{Kind: models.SubscriptionTeam, Target: "Ohio State"}, game, true},
+		{"Team spelled differently", models.GuildSubscription{Kind: models.SubscriptionTeam, Target: "michigan"}, game, true},
+		{"Team not playing", models.GuildSubscription{Kind: models.SubscriptionTeam, Target: "Alabama"}, game, false},
+		{"Conference team playing", models.GuildSubscription{Kind: models.SubscriptionConference, Target: "Big Ten"}, game, true},
+		{"Conference not playing", models.GuildSubscription{Kind: models.SubscriptionConference, Target: "SEC"}, game, false},
+		{"Ranked matchup", models.GuildSubscription{Kind: models.SubscriptionRanked}, rankedGame, true},
+		{"Unranked matchup", models.GuildSubscription{Kind: models.SubscriptionRanked}, game, false},
+		{"Unknown kind", models.GuildSubscription{Kind: "player", Target: "Michigan"}, game, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subscriptionMatches(teamService.Shared(), tt.sub, tt.game); got != tt.expected {
+				t.Errorf("subscriptionMatches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubscriptionDue(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	// A 7pm Central kickoff.
+	game := subscriptionGame{StartTime: time.Date(2027, 10, 2, 19, 0, 0, 0, chicago)}
+
+	tests := []struct {
+		name     string
+		hours    int
+		now      time.Time
+		expected bool
+	}{
+		{"Posts as soon as lines are up", 0, time.Date(2027, 9, 28, 12, 0, 0, 0, chicago), true},
+		{"Outside the hours window", 6, time.Date(2027, 10, 2, 12, 0, 0, 0, chicago), false},
+		{"Inside the hours window", 6, time.Date(2027, 10, 2, 13, 30, 0, 0, chicago), true},
+		{"Game day before 9am local", models.HoursBeforeGameDay, time.Date(2027, 10, 2, 8, 59, 0, 0, chicago), false},
+		{"Game day at 9am local", models.HoursBeforeGameDay, time.Date(2027, 10, 2, 9, 0, 0, 0, chicago), true},
+		{"Day before the game", models.HoursBeforeGameDay, time.Date(2027, 10, 1, 21, 0, 0, 0, chicago), false},
+		{"Game already started", models.HoursBeforeGameDay, time.Date(2027, 10, 2, 19, 30, 0, 0, chicago), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := models.GuildSubscription{HoursBefore: tt.hours}
+			if got := subscriptionDue(sub, game, tt.now.UTC(), chicago); got != tt.expected {
+				t.Errorf("subscriptionDue() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Game day at 9am local when clocks spring forward", func(t *testing.T) {
+		dstGame := subscriptionGame{StartTime: time.Date(2027, 3, 14, 19, 0, 0, 0, chicago)}
+		sub := models.GuildSubscription{HoursBefore: models.HoursBeforeGameDay}
+		if !subscriptionDue(sub, dstGame, time.Date(2027, 3, 14, 9, 0, 0, 0, chicago).UTC(), chicago) {
+			t.Errorf("expected the game day post to be due at 9am local")
+		}
+	})
+}
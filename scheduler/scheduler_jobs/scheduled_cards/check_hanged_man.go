@@ -5,6 +5,7 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -51,13 +52,12 @@ func CheckHangedMan(s *discordgo.Session, db *gorm.DB) error {
 				gainAmount = guild.Pool
 			}
 
-			user.Points += gainAmount
 			guild.Pool -= gainAmount
 			if guild.Pool < 0 {
 				guild.Pool = 0
 			}
 
-			if err := tx.Save(&user).Error; err != nil {
+			if err := ledgerService.Apply(tx, &user, gainAmount, ledgerService.Job("hanged_man", cards.TheHangedManCardID)); err != nil {
 				return err
 			}
 
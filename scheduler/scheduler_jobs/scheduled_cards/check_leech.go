@@ -5,6 +5,7 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -72,15 +73,10 @@ func CheckLeech(s *discordgo.Session, db *gorm.DB) error {
 				if actualSiphon <= 0 {
 					return nil
 				}
-				randomUser.Points -= actualSiphon
-				if randomUser.Points < 0 {
-					randomUser.Points = 0
-				}
-				if err := tx.Save(&randomUser).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &randomUser, -actualSiphon, ledgerService.Job("leech", cards.LeechCardID)); err != nil {
 					return err
 				}
-				leechHolder.Points += actualSiphon
-				if err := tx.Save(&leechHolder).Error; err != nil {
+				if err := ledgerService.Apply(tx, &leechHolder, actualSiphon, ledgerService.Job("leech", cards.LeechCardID)); err != nil {
 					return err
 				}
 				return nil
@@ -94,16 +90,11 @@ func CheckLeech(s *discordgo.Session, db *gorm.DB) error {
 				return nil
 			}
 
-			richestPlayer.Points -= siphonAmount
-			if richestPlayer.Points < 0 {
-				richestPlayer.Points = 0
-			}
-			if err := tx.Save(&richestPlayer).Error; err != nil {
+			if _, err := ledgerService.ApplyFloored(tx, &richestPlayer, -siphonAmount, ledgerService.Job("leech", cards.LeechCardID)); err != nil {
 				return err
 			}
 
-			leechHolder.Points += siphonAmount
-			if err := tx.Save(&leechHolder).Error; err != nil {
+			if err := ledgerService.Apply(tx, &leechHolder, siphonAmount, ledgerService.Job("leech", cards.LeechCardID)); err != nil {
 				return err
 			}
 
@@ -5,6 +5,7 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -41,12 +42,7 @@ func CheckLoanShark(s *discordgo.Session, db *gorm.DB) error {
 				deduction = user.Points
 			}
 
-			user.Points -= deduction
-			if user.Points < 0 {
-				user.Points = 0
-			}
-
-			if err := tx.Save(&user).Error; err != nil {
+			if _, err := ledgerService.ApplyFloored(tx, &user, -deduction, ledgerService.Job("loan_shark", cards.LoanSharkCardID)); err != nil {
 				return err
 			}
 
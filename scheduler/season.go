@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"perfectOddsBot/scheduler/scheduler_jobs"
+	"perfectOddsBot/services/extService"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// seasonRefreshInterval is how long a sport's season calendar is trusted before
+// it is fetched again.
+const seasonRefreshInterval = 6 * time.Hour
+
+// seasonJob is a sports job that polls at a different rate in each phase of a
+// sport's season. A phase missing from Intervals leaves the job off for it.
+type seasonJob struct {
+	Name      string
+	Intervals map[extService.SeasonPhase]time.Duration
+	// Offset staggers the job within its interval so jobs don't all run on
+	// the hour.
+	Offset time.Duration
+	Run    func(s *discordgo.Session, db *gorm.DB, sports []string) error
+}
+
+var seasonJobs = []*seasonJob{
+	{
+		Name: "Lock bets at game start",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhasePreseason:  5 * time.Minute,
+			extService.PhaseRegular:    5 * time.Minute,
+			extService.PhasePostseason: 5 * time.Minute,
+			extService.PhaseOffseason:  time.Hour,
+		},
+		Run: scheduler_jobs.CheckGameStart,
+	},
+	{
+		Name: "Pay out finished games",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhasePreseason:  time.Hour,
+			extService.PhaseRegular:    time.Hour,
+			extService.PhasePostseason: 30 * time.Minute,
+			extService.PhaseOffseason:  24 * time.Hour,
+		},
+		Run: scheduler_jobs.CheckGameEnd,
+	},
+	{
+		Name: "Track live games",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhasePreseason:  2 * time.Minute,
+			extService.PhaseRegular:    2 * time.Minute,
+			extService.PhasePostseason: 2 * time.Minute,
+		},
+		Run: scheduler_jobs.CheckLiveGames,
+	},
+	{
+		Name: "Refresh lines",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhasePreseason:  6 * time.Hour,
+			extService.PhaseRegular:    time.Hour,
+			extService.PhasePostseason: 30 * time.Minute,
+		},
+		Offset: 30 * time.Minute,
+		Run:    scheduler_jobs.CheckLines,
+	},
+	{
+		Name: "Post subscribed games",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhasePreseason:  6 * time.Hour,
+			extService.PhaseRegular:    time.Hour,
+			extService.PhasePostseason: time.Hour,
+		},
+		Offset: 15 * time.Minute,
+		Run:    scheduler_jobs.CheckSubscriptions,
+	},
+}
+
+// seasonSchedule decides which season jobs are due for which sports.
+type seasonSchedule struct {
+	mu        sync.Mutex
+	seasons   map[string]extService.Season
+	refreshed time.Time
+	// lastSlot is the interval slot each job last ran in, per sport.
+	lastSlot map[string]time.Time
+	lastRun  map[string]time.Time
+	running  map[string]*sync.Mutex
+}
+
+var schedule = newSeasonSchedule()
+
+func newSeasonSchedule() *seasonSchedule {
+	return &seasonSchedule{
+		seasons:  make(map[string]extService.Season),
+		lastSlot: make(map[string]time.Time),
+		lastRun:  make(map[string]time.Time),
+		running:  make(map[string]*sync.Mutex),
+	}
+}
+
+// refreshSeasons fetches every sport's season calendar once it is stale. A sport
+// whose calendar can't be fetched keeps its last known season, or is treated as
+// in its regular season so its bets keep settling. The calendars are fetched
+// without holding sc.mu, so jobs keep being scheduled while the fetch is slow.
+func (sc *seasonSchedule) refreshSeasons(now time.Time, fetch func(sport string, now time.Time) (extService.Season, error)) {
+	sc.mu.Lock()
+	if !sc.refreshed.IsZero() && now.Sub(sc.refreshed) < seasonRefreshInterval {
+		sc.mu.Unlock()
+		return
+	}
+	sc.refreshed = now
+	sc.mu.Unlock()
+
+	fetched := make(map[string]extService.Season)
+	for _, sport := range extService.SeasonSports {
+		season, err := fetch(sport, now)
+		if err != nil {
+			log.Printf("Error fetching %s season calendar: %v\n", sport, err)
+			continue
+		}
+		fetched[sport] = season
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for _, sport := range extService.SeasonSports {
+		season, found := fetched[sport]
+		if !found {
+			if _, known := sc.seasons[sport]; known {
+				continue
+			}
+			season = extService.Season{
+				Sport:  sport,
+				Name:   sport,
+				Phase:  extService.PhaseRegular,
+				Reason: "The season calendar couldn't be fetched, so the regular season schedule is used",
+			}
+		}
+		sc.seasons[sport] = season
+	}
+}
+
+// due returns the sports a job should run for now, and marks it as run for them.
+func (sc *seasonSchedule) due(job *seasonJob, now time.Time) []string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var sports []string
+	for _, sport := range extService.SeasonSports {
+		interval := job.Intervals[sc.seasons[sport].Phase]
+		if interval == 0 {
+			continue
+		}
+		key := job.Name + ":" + sport
+		slot := now.Add(-job.Offset).Truncate(interval)
+		if !slot.After(sc.lastSlot[key]) {
+			continue
+		}
+		sc.lastSlot[key] = slot
+		sc.lastRun[key] = now
+		sports = append(sports, sport)
+	}
+	return sports
+}
+
+// tick runs every season job that is due. Each job runs on its own goroutine
+// and is skipped while a previous run is still going.
+func (sc *seasonSchedule) tick(s *discordgo.Session, db *gorm.DB, now time.Time) {
+	sc.refreshSeasons(now, extService.GetSeason)
+
+	for _, job := range seasonJobs {
+		sc.mu.Lock()
+		running, found := sc.running[job.Name]
+		if !found {
+			running = &sync.Mutex{}
+			sc.running[job.Name] = running
+		}
+		sc.mu.Unlock()
+
+		if !running.TryLock() {
+			log.Printf("Skipping %q, the previous run is still going\n", job.Name)
+			continue
+		}
+		sports := sc.due(job, now)
+		if len(sports) == 0 {
+			running.Unlock()
+			continue
+		}
+
+		go func(job *seasonJob, sports []string) {
+			defer running.Unlock()
+			err := job.Run(s, db, sports)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}(job, sports)
+	}
+}
+
+// JobStatus is how often a season job runs for a sport right now.
+type JobStatus struct {
+	Name string
+	// Interval is zero when the job is off for the sport's current phase.
+	Interval time.Duration
+	LastRun  time.Time
+}
+
+// SportStatus is a sport's season and the jobs it drives.
+type SportStatus struct {
+	Season extService.Season
+	Jobs   []JobStatus
+}
+
+// Status returns every sport's season and which jobs are running for it.
+func Status(now time.Time) []SportStatus {
+	schedule.refreshSeasons(now, extService.GetSeason)
+
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	var statuses []SportStatus
+	for _, sport := range extService.SeasonSports {
+		season := schedule.seasons[sport]
+		status := SportStatus{Season: season}
+		for _, job := range seasonJobs {
+			status.Jobs = append(status.Jobs, JobStatus{
+				Name:     job.Name,
+				Interval: job.Intervals[season.Phase],
+				LastRun:  schedule.lastRun[job.Name+":"+sport],
+			})
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
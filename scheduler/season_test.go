@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"errors"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/extService"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeasonScheduleDue(t *testing.T) {
+	phases := map[string]extService.SeasonPhase{
+		models.SportCFB: extService.PhasePostseason,
+		models.SportCBB: extService.PhaseRegular,
+		models.SportNFL: extService.PhaseRegular,
+		models.SportNBA: extService.PhaseOffseason,
+	}
+	fetch := func(sport string, now time.Time) (extService.Season, error) {
+		return extService.Season{Sport: sport, Phase: phases[sport]}, nil
+	}
+	job := &seasonJob{
+		Name: "test",
+		Intervals: map[extService.SeasonPhase]time.Duration{
+			extService.PhaseRegular:    time.Hour,
+			extService.PhasePostseason: 30 * time.Minute,
+		},
+		Offset: 15 * time.Minute,
+	}
+
+	sc := newSeasonSchedule()
+	start := time.Date(2027, 1, 1, 12, 15, 0, 0, time.UTC)
+	sc.refreshSeasons(start, fetch)
+
+	steps := []struct {
+		at   time.Duration
+		want []string
+	}{
+		{0, []string{models.SportCFB, models.SportCBB, models.SportNFL}},
+		{5 * time.Minute, nil},
+		{30 * time.Minute, []string{models.SportCFB}},
+		{time.Hour, []string{models.SportCFB, models.SportCBB, models.SportNFL}},
+	}
+	for _, step := range steps {
+		got := sc.due(job, start.Add(step.at))
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("due at +%s = %v, want %v", step.at, got, step.want)
+		}
+	}
+}
+
+func TestSeasonScheduleCalendarUnavailable(t *testing.T) {
+	now := time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)
+	failing := func(sport string, now time.Time) (extService.Season, error) {
+		return extService.Season{}, errors.New("calendar down")
+	}
+
+	sc := newSeasonSchedule()
+	sc.refreshSeasons(now, failing)
+	if phase := sc.seasons[models.SportCFB].Phase; phase != extService.PhaseRegular {
+		t.Errorf("expected an unknown season to poll as regular season, got %s", phase)
+	}
+
+	sc.refreshSeasons(now.Add(seasonRefreshInterval), func(sport string, now time.Time) (extService.Season, error) {
+		return extService.Season{Sport: sport, Phase: extService.PhaseOffseason}, nil
+	})
+	sc.refreshSeasons(now.Add(2*seasonRefreshInterval), failing)
+	if phase := sc.seasons[models.SportCFB].Phase; phase != extService.PhaseOffseason {
+		t.Errorf("expected the last known season to be kept, got %s", phase)
+	}
+}
+
+func TestSeasonScheduleRefreshDoesNotBlockJobs(t *testing.T) {
+	now := time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &seasonJob{Name: "test", Intervals: map[extService.SeasonPhase]time.Duration{extService.PhaseRegular: time.Hour}}
+
+	sc := newSeasonSchedule()
+	sc.refreshSeasons(now, func(sport string, now time.Time) (extService.Season, error) {
+		return extService.Season{Sport: sport, Phase: extService.PhaseRegular}, nil
+	})
+
+	slow := func(sport string, now time.Time) (extService.Season, error) {
+		scheduled := make(chan struct{})
+		go func() {
+			sc.due(job, now)
+			close(scheduled)
+		}()
+		select {
+		case <-scheduled:
+		case <-time.After(time.Second):
+			t.Error("expected jobs to be scheduled while the calendar is fetched")
+		}
+		return extService.Season{Sport: sport, Phase: extService.PhaseRegular}, nil
+	}
+	sc.refreshSeasons(now.Add(seasonRefreshInterval), slow)
+}
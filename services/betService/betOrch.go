@@ -1,11 +1,13 @@
 package betService
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"perfectOddsBot/models"
-	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/messageService"
@@ -14,6 +16,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// MaxCustomBetOptions is the number of option/odds pairs accepted by /create-bet.
+const MaxCustomBetOptions = 6
+
 func CreateCustomBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
 	if !common.IsAdmin(s, i) {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -30,62 +35,109 @@ func CreateCustomBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *g
 		return
 	}
 
-	options := i.ApplicationCommandData().Options
-	description := options[0].StringValue()
-	option1 := options[1].StringValue()
-	option2 := options[2].StringValue()
-	odds1 := -110
-	odds2 := -110
-	if len(options) > 3 {
-		if options[3] != nil {
-			odds1 = int(options[3].IntValue())
+	commandOptions := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		commandOptions[opt.Name] = opt
+	}
+
+	description := commandOptions["description"].StringValue()
+
+	var betOptions []models.BetOption
+	for n := 1; n <= MaxCustomBetOptions; n++ {
+		nameOpt, ok := commandOptions[fmt.Sprintf("option%d", n)]
+		if !ok || strings.TrimSpace(nameOpt.StringValue()) == "" {
+			continue
 		}
-		if len(options) > 4 && options[4] != nil {
-			odds2 = int(options[4].IntValue())
+
+		odds := -110
+		if oddsOpt, ok := commandOptions[fmt.Sprintf("odds%d", n)]; ok {
+			odds = int(oddsOpt.IntValue())
 		}
+
+		betOptions = append(betOptions, models.BetOption{
+			OptionNumber: len(betOptions) + 1,
+			Name:         strings.TrimSpace(nameOpt.StringValue()),
+			Odds:         odds,
+		})
 	}
+
+	if len(betOptions) < 2 {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "A bet needs at least two options.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
 	guildID := i.GuildID
 
+	var lockAt *time.Time
+	if lockOpt, ok := commandOptions["lock"]; ok && strings.TrimSpace(lockOpt.StringValue()) != "" {
+		guild, err := guildService.GetGuildInfo(s, db, guildID, i.ChannelID)
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		parsed, err := common.ParseLockTime(lockOpt.StringValue(), time.Now(), common.GuildLocation(*guild))
+		if err != nil {
+			err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: fmt.Sprintf("Invalid lock time: %v.", err),
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			})
+			if err != nil {
+				common.SendError(s, i, err, db)
+			}
+			return
+		}
+		lockAt = &parsed
+	}
+
 	bet := models.Bet{
 		Description:  description,
-		Option1:      option1,
-		Option2:      option2,
-		Odds1:        odds1,
-		Odds2:        odds2,
+		Option1:      betOptions[0].Name,
+		Option2:      betOptions[1].Name,
+		Odds1:        betOptions[0].Odds,
+		Odds2:        betOptions[1].Odds,
 		Active:       true,
 		GuildID:      guildID,
 		ChannelID:    i.ChannelID,
 		AdminCreated: true,
+		Options:      betOptions,
+		LockAt:       lockAt,
 	}
 	db.Create(&bet)
 
+	var fields []*discordgo.MessageEmbedField
+	for _, option := range bet.Options {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s", common.GetOptionEmoji(option.OptionNumber), option.Name),
+			Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(option.Odds))),
+		})
+	}
+
 	embed := &discordgo.MessageEmbed{
 		Title:       fmt.Sprint("📢 New Bet Created"),
-		Description: description,
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  fmt.Sprintf("1️⃣ %s", option1),
-				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(odds1))),
-			},
-			{
-				Name:  fmt.Sprintf("2️⃣ %s", option2),
-				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(odds2))),
-			},
-		},
-		Color: 0x3498db,
+		Description: common.CustomBetDescription(bet),
+		Fields:      fields,
+		Color:       0x3498db,
 	}
 
-	buttons := messageService.GetAllButtonList(s, i, option1, option2, bet.ID)
+	components := messageService.GetAllButtonList(s, i, bet.Options, bet.ID)
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
-			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: buttons,
-				},
-			},
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
 		},
 	})
 
@@ -105,430 +157,29 @@ func CreateCustomBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *g
 
 func ResolveBetByID(s *discordgo.Session, i *discordgo.InteractionCreate, betID int, winningOption int, db *gorm.DB) {
 	var bet models.Bet
-	winnersList := ""
-	loserList := ""
 	result := db.First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID)
-	if result.Error != nil || bet.ID == 0 {
-		response := "Bet not found or already resolved."
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: response,
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		if err != nil {
-			common.SendError(s, i, err, db)
-			return
-		}
+	if result.Error != nil || bet.ID == 0 || bet.Paid || bet.Voided {
+		respondBetAlreadyResolved(s, i, db)
 		return
 	}
 
-	guild, err := guildService.GetGuildInfo(s, db, bet.GuildID, bet.ChannelID)
-	if err != nil {
-		common.SendError(s, i, err, db)
+	settlement, err := SettleBet(s, db, bet.ID, ManualResult(winningOption))
+	if errors.Is(err, ErrBetAlreadySettled) {
+		respondBetAlreadyResolved(s, i, db)
 		return
 	}
-
-	var entries []models.BetEntry
-	db.Where("bet_id = ? AND deleted_at IS NULL", bet.ID).Find(&entries)
-
-	totalPayout := 0.0
-	totalWinningPayouts := 0.0
-	lostPoolAmount := 0.0
-	winnerDiscordIDs := make(map[string]float64)
-	for _, entry := range entries {
-		var user models.User
-		db.First(&user, "id = ?", entry.UserID)
-		if user.ID == 0 {
-			continue
-		}
-
-		if entry.Option == winningOption {
-			payout := common.CalculatePayout(entry.Amount, winningOption, bet)
-
-			unoApplied, isWinAfterUno, err := cardService.ApplyUnoReverseIfApplicable(db, user, bet.ID, true)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Uno Reverse: %v", err), db)
-				return
-			}
-
-			if unoApplied && !isWinAfterUno {
-				user.TotalBetsLost++
-				user.TotalPointsLost += float64(entry.Amount)
-				db.Save(&user)
-				lostPoolAmount += float64(entry.Amount)
-				continue
-			}
-
-			_, _, antiAntiBetLosers, antiAntiBetApplied, err := cardService.ApplyAntiAntiBetIfApplicable(db, user, true)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Anti-Anti-Bet: %v", err), db)
-				return
-			}
-			if antiAntiBetApplied {
-				if len(antiAntiBetLosers) > 0 {
-					for _, loser := range antiAntiBetLosers {
-						cardHolderUsername := common.GetUsernameWithDB(db, s, user.GuildID, loser.DiscordID)
-						loserList += fmt.Sprintf("%s - **Lost $%.1f** (Anti-Anti-Bet!)\n", cardHolderUsername, loser.Payout)
-					}
-				}
-			}
-
-			consumer := func(db *gorm.DB, user models.User, cardID uint) error {
-				return cardService.PlayCardFromInventory(s, db, user, cardID)
-			}
-
-			modifiedPayout, hasDoubleDown, err := cardService.ApplyDoubleDownIfAvailable(db, consumer, user, payout)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Double Down: %v", err), db)
-				return
-			}
-
-			payoutAfterDoubleDown := modifiedPayout
-
-			modifiedPayout, hasGambler, err := cardService.ApplyGamblerIfAvailable(db, consumer, user, modifiedPayout, true)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Gambler: %v", err), db)
-				return
-			}
-
-			modifiedPayout, hasHomeFieldAdvantage, err := cardService.ApplyHomeFieldAdvantageIfApplicable(db, user, modifiedPayout)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Home Field Advantage: %v", err), db)
-				return
-			}
-
-			modifiedPayout, hasRoughingTheKicker, err := cardService.ApplyRoughingTheKickerIfApplicable(db, user, modifiedPayout)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Roughing the Kicker: %v", err), db)
-				return
-			}
-
-			modifiedPayout, hasHeismanCampaign, err := cardService.ApplyHeismanCampaignIfApplicable(db, user, modifiedPayout)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Heisman Campaign: %v", err), db)
-				return
-			}
-
-			_, insuranceApplied, err := cardService.ApplyBetInsuranceIfApplicable(db, consumer, user, 0, true)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Bet Insurance: %v", err), db)
-				return
-			}
-
-			user.Points += modifiedPayout
-			user.TotalBetsWon++
-			user.TotalPointsWon += modifiedPayout
-			db.Save(&user)
-			totalPayout += modifiedPayout
-			totalWinningPayouts += modifiedPayout
-			winnerDiscordIDs[user.DiscordID] += modifiedPayout
-
-			if modifiedPayout > 0 {
-				username := common.GetUsernameWithDB(db, s, user.GuildID, user.DiscordID)
-				doubleDownMsg := ""
-				if hasDoubleDown {
-					doubleDownMsg = " (Double Down: 2x payout!)"
-				}
-				gamblerMsg := ""
-				if hasGambler {
-					if modifiedPayout > payoutAfterDoubleDown {
-						gamblerMsg = " (The Gambler: 2x payout!)"
-					} else {
-						gamblerMsg = " (The Gambler: consumed, no double)"
-					}
-				}
-				hfaMsg := ""
-				if hasHomeFieldAdvantage {
-					hfaMsg = " (Home Field Advantage: +15!)"
-				}
-				rtkMsg := ""
-				if hasRoughingTheKicker {
-					rtkMsg = " (Roughing the Kicker: -15% payout)"
-				}
-				heismanMsg := ""
-				if hasHeismanCampaign {
-					heismanMsg = " (Heisman Campaign: -15% payout)"
-				}
-				insuranceMsg := ""
-				if insuranceApplied {
-					insuranceMsg = " (Bet Insurance: consumed)"
-				}
-				winnersList += fmt.Sprintf("%s - Won $%.1f%s%s%s%s%s%s\n", username, modifiedPayout, doubleDownMsg, gamblerMsg, hfaMsg, rtkMsg, heismanMsg, insuranceMsg)
-			}
-		} else {
-			unoApplied, isWinAfterUno, err := cardService.ApplyUnoReverseIfApplicable(db, user, bet.ID, false)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Uno Reverse: %v", err), db)
-				return
-			}
-
-			if unoApplied && isWinAfterUno {
-				payout := common.CalculatePayout(entry.Amount, entry.Option, bet)
-
-				consumer := func(db *gorm.DB, user models.User, cardID uint) error {
-					return cardService.PlayCardFromInventory(s, db, user, cardID)
-				}
-
-				modifiedPayout, hasDoubleDown, err := cardService.ApplyDoubleDownIfAvailable(db, consumer, user, payout)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Double Down: %v", err), db)
-					return
-				}
-
-				payoutAfterDoubleDown := modifiedPayout
-
-				modifiedPayout, hasGambler, err := cardService.ApplyGamblerIfAvailable(db, consumer, user, modifiedPayout, true)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Gambler: %v", err), db)
-					return
-				}
-
-				modifiedPayout, hasHomeFieldAdvantage, err := cardService.ApplyHomeFieldAdvantageIfApplicable(db, user, modifiedPayout)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Home Field Advantage: %v", err), db)
-					return
-				}
-
-				modifiedPayout, hasRoughingTheKicker, err := cardService.ApplyRoughingTheKickerIfApplicable(db, user, modifiedPayout)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Roughing the Kicker: %v", err), db)
-					return
-				}
-
-				modifiedPayout, hasHeismanCampaign, err := cardService.ApplyHeismanCampaignIfApplicable(db, user, modifiedPayout)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Heisman Campaign: %v", err), db)
-					return
-				}
-
-				hedgeRefund, hedgeApplied, err := cardService.ApplyEmotionalHedgeIfApplicable(db, consumer, user, bet, entry.Option, float64(entry.Amount), 0)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Emotional Hedge: %v", err), db)
-					return
-				}
-
-				_, insuranceApplied, err := cardService.ApplyBetInsuranceIfApplicable(db, consumer, user, 0, true)
-				if err != nil {
-					common.SendError(s, i, fmt.Errorf("error checking Bet Insurance: %v", err), db)
-					return
-				}
-
-				user.Points += modifiedPayout
-				user.TotalBetsWon++
-				user.TotalPointsWon += modifiedPayout
-
-				if hedgeApplied && hedgeRefund > 0 {
-					user.Points += hedgeRefund
-				}
-
-				db.Save(&user)
-				totalPayout += modifiedPayout + hedgeRefund
-				totalWinningPayouts += modifiedPayout + hedgeRefund
-				winnerDiscordIDs[user.DiscordID] += modifiedPayout + hedgeRefund
-
-				username := common.GetUsernameWithDB(db, s, user.GuildID, user.DiscordID)
-
-				doubleDownMsg := ""
-				if hasDoubleDown {
-					doubleDownMsg = " (Double Down: 2x payout!)"
-				}
-				gamblerMsg := ""
-				if hasGambler {
-					if modifiedPayout > payoutAfterDoubleDown {
-						gamblerMsg = " (The Gambler: 2x payout!)"
-					} else {
-						gamblerMsg = " (The Gambler: consumed, no double)"
-					}
-				}
-				hfaMsg := ""
-				if hasHomeFieldAdvantage {
-					hfaMsg = " (Home Field Advantage: +15!)"
-				}
-				rtkMsg := ""
-				if hasRoughingTheKicker {
-					rtkMsg = " (Roughing the Kicker: -15% payout)"
-				}
-				heismanMsg := ""
-				if hasHeismanCampaign {
-					heismanMsg = " (Heisman Campaign: -15% payout)"
-				}
-				hedgeMsg := ""
-				if hedgeApplied && hedgeRefund > 0 {
-					hedgeMsg = fmt.Sprintf(" (Emotional Hedge: Refunding $%.1f)", hedgeRefund)
-				} else if hedgeApplied {
-					hedgeMsg = " (Emotional Hedge: consumed)"
-				}
-				insuranceMsg := ""
-				if insuranceApplied {
-					insuranceMsg = " (Bet Insurance: consumed)"
-				}
-
-				winnersList += fmt.Sprintf("%s - Won $%.1f (Uno Reverse!)%s%s%s%s%s%s%s\n", username, modifiedPayout, doubleDownMsg, gamblerMsg, hfaMsg, rtkMsg, heismanMsg, hedgeMsg, insuranceMsg)
-				continue
-			}
-
-			antiAntiBetPayout, antiAntiBetWinners, _, antiAntiBetApplied, err := cardService.ApplyAntiAntiBetIfApplicable(db, user, false)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Anti-Anti-Bet: %v", err), db)
-				return
-			}
-			if antiAntiBetApplied && antiAntiBetPayout > 0 {
-				totalPayout += antiAntiBetPayout
-
-				if len(antiAntiBetWinners) > 0 {
-					for _, winner := range antiAntiBetWinners {
-						username := common.GetUsernameWithDB(db, s, user.GuildID, winner.DiscordID)
-						winnersList += fmt.Sprintf("%s - Won $%.1f (Anti-Anti-Bet!)\n", username, winner.Payout)
-					}
-				}
-			}
-
-			consumer := func(db *gorm.DB, user models.User, cardID uint) error {
-				return cardService.PlayCardFromInventory(s, db, user, cardID)
-			}
-
-			jailRefund, jailApplied, err := cardService.ApplyGetOutOfJailIfApplicable(db, consumer, user, float64(entry.Amount))
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Get Out of Jail Free: %v", err), db)
-				return
-			}
-
-			if jailApplied && jailRefund > 0 {
-				user.Points += jailRefund
-				db.Save(&user)
-				username := common.GetUsernameWithDB(db, s, user.GuildID, user.DiscordID)
-				loserList += fmt.Sprintf("%s - **Lost $%.1f** (Get Out of Jail Free: Full refund!)\n", username, float64(entry.Amount))
-				continue
-			}
-
-			insuranceRefund, insuranceApplied, err := cardService.ApplyBetInsuranceIfApplicable(db, consumer, user, float64(entry.Amount), false)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Bet Insurance: %v", err), db)
-				return
-			}
-
-			lossAmount := float64(entry.Amount)
-			modifiedLoss, hasGambler, err := cardService.ApplyGamblerIfAvailable(db, consumer, user, -lossAmount, false)
-			if err != nil {
-				common.SendError(s, i, fmt.Errorf("error checking Gambler: %v", err), db)
-				return
-			}
-
-			actualLoss := lossAmount
-			if hasGambler && modifiedLoss < -lossAmount {
-				actualLoss = -modifiedLoss
-			}
-
-			user.TotalBetsLost++
-			user.TotalPointsLost += actualLoss
-
-			if insuranceApplied && insuranceRefund > 0 {
-				user.Points += insuranceRefund
-				lostPoolAmount -= insuranceRefund
-			}
-
-			db.Save(&user)
-			lostPoolAmount += actualLoss
-		}
-	}
-
-	if totalWinningPayouts > 0 {
-		vampirePayout, vampireWinners, vampireApplied, err := cardService.ApplyVampireIfApplicable(db, bet.GuildID, totalWinningPayouts, winnerDiscordIDs)
-		if err != nil {
-			common.SendError(s, i, fmt.Errorf("error checking Vampire: %v", err), db)
-			return
-		}
-		if vampireApplied && vampirePayout > 0 {
-			totalPayout += vampirePayout
-			if len(vampireWinners) > 0 {
-				for _, winner := range vampireWinners {
-					username := common.GetUsernameWithDB(db, s, bet.GuildID, winner.DiscordID)
-					winnersList += fmt.Sprintf("%s - Won $%.1f (Vampire)\n", username, winner.Payout)
-				}
-			}
-		}
-
-		loversPayout, loversWinners, loversApplied, err := cardService.ApplyTheLoversIfApplicable(db, bet.GuildID, winnerDiscordIDs)
-		if err != nil {
-			common.SendError(s, i, fmt.Errorf("error checking The Lovers: %v", err), db)
-			return
-		}
-		if loversApplied && loversPayout > 0 {
-			totalPayout += loversPayout
-			if len(loversWinners) > 0 {
-				for _, winner := range loversWinners {
-					username := common.GetUsernameWithDB(db, s, bet.GuildID, winner.DiscordID)
-					winnersList += fmt.Sprintf("%s - Won $%.1f (The Lovers)\n", username, winner.Payout)
-				}
-			}
-		}
-
-		devilDiverted, devilDivertedList, devilApplied, err := cardService.ApplyTheDevilIfApplicable(db, bet.GuildID, winnerDiscordIDs)
-		if err != nil {
-			common.SendError(s, i, fmt.Errorf("error checking The Devil: %v", err), db)
-			return
-		}
-		if devilApplied && devilDiverted > 0 {
-			totalPayout -= devilDiverted
-			if len(devilDivertedList) > 0 {
-				for _, diverted := range devilDivertedList {
-					username := common.GetUsernameWithDB(db, s, bet.GuildID, diverted.DiscordID)
-					netAmount := winnerDiscordIDs[diverted.DiscordID]
-					grossAmount := netAmount + diverted.Diverted
-					oldStr := fmt.Sprintf("%s - Won $%.1f", username, grossAmount)
-					newStr := fmt.Sprintf("%s - Won $%.1f ($%.1f diverted to pool via The Devil)", username, netAmount, diverted.Diverted)
-					winnersList = strings.Replace(winnersList, oldStr, newStr, 1)
-				}
-			}
-		}
-
-		emperorDiverted, emperorDivertedList, emperorApplied, err := cardService.ApplyTheEmperorIfApplicable(db, bet.GuildID, winnerDiscordIDs)
-		if err != nil {
-			common.SendError(s, i, fmt.Errorf("error checking The Emperor: %v", err), db)
-			return
-		}
-		if emperorApplied && emperorDiverted > 0 {
-			totalPayout -= emperorDiverted
-			if len(emperorDivertedList) > 0 {
-				for _, diverted := range emperorDivertedList {
-					username := common.GetUsernameWithDB(db, s, bet.GuildID, diverted.DiscordID)
-					netAmount := winnerDiscordIDs[diverted.DiscordID]
-					grossAmount := netAmount + diverted.Diverted
-					oldStr := fmt.Sprintf("%s - Won $%.1f", username, grossAmount)
-					newStr := fmt.Sprintf("%s - Won $%.1f ($%.1f diverted to pool via The Emperor)", username, netAmount, diverted.Diverted)
-					winnersList = strings.Replace(winnersList, oldStr, newStr, 1)
-				}
-			}
-		}
-	}
-
-	if lostPoolAmount > 0 {
-		db.Model(&models.Guild{}).Where("id = ?", guild.ID).UpdateColumn("pool", gorm.Expr("pool + ?", lostPoolAmount))
-	}
-
-	bet.Active = false
-	db.Model(&bet).UpdateColumn("paid", true).UpdateColumn("active", false)
-
-	err = UpdateParlaysOnBetResolution(s, db, bet.ID, winningOption, 0)
 	if err != nil {
-		fmt.Printf("Error updating parlays for bet %d: %v\n", bet.ID, err)
-	}
-
-	winningOptionName := bet.Option1
-	if winningOption == 2 {
-		winningOptionName = bet.Option2
+		common.SendError(s, i, fmt.Errorf("error settling bet %d: %v", bet.ID, err), db)
+		return
 	}
 
-	winnersText := strings.TrimSpace(winnersList)
-	losersText := strings.TrimSpace(loserList)
 	embed := messageService.BuildBetResolutionEmbed(
-		bet.Description,
-		fmt.Sprintf("Winning option: **%s**", winningOptionName),
-		totalPayout,
-		winnersText,
-		losersText,
+		settlement.Bet.Description,
+		ResultSubtitle(settlement.Bet, winningOption),
+		settlement.TotalPayout,
+		settlement.Winners,
+		settlement.Losers,
+		settlement.Pushes,
 	)
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -542,12 +193,26 @@ func ResolveBetByID(s *discordgo.Session, i *discordgo.InteractionCreate, betID
 	}
 }
 
+func respondBetAlreadyResolved(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Bet not found or already resolved.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
+
 func MyOpenBets(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
 	var bets []models.BetEntry
 	userID := i.Member.User.ID
 
 	result := db.
 		Preload("Bet").
+		Preload("Bet.Options").
 		Joins("JOIN bets ON bet_entries.bet_id = bets.id").
 		Joins("JOIN users ON bet_entries.user_id = users.id").
 		Where("users.discord_id = ? AND bets.paid = 0 AND bets.guild_id = ? and bet_entries.deleted_at is null", userID, i.GuildID).
@@ -586,12 +251,25 @@ func MyOpenBets(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.D
 		return
 	}
 
+	betIDs := make([]uint, 0, len(bets))
+	for _, bet := range bets {
+		betIDs = append(betIDs, bet.BetID)
+	}
+	latestLines, err := LatestLines(db, betIDs)
+	if err != nil {
+		log.Printf("Error loading lines for open bets: %v", err)
+		latestLines = map[uint]models.LineHistory{}
+	}
+
 	var fields []*discordgo.MessageEmbedField
+	var cashOutButtons []discordgo.MessageComponent
 	for idx, bet := range bets {
 		var fieldValue string
 		var optionName string
 
-		if bet.Spread != nil {
+		if bet.Total != nil {
+			optionName = common.FormatTotalOption(bet.Option, *bet.Total)
+		} else if bet.Spread != nil {
 			if bet.Option == 1 {
 				optionName = fmt.Sprintf("Home %s", common.FormatOdds(*bet.Spread))
 			} else {
@@ -599,15 +277,30 @@ func MyOpenBets(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.D
 				optionName = fmt.Sprintf("Away %s", common.FormatOdds(spreadVal))
 			}
 		} else {
-			if bet.Option == 1 {
-				optionName = bet.Bet.Option1
-			} else {
-				optionName = bet.Bet.Option2
-			}
+			optionName = common.GetOptionName(bet.Bet, bet.Option)
 		}
 
+		if bet.Odds != nil {
+			optionName = fmt.Sprintf("%s (%s)", optionName, common.FormatOdds(float64(*bet.Odds)))
+		}
 		fieldValue = fmt.Sprintf("**%s**\n💰 Amount: %d points", optionName, bet.Amount)
 
+		if line, found := latestLines[bet.BetID]; found {
+			label := "Current"
+			if !bet.Bet.Active {
+				label = "Closing"
+			}
+			fieldValue += fmt.Sprintf("\n📈 %s line: %s", label, FormatEntryLine(bet, line))
+			if value, unit, ok := ClosingLineValue(bet, line); ok {
+				fieldValue += fmt.Sprintf(" (CLV: %+.1f%s)", value, unit)
+			}
+		}
+
+		if offer, ok := BetEntryCashOut(bet); ok {
+			fieldValue += fmt.Sprintf("\n💸 Cash out: %.1f points", offer)
+			cashOutButtons = append(cashOutButtons, cashOutButton(fmt.Sprintf("Cash Out #%d (%.1f)", idx+1, offer), "bet", bet.ID, offer))
+		}
+
 		fieldName := fmt.Sprintf("%d. %s", idx+1, bet.Bet.Description)
 
 		fields = append(fields, &discordgo.MessageEmbedField{
@@ -624,11 +317,12 @@ func MyOpenBets(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.D
 		Color:       0x5865F2,
 	}
 
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
-			Flags:  discordgo.MessageFlagsEphemeral,
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: cashOutButtonRows(cashOutButtons),
+			Flags:      discordgo.MessageFlagsEphemeral,
 		},
 	})
 	if err != nil {
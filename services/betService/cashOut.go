@@ -0,0 +1,613 @@
+package betService
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/ledgerService"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// cashOutHold is the share of a ticket's fair value kept back from a cash
+	// out offer.
+	cashOutHold = 0.10
+	// cashOutPointValue is the change in win probability for each point an
+	// entry's spread or total is better or worse than the current line.
+	cashOutPointValue = 0.03
+	// liveStateMaxAge is how old a live score can be and still price an offer.
+	// The live tracker refreshes scores every two minutes, so a score older
+	// than one poll and a little slack is no longer the game's.
+	liveStateMaxAge = 3 * time.Minute
+)
+
+// liveMarginStdDev is the standard deviation of a full game's final margin,
+// and of its total against the line, by sport.
+var liveMarginStdDev = map[string]float64{
+	models.SportCFB: 16,
+	models.SportNFL: 13.5,
+	models.SportCBB: 11,
+	models.SportNBA: 12,
+}
+
+var (
+	// ErrCashOutUnavailable is returned when a ticket has no cash out offer,
+	// such as once its game has closed without a live score or it has settled.
+	ErrCashOutUnavailable = errors.New("cash out unavailable")
+	// ErrCashOutDropped is returned when the offer has fallen below the one the
+	// user accepted.
+	ErrCashOutDropped = errors.New("cash out offer dropped")
+)
+
+// CashOutBlockedError is a parlay with no cash out offer because some of its
+// open legs can't be priced right now. Its message names those legs. It
+// matches ErrCashOutUnavailable with errors.Is.
+type CashOutBlockedError struct {
+	Legs []string
+}
+
+func (e *CashOutBlockedError) Error() string {
+	return fmt.Sprintf("%s can't be priced right now", strings.Join(e.Legs, ", "))
+}
+
+func (e *CashOutBlockedError) Is(target error) bool {
+	return target == ErrCashOutUnavailable
+}
+
+// LiveState is a game's score as the live tracker last saw it, with scores in
+// the order of the bet's options.
+type LiveState struct {
+	Score1 int
+	Score2 int
+	// Remaining is the share of the game left to play, from 1 at kickoff to 0
+	// at the final whistle.
+	Remaining float64
+	UpdatedAt time.Time
+}
+
+var (
+	liveStates   = make(map[uint]LiveState)
+	liveStatesMu sync.RWMutex
+)
+
+// SetLiveState records the live score of a bet's game for cash out offers.
+func SetLiveState(betID uint, state LiveState) {
+	liveStatesMu.Lock()
+	defer liveStatesMu.Unlock()
+	liveStates[betID] = state
+}
+
+// ClearLiveState forgets a bet's live score once its game is over.
+func ClearLiveState(betID uint) {
+	liveStatesMu.Lock()
+	defer liveStatesMu.Unlock()
+	delete(liveStates, betID)
+}
+
+func currentLiveState(betID uint) (LiveState, bool) {
+	liveStatesMu.RLock()
+	defer liveStatesMu.RUnlock()
+	state, found := liveStates[betID]
+	if !found || time.Since(state.UpdatedAt) > liveStateMaxAge {
+		return LiveState{}, false
+	}
+	return state, true
+}
+
+// BetEntryCashOut returns what an entry can be cashed out for: its payout,
+// times the chance it wins, less cashOutHold. entry.Bet and its options must
+// be loaded.
+func BetEntryCashOut(entry models.BetEntry) (float64, bool) {
+	probability, ok := legWinProbability(entry.Bet, entry.Option, entry.Spread, entry.Total)
+	if !ok {
+		return 0, false
+	}
+	payout := common.CalculateEntryPayout(entry, entry.Option, entry.Bet)
+	return cashOutOffer(payout * probability)
+}
+
+// ParlayCashOut returns what an open parlay can be cashed out for: its stake
+// times the odds of the legs already won and the fair value of the legs still
+// open, less cashOutHold. Pushed and voided legs are left out, as they are
+// when the parlay is paid. ParlayEntries and their bets must be loaded.
+//
+// It returns ErrCashOutUnavailable once the parlay has settled or a leg has
+// lost, and a *CashOutBlockedError naming every open leg that can't be priced.
+func ParlayCashOut(parlay models.Parlay) (float64, error) {
+	if parlay.Status != "pending" && parlay.Status != "partial" {
+		return 0, ErrCashOutUnavailable
+	}
+
+	value := float64(parlay.Amount)
+	var blocked []string
+	for _, entry := range parlay.ParlayEntries {
+		if entry.Push || entry.Void {
+			continue
+		}
+		multiplier := common.CalculateParlayOddsMultiplier([]int{common.GetParlayEntryOdds(entry)})
+		if entry.Resolved {
+			if entry.Won == nil || !*entry.Won {
+				return 0, ErrCashOutUnavailable
+			}
+			value *= multiplier
+			continue
+		}
+		probability, ok := legWinProbability(entry.Bet, entry.SelectedOption, entry.Spread, entry.Total)
+		if !ok {
+			blocked = append(blocked, cashOutLegName(entry))
+			continue
+		}
+		value *= multiplier * probability
+	}
+	if len(blocked) > 0 {
+		return 0, &CashOutBlockedError{Legs: blocked}
+	}
+	offer, ok := cashOutOffer(value)
+	if !ok {
+		return 0, ErrCashOutUnavailable
+	}
+	return offer, nil
+}
+
+// cashOutLegName names a leg by its bet's matchup, without the extra lines
+// some bet descriptions carry.
+func cashOutLegName(entry models.ParlayEntry) string {
+	name := strings.TrimSpace(strings.SplitN(entry.Bet.Description, "\n", 2)[0])
+	if name == "" {
+		return fmt.Sprintf("Bet #%d", entry.BetID)
+	}
+	return name
+}
+
+func cashOutOffer(fairValue float64) (float64, bool) {
+	offer := common.FromMinorUnits(int64(math.Floor(fairValue * (1 - cashOutHold) * common.PointsScale)))
+	return offer, offer > 0
+}
+
+// legWinProbability is the chance an option wins at the given line. Only bets
+// on a game are priced: open bets before kickoff from their current odds, and
+// bets whose game is under way from a live score no older than
+// liveStateMaxAge. Custom bets, and bets past their start or lock time without
+// a fresh live score, have no price.
+func legWinProbability(bet models.Bet, option int, spread *float64, total *float64) (float64, bool) {
+	if bet.Paid || bet.Voided || (bet.CfbdID == nil && bet.EspnID == nil) {
+		return 0, false
+	}
+	if bet.Active && !betStarted(bet, time.Now()) {
+		return pregameWinProbability(bet, option, spread, total), true
+	}
+	if state, found := currentLiveState(bet.ID); found {
+		return liveWinProbability(bet, option, spread, total, state), true
+	}
+	return 0, false
+}
+
+// betStarted reports whether a bet's game has kicked off or its lock time has
+// passed, whether or not a job has closed it yet.
+func betStarted(bet models.Bet, now time.Time) bool {
+	return (bet.GameStartDate != nil && !bet.GameStartDate.After(now)) ||
+		(bet.LockAt != nil && !bet.LockAt.After(now))
+}
+
+// pregameWinProbability is the chance the bet's current odds give an option,
+// with the bookmaker's margin taken out, moved cashOutPointValue for each
+// point the entry's line is better or worse than the current one.
+func pregameWinProbability(bet models.Bet, option int, spread *float64, total *float64) float64 {
+	probability := noVigProbability(bet, option)
+
+	switch {
+	case total != nil && bet.Total != nil:
+		edge := *bet.Total - *total
+		if option == 2 {
+			edge = -edge
+		}
+		probability += edge * cashOutPointValue
+	case spread != nil && bet.Spread != nil:
+		edge := *spread - *bet.Spread
+		if option == 2 {
+			edge = -edge
+		}
+		probability += edge * cashOutPointValue
+	}
+	return clampProbability(probability)
+}
+
+func noVigProbability(bet models.Bet, option int) float64 {
+	var selected, sum float64
+	for _, betOption := range common.GetBetOptions(bet) {
+		probability := impliedProbability(betOption.Odds)
+		sum += probability
+		if betOption.OptionNumber == option {
+			selected = probability
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+	return selected / sum
+}
+
+// liveWinProbability is the chance an option wins given the live score. The
+// rest of the game is expected to go as the bet's line said the whole game
+// would, scaled to the time left, with normally distributed error.
+func liveWinProbability(bet models.Bet, option int, spread *float64, total *float64, state LiveState) float64 {
+	fullGameStdDev, found := liveMarginStdDev[bet.Sport]
+	if !found {
+		fullGameStdDev = liveMarginStdDev[models.SportNFL]
+	}
+	stdDev := fullGameStdDev * math.Sqrt(state.Remaining)
+
+	if total != nil || (spread == nil && bet.Total != nil) {
+		line := bet.Total
+		if total != nil {
+			line = total
+		}
+		expected := float64(state.Score1+state.Score2) + pregameTotal(bet, *line)*state.Remaining
+		over := 1 - normalCDF(*line, expected, stdDev)
+		if option == 1 {
+			return clampProbability(over)
+		}
+		return clampProbability(1 - over)
+	}
+
+	// Option 1 covers when its final margin beats minus its spread, or wins
+	// outright on a moneyline.
+	line := 0.0
+	if spread != nil {
+		line = *spread
+	} else if bet.Spread != nil {
+		line = *bet.Spread
+	}
+	expected := float64(state.Score1-state.Score2) + pregameMargin(bet, fullGameStdDev)*state.Remaining
+	covers := 1 - normalCDF(-line, expected, stdDev)
+	if option == 1 {
+		return clampProbability(covers)
+	}
+	return clampProbability(1 - covers)
+}
+
+// pregameTotal is the combined score the bet's total expected for the whole
+// game.
+func pregameTotal(bet models.Bet, line float64) float64 {
+	if bet.Total != nil {
+		return *bet.Total
+	}
+	return line
+}
+
+// pregameMargin is option 1's expected full game margin: minus its spread, or
+// for a moneyline the margin that makes its win probability what the odds
+// imply.
+func pregameMargin(bet models.Bet, fullGameStdDev float64) float64 {
+	if bet.Spread != nil {
+		return -*bet.Spread
+	}
+	probability := clampProbability(noVigProbability(bet, 1))
+	return fullGameStdDev * math.Sqrt2 * math.Erfinv(2*probability-1)
+}
+
+func normalCDF(x float64, mean float64, stdDev float64) float64 {
+	if stdDev <= 0 {
+		switch {
+		case x > mean:
+			return 1
+		case x < mean:
+			return 0
+		}
+		return 0.5
+	}
+	return 0.5 * (1 + math.Erf((x-mean)/(stdDev*math.Sqrt2)))
+}
+
+func clampProbability(probability float64) float64 {
+	return math.Min(math.Max(probability, 0.01), 0.99)
+}
+
+// CashOutBetEntry settles a user's entry for its current cash out offer, as
+// long as the offer is at least minimum. The bet is locked so the entry can't
+// be settled at the same time, and the entry is deleted so settlement skips
+// it. The entry comes off its option's bet count, and once the user has no
+// entries left on the bet their cards on it are released. It returns the
+// amount paid, or the current offer with ErrCashOutDropped.
+func CashOutBetEntry(db *gorm.DB, entryID uint, discordID string, guildID string, minimum float64) (float64, error) {
+	var offer float64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var entry models.BetEntry
+		if err := tx.First(&entry, entryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCashOutUnavailable
+			}
+			return err
+		}
+
+		var bet models.Bet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Options").First(&bet, entry.BetID).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, entry.UserID).Error; err != nil {
+			return err
+		}
+		if user.DiscordID != discordID || user.GuildID != guildID || bet.GuildID != guildID {
+			return ErrCashOutUnavailable
+		}
+
+		// Re-read the entry under the bet's lock, in case it was settled or
+		// cashed out while waiting for it.
+		if err := tx.First(&entry, entryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCashOutUnavailable
+			}
+			return err
+		}
+		entry.Bet = bet
+
+		var ok bool
+		offer, ok = BetEntryCashOut(entry)
+		if !ok {
+			return ErrCashOutUnavailable
+		}
+		if offer < minimum {
+			return ErrCashOutDropped
+		}
+
+		if err := ledgerService.Apply(tx, &user, offer, ledgerService.Bet(ledgerService.ReasonBetCashOut, bet.ID)); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BetEntry{}).Where("id = ?", entry.ID).Update("cash_out", offer).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.BetEntry{}, entry.ID).Error; err != nil {
+			return err
+		}
+		if err := decrementBetCount(tx, bet, entry.Option); err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.BetEntry{}).Where("bet_id = ? AND user_id = ?", bet.ID, user.ID).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining == 0 {
+			if _, err := cardService.ReleaseCardsOnBet(tx, user.ID, bet.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return offer, err
+}
+
+// decrementBetCount takes an entry off its option's bet count. Bets from
+// before options had their own rows keep the count on the bet.
+func decrementBetCount(tx *gorm.DB, bet models.Bet, option int) error {
+	if len(bet.Options) > 0 {
+		return tx.Model(&models.BetOption{}).
+			Where("bet_id = ? AND option_number = ? AND bet_count > 0", bet.ID, option).
+			UpdateColumn("bet_count", gorm.Expr("bet_count - 1")).Error
+	}
+	column := "bets_option1"
+	if option == 2 {
+		column = "bets_option2"
+	}
+	return tx.Model(&models.Bet{}).
+		Where("id = ? AND "+column+" > 0", bet.ID).
+		UpdateColumn(column, gorm.Expr(column+" - 1")).Error
+}
+
+// CashOutParlay settles a user's parlay for its current cash out offer, as
+// long as the offer is at least minimum. A round robin parlay cashes out every
+// open parlay on its ticket. The bets of the open legs are locked so they
+// can't settle at the same time, and the open legs are marked resolved so
+// settlement skips them. It returns the amount paid, or the current offer with
+// ErrCashOutDropped.
+func CashOutParlay(db *gorm.DB, parlayID uint, discordID string, guildID string, minimum float64) (float64, error) {
+	var total float64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var parlay models.Parlay
+		if err := tx.Preload("ParlayEntries").First(&parlay, parlayID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCashOutUnavailable
+			}
+			return err
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, parlay.UserID).Error; err != nil {
+			return err
+		}
+		if user.DiscordID != discordID || user.GuildID != guildID || parlay.GuildID != guildID {
+			return ErrCashOutUnavailable
+		}
+
+		loadTicket := func() ([]models.Parlay, error) {
+			query := tx.Preload("ParlayEntries").Preload("ParlayEntries.Bet").Preload("ParlayEntries.Bet.Options").
+				Where("status IN ?", []string{"pending", "partial"})
+			if parlay.TicketID != "" {
+				query = query.Where("ticket_id = ? AND user_id = ?", parlay.TicketID, user.ID)
+			} else {
+				query = query.Where("id = ?", parlay.ID)
+			}
+			var parlays []models.Parlay
+			err := query.Find(&parlays).Error
+			return parlays, err
+		}
+
+		parlays, err := loadTicket()
+		if err != nil {
+			return err
+		}
+
+		// Lock the open legs' bets in ID order, then read the parlays again
+		// in case a leg settled while waiting.
+		var betIDs []uint
+		seen := make(map[uint]bool)
+		for _, p := range parlays {
+			for _, entry := range p.ParlayEntries {
+				if !entry.Resolved && !seen[entry.BetID] {
+					seen[entry.BetID] = true
+					betIDs = append(betIDs, entry.BetID)
+				}
+			}
+		}
+		sort.Slice(betIDs, func(a, b int) bool { return betIDs[a] < betIDs[b] })
+		if len(betIDs) > 0 {
+			var locked []models.Bet
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", betIDs).Order("id").Find(&locked).Error; err != nil {
+				return err
+			}
+		}
+
+		parlays, err = loadTicket()
+		if err != nil {
+			return err
+		}
+		if len(parlays) == 0 {
+			return ErrCashOutUnavailable
+		}
+
+		offers := make([]float64, len(parlays))
+		for idx, p := range parlays {
+			offer, err := ParlayCashOut(p)
+			if err != nil {
+				return err
+			}
+			offers[idx] = offer
+			total += offer
+		}
+		if total < minimum {
+			return ErrCashOutDropped
+		}
+
+		for idx, p := range parlays {
+			if err := ledgerService.Apply(tx, &user, offers[idx], ledgerService.Parlay(ledgerService.ReasonParlayCashOut, p.ID)); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Parlay{}).Where("id = ?", p.ID).Updates(map[string]interface{}{
+				"status":   "cashed_out",
+				"cash_out": offers[idx],
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ParlayEntry{}).Where("parlay_id = ? AND resolved = ?", p.ID, false).Update("resolved", true).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+// HandleCashOut accepts the cash out offer on a /my-bets or /my-parlays
+// button. The ticket is settled as long as its offer hasn't dropped below the
+// one on the button; if it has risen, the higher offer is paid.
+func HandleCashOut(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	parts := strings.Split(customID, "_")
+	if len(parts) != 4 {
+		return fmt.Errorf("invalid cash out custom ID format")
+	}
+
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return err
+	}
+	quoted, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return err
+	}
+	minimum := common.FromMinorUnits(quoted)
+
+	var paid float64
+	switch parts[1] {
+	case "bet":
+		paid, err = CashOutBetEntry(db, uint(id), i.Member.User.ID, i.GuildID, minimum)
+	case "parlay":
+		paid, err = CashOutParlay(db, uint(id), i.Member.User.ID, i.GuildID, minimum)
+	default:
+		return fmt.Errorf("invalid cash out custom ID format")
+	}
+
+	var content string
+	var blocked *CashOutBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		content = fmt.Sprintf("This ticket can't be cashed out right now: %s.", blocked.Error())
+	case errors.Is(err, ErrCashOutUnavailable):
+		content = "This ticket can no longer be cashed out."
+	case errors.Is(err, ErrCashOutDropped):
+		content = fmt.Sprintf("The cash out offer has dropped to **%.1f** points. Run the command again to see the new offer.", paid)
+	case err != nil:
+		return err
+	default:
+		content = fmt.Sprintf("💸 Cashed out for **%.1f** points.", paid)
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func cashOutButton(label string, kind string, id uint, offer float64) discordgo.Button {
+	return discordgo.Button{
+		Label:    label,
+		CustomID: fmt.Sprintf("cashout_%s_%d_%d", kind, id, common.ToMinorUnits(offer)),
+		Style:    discordgo.SecondaryButton,
+		Emoji:    &discordgo.ComponentEmoji{Name: "💸"},
+	}
+}
+
+// cashOutButtonRows lays buttons out five to a row, dropping any past the
+// five rows a message can hold.
+func cashOutButtonRows(buttons []discordgo.MessageComponent) []discordgo.MessageComponent {
+	var rows []discordgo.MessageComponent
+	for len(buttons) > 0 && len(rows) < 5 {
+		n := len(buttons)
+		if n > 5 {
+			n = 5
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[:n]})
+		buttons = buttons[n:]
+	}
+	return rows
+}
+
+// ticketCashOut totals the offers on a ticket's open parlays. It returns the
+// ID of the first, which stands for the ticket on its cash out button, or the
+// error from the first parlay without an offer.
+func ticketCashOut(ticket []models.Parlay) (float64, uint, error) {
+	var total float64
+	var parlayID uint
+	for _, parlay := range ticket {
+		if parlay.Status != "pending" && parlay.Status != "partial" {
+			continue
+		}
+		offer, err := ParlayCashOut(parlay)
+		if err != nil {
+			return 0, 0, err
+		}
+		if parlayID == 0 {
+			parlayID = parlay.ID
+		}
+		total += offer
+	}
+	if parlayID == 0 {
+		return 0, 0, ErrCashOutUnavailable
+	}
+	return total, parlayID, nil
+}
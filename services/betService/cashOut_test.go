@@ -0,0 +1,167 @@
+package betService
+
+import (
+	"errors"
+	"math"
+	"perfectOddsBot/models"
+	"testing"
+	"time"
+)
+
+func TestPregameWinProbability(t *testing.T) {
+	spread := -7.5
+	bet := models.Bet{Active: true, Odds1: -110, Odds2: -110, Spread: &spread}
+
+	if p := pregameWinProbability(bet, 1, &spread, nil); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("expected an even line to be 50%%, got %v", p)
+	}
+
+	// Taken at -3.5 with the line now -7.5, the favorite has four points to
+	// spare.
+	taken := -3.5
+	if p := pregameWinProbability(bet, 1, &taken, nil); math.Abs(p-0.62) > 1e-9 {
+		t.Errorf("expected 62%% for a line four points better, got %v", p)
+	}
+	if p := pregameWinProbability(bet, 2, &taken, nil); math.Abs(p-0.38) > 1e-9 {
+		t.Errorf("expected 38%% for a line four points worse, got %v", p)
+	}
+
+	moneyline := models.Bet{Active: true, Odds1: -200, Odds2: 170}
+	p1 := pregameWinProbability(moneyline, 1, nil, nil)
+	p2 := pregameWinProbability(moneyline, 2, nil, nil)
+	if math.Abs(p1+p2-1) > 1e-9 || p1 <= p2 {
+		t.Errorf("expected no-vig probabilities favoring option 1, got %v and %v", p1, p2)
+	}
+}
+
+func TestBetEntryCashOut(t *testing.T) {
+	spread := -7.5
+	odds := -110
+	gameID := "401"
+	kickoff := time.Now().Add(time.Hour)
+	entry := models.BetEntry{
+		Option: 1,
+		Amount: 100,
+		Spread: &spread,
+		Odds:   &odds,
+		Bet:    models.Bet{ID: 1, Active: true, Odds1: -110, Odds2: -110, Spread: &spread, CfbdID: &gameID, GameStartDate: &kickoff},
+	}
+
+	// A 190.90 payout at even chances is worth 95.45, less the hold.
+	offer, ok := BetEntryCashOut(entry)
+	if !ok || offer != 85.9 {
+		t.Errorf("expected an offer of 85.9, got %v (%v)", offer, ok)
+	}
+
+	custom := entry
+	custom.Bet.CfbdID = nil
+	if _, ok := BetEntryCashOut(custom); ok {
+		t.Error("expected no offer on a custom bet")
+	}
+
+	started := time.Now().Add(-time.Minute)
+	entry.Bet.GameStartDate = &started
+	if _, ok := BetEntryCashOut(entry); ok {
+		t.Error("expected no offer on a bet still open after kickoff without a live score")
+	}
+
+	entry.Bet.Active = false
+	if _, ok := BetEntryCashOut(entry); ok {
+		t.Error("expected no offer on a closed bet without a live score")
+	}
+
+	SetLiveState(entry.Bet.ID, LiveState{Score1: 28, Score2: 3, Remaining: 0.25, UpdatedAt: time.Now().Add(-liveStateMaxAge - time.Minute)})
+	if _, ok := BetEntryCashOut(entry); ok {
+		t.Error("expected no offer from a stale live score")
+	}
+
+	SetLiveState(entry.Bet.ID, LiveState{Score1: 28, Score2: 3, Remaining: 0.25, UpdatedAt: time.Now()})
+	defer ClearLiveState(entry.Bet.ID)
+	live, ok := BetEntryCashOut(entry)
+	if !ok || live <= offer {
+		t.Errorf("expected a bigger offer with the favorite up 25 late, got %v (%v)", live, ok)
+	}
+
+	entry.Bet.Paid = true
+	if _, ok := BetEntryCashOut(entry); ok {
+		t.Error("expected no offer on a settled bet")
+	}
+}
+
+func TestLiveWinProbability(t *testing.T) {
+	spread := -3.0
+	total := 45.0
+	bet := models.Bet{Sport: models.SportNFL, Spread: &spread}
+
+	if p := liveWinProbability(bet, 1, &spread, nil, LiveState{Remaining: 1}); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("expected a game at kickoff to be 50%% against the spread, got %v", p)
+	}
+	if p := liveWinProbability(bet, 1, &spread, nil, LiveState{Score1: 10, Score2: 0, Remaining: 0.5}); p < 0.7 {
+		t.Errorf("expected the favorite up 10 at the half to be likely to cover, got %v", p)
+	}
+	if p := liveWinProbability(bet, 2, &spread, nil, LiveState{Score1: 7, Score2: 3, Remaining: 0}); p != 0.01 {
+		t.Errorf("expected a final margin past the spread to leave the underdog no chance, got %v", p)
+	}
+
+	totalBet := models.Bet{Sport: models.SportNFL, Total: &total}
+	if p := liveWinProbability(totalBet, 1, nil, &total, LiveState{Score1: 24, Score2: 17, Remaining: 0.5}); p < 0.9 {
+		t.Errorf("expected 41 points at the half to make the over likely, got %v", p)
+	}
+
+	moneyline := models.Bet{Sport: models.SportNBA, Odds1: -110, Odds2: -110}
+	if p := liveWinProbability(moneyline, 1, nil, nil, LiveState{Score1: 50, Score2: 50, Remaining: 0.5}); math.Abs(p-0.5) > 1e-9 {
+		t.Errorf("expected a tied pick'em to be 50%%, got %v", p)
+	}
+}
+
+func TestParlayCashOut(t *testing.T) {
+	won := true
+	lost := false
+	odds := 100
+	gameID := "401"
+	open := models.Bet{ID: 2, Active: true, Odds1: 100, Odds2: 100, EspnID: &gameID}
+
+	parlay := models.Parlay{
+		Amount: 100,
+		Status: "partial",
+		ParlayEntries: []models.ParlayEntry{
+			{BetID: 1, SelectedOption: 1, Odds: &odds, Resolved: true, Won: &won},
+			{BetID: 3, SelectedOption: 1, Odds: &odds, Resolved: true, Push: true},
+			{BetID: 2, SelectedOption: 1, Odds: &odds, Bet: open},
+		},
+	}
+
+	// The won leg doubles the stake and the open leg is a coin flip at +100,
+	// so the parlay is worth 200 less the hold. The push is left out.
+	offer, err := ParlayCashOut(parlay)
+	if err != nil || offer != 180 {
+		t.Errorf("expected an offer of 180, got %v (%v)", offer, err)
+	}
+
+	parlay.ParlayEntries = append(parlay.ParlayEntries, models.ParlayEntry{
+		BetID:          4,
+		SelectedOption: 1,
+		Odds:           &odds,
+		Bet:            models.Bet{ID: 4, Description: "Rice @ Navy\n- Broadcast: CBSSN", Odds1: 100, Odds2: 100, EspnID: &gameID},
+	})
+	_, err = ParlayCashOut(parlay)
+	var blocked *CashOutBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, ErrCashOutUnavailable) {
+		t.Fatalf("expected a closed leg without a live score to block the offer, got %v", err)
+	}
+	if len(blocked.Legs) != 1 || blocked.Legs[0] != "Rice @ Navy" {
+		t.Errorf("expected the blocking leg to be named, got %v", blocked.Legs)
+	}
+	parlay.ParlayEntries = parlay.ParlayEntries[:3]
+
+	parlay.ParlayEntries[0].Won = &lost
+	if _, err := ParlayCashOut(parlay); !errors.Is(err, ErrCashOutUnavailable) {
+		t.Errorf("expected no offer once a leg has lost, got %v", err)
+	}
+
+	parlay.ParlayEntries[0].Won = &won
+	parlay.Status = "cashed_out"
+	if _, err := ParlayCashOut(parlay); !errors.Is(err, ErrCashOutUnavailable) {
+		t.Errorf("expected no offer on a parlay that is already cashed out, got %v", err)
+	}
+}
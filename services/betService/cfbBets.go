@@ -2,12 +2,15 @@ package betService
 
 import (
 	"fmt"
+	"log"
 	"math"
 	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/extService"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/messageService"
+	"perfectOddsBot/services/teamService"
 	"strconv"
 	"sync"
 	"time"
@@ -60,7 +63,7 @@ func CreateCFBBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 
 	var dbBet models.Bet
 	result := db.
-		Where("cfbd_id = ? AND guild_id = ?", betID, i.GuildID).
+		Where("cfbd_id = ? AND guild_id = ? AND link = ''", betID, i.GuildID).
 		Find(&dbBet)
 	if result.Error != nil {
 		common.SendError(s, i, result.Error, db)
@@ -98,18 +101,12 @@ func CreateCFBBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 			lineValue += 0.5
 		}
 
-		loc, err := time.LoadLocation("America/New_York")
-		if err != nil {
-			common.SendError(s, i, err, db)
-			return
-		}
-		t := cfbdBet.StartDate.In(loc)
-		formattedTime := t.Format("Mon 03:04 pm MST")
+		homeTeam, awayTeam := cfbTeamNames(teamService.ForGuild(db, guildID), cfbdBet)
 
 		dbBet = models.Bet{
-			Description:   fmt.Sprintf("%s @ %s (%s)", cfbdBet.AwayTeam, cfbdBet.HomeTeam, formattedTime),
-			Option1:       fmt.Sprintf("%s %s", cfbdBet.HomeTeam, common.FormatOdds(lineValue)),
-			Option2:       fmt.Sprintf("%s %s", cfbdBet.AwayTeam, common.FormatOdds(lineValue*-1)),
+			Description:   fmt.Sprintf("%s @ %s", awayTeam, homeTeam),
+			Option1:       fmt.Sprintf("%s %s", homeTeam, common.FormatOdds(lineValue)),
+			Option2:       fmt.Sprintf("%s %s", awayTeam, common.FormatOdds(lineValue*-1)),
 			Odds1:         -110,
 			Odds2:         -110,
 			Active:        true,
@@ -117,16 +114,20 @@ func CreateCFBBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 			ChannelID:     i.ChannelID,
 			GameStartDate: &cfbdBet.StartDate,
 			CfbdID:        &cfbdBetID,
+			Sport:         models.SportCFB,
 			AdminCreated:  common.IsAdmin(s, i),
 			Spread:        &lineValue,
 		}
 		db.Create(&dbBet)
+		if _, err := RecordLine(db, dbBet); err != nil {
+			log.Printf("Error recording opening line for bet %d: %v", dbBet.ID, err)
+		}
 	}
 
 	buttons := messageService.GetBetOnlyButtonsList(dbBet.Option1, dbBet.Option2, dbBet.ID)
 	embed := &discordgo.MessageEmbed{
 		Title:       "📢 New CFB Bet Created (Will Auto Close & Resolve)",
-		Description: dbBet.Description,
+		Description: common.GameBetDescription(dbBet),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:  fmt.Sprintf("1️⃣ %s", dbBet.Option1),
@@ -196,6 +197,7 @@ func CreateCFBBetSelector(s *discordgo.Session, i *discordgo.InteractionCreate,
 	}
 
 	conferenceList := []string{"Big Ten", "ACC", "SEC", "Big 12", "Pac-12"}
+	teams := teamService.ForGuild(db, i.GuildID)
 	var selectOptions []discordgo.SelectMenuOption
 	for _, bet := range bettingLines {
 		if (common.Contains(conferenceList, bet.HomeConference) || common.Contains(conferenceList, bet.AwayConference)) &&
@@ -206,12 +208,13 @@ func CreateCFBBetSelector(s *discordgo.Session, i *discordgo.InteractionCreate,
 				continue
 			}
 
-			label := fmt.Sprintf("%s @ %s", bet.AwayTeam, bet.HomeTeam)
+			homeTeam, awayTeam := cfbTeamNames(teams, bet)
+			label := fmt.Sprintf("%s @ %s", awayTeam, homeTeam)
 			if len(label) > 100 {
 				label = label[:97] + "..."
 			}
 
-			description := line.FormattedSpread
+			description := fmt.Sprintf("%s - %s", common.FormatGameTime(bet.StartDate, common.GuildLocation(*guild)), line.FormattedSpread)
 			if len(description) > 100 {
 				description = description[:97] + "..."
 			}
@@ -315,8 +318,7 @@ func ShowCFBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreat
 		return err
 	}
 
-	homeTeam := cfbdBet.HomeTeam
-	awayTeam := cfbdBet.AwayTeam
+	homeTeam, awayTeam := cfbTeamNames(teamService.ForGuild(db, i.GuildID), cfbdBet)
 
 	var spreadValue float64
 	homeSpreadOdds := -110
@@ -351,7 +353,13 @@ func ShowCFBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreat
 		},
 	}
 
-	var buttons []discordgo.MessageComponent
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Create ATS Bet",
+			CustomID: fmt.Sprintf("cfb_bet_type_ats_%d", betID),
+			Style:    discordgo.PrimaryButton,
+		},
+	}
 	if moneylineAvailable {
 		moneylineField := fmt.Sprintf("**Moneyline**\n1️⃣ %s (Odds: %s)\n2️⃣ %s (Odds: %s)",
 			homeTeam, common.FormatOdds(float64(homeMoneyline)),
@@ -360,42 +368,45 @@ func ShowCFBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreat
 			Name:  "💰 Moneyline Bet",
 			Value: moneylineField,
 		})
-		buttons = []discordgo.MessageComponent{
-			discordgo.Button{
-				Label:    "Create ATS Bet",
-				CustomID: fmt.Sprintf("cfb_bet_type_ats_%d", betID),
-				Style:    discordgo.PrimaryButton,
-			},
-			discordgo.Button{
-				Label:    "Create Moneyline Bet",
-				CustomID: fmt.Sprintf("cfb_bet_type_ml_%d", betID),
-				Style:    discordgo.SuccessButton,
-			},
-			discordgo.Button{
-				Label:    "Cancel",
-				CustomID: fmt.Sprintf("cfb_bet_type_cancel_%d", betID),
-				Style:    discordgo.DangerButton,
-			},
-		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Create Moneyline Bet",
+			CustomID: fmt.Sprintf("cfb_bet_type_ml_%d", betID),
+			Style:    discordgo.SuccessButton,
+		})
 	} else {
 		embedFields = append(embedFields, &discordgo.MessageEmbedField{
 			Name:  "💰 Moneyline Bet",
 			Value: "No moneyline bet available",
 		})
-		buttons = []discordgo.MessageComponent{
-			discordgo.Button{
-				Label:    "Create ATS Bet",
-				CustomID: fmt.Sprintf("cfb_bet_type_ats_%d", betID),
-				Style:    discordgo.PrimaryButton,
-			},
-			discordgo.Button{
-				Label:    "Cancel",
-				CustomID: fmt.Sprintf("cfb_bet_type_cancel_%d", betID),
-				Style:    discordgo.DangerButton,
-			},
-		}
 	}
 
+	if line.OverUnder != nil {
+		totalValue := *line.OverUnder
+		totalField := fmt.Sprintf("**Over/Under (Combined Score)**\n1️⃣ %s (Odds: %s)\n2️⃣ %s (Odds: %s)",
+			common.FormatTotalOption(1, totalValue), common.FormatOdds(-110),
+			common.FormatTotalOption(2, totalValue), common.FormatOdds(-110))
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "🔢 Over/Under Bet",
+			Value: totalField,
+		})
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Create Over/Under Bet",
+			CustomID: fmt.Sprintf("cfb_bet_type_total_%d", betID),
+			Style:    discordgo.SecondaryButton,
+		})
+	} else {
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "🔢 Over/Under Bet",
+			Value: "No over/under bet available",
+		})
+	}
+
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Cancel",
+		CustomID: fmt.Sprintf("cfb_bet_type_cancel_%d", betID),
+		Style:    discordgo.DangerButton,
+	})
+
 	embed := &discordgo.MessageEmbed{
 		Title:       "Select Bet Type",
 		Description: description,
@@ -430,12 +441,7 @@ func CreateCFBBetFromGameID(s *discordgo.Session, i *discordgo.InteractionCreate
 	}
 
 	var dbBet models.Bet
-	var result *gorm.DB
-	if betType == "moneyline" || betType == "ml" {
-		result = db.Where("cfbd_id = ? AND guild_id = ? AND spread IS NULL", betID, i.GuildID).Find(&dbBet)
-	} else {
-		result = db.Where("cfbd_id = ? AND guild_id = ? AND spread IS NOT NULL", betID, i.GuildID).Find(&dbBet)
-	}
+	result := whereBetType(db.Where("cfbd_id = ? AND guild_id = ? AND link = ''", betID, i.GuildID), betType).Find(&dbBet)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -454,73 +460,24 @@ func CreateCFBBetFromGameID(s *discordgo.Session, i *discordgo.InteractionCreate
 			return err
 		}
 
-		cfbdBetID := strconv.Itoa(cfbdBet.ID)
-
-		var option1, option2 string
-		var odds1, odds2 int
-		var spreadValue *float64
-
-		if betType == "moneyline" || betType == "ml" {
-			if line.HomeMoneyline == nil || line.AwayMoneyline == nil {
-				return fmt.Errorf("moneyline odds are not available for this game")
-			}
-			option1 = cfbdBet.HomeTeam
-			option2 = cfbdBet.AwayTeam
-			odds1 = *line.HomeMoneyline
-			odds2 = *line.AwayMoneyline
-			spreadValue = nil
-		} else {
-			var lineValue float64
-			if line.Spread != nil {
-				lineValue = *line.Spread
-			} else {
-				return fmt.Errorf("no spread available")
-			}
-			if lineValue == math.Trunc(lineValue) {
-				lineValue += 0.5
-			}
-
-			option1 = fmt.Sprintf("%s %s", cfbdBet.HomeTeam, common.FormatOdds(lineValue))
-			option2 = fmt.Sprintf("%s %s", cfbdBet.AwayTeam, common.FormatOdds(lineValue*-1))
-			odds1 = -110
-			odds2 = -110
-			spreadValue = &lineValue
-		}
-
-		loc, err := time.LoadLocation("America/New_York")
+		dbBet, err = newCFBBet(cfbdBet, line, betType, teamService.ForGuild(db, guild.GuildID))
 		if err != nil {
 			return err
 		}
-		t := cfbdBet.StartDate.In(loc)
-		formattedTime := t.Format("Mon 03:04 pm MST")
-
-		dbBet = models.Bet{
-			Description:   fmt.Sprintf("%s @ %s (%s)", cfbdBet.AwayTeam, cfbdBet.HomeTeam, formattedTime),
-			Option1:       option1,
-			Option2:       option2,
-			Odds1:         odds1,
-			Odds2:         odds2,
-			Active:        true,
-			GuildID:       guildID,
-			ChannelID:     i.ChannelID,
-			GameStartDate: &cfbdBet.StartDate,
-			CfbdID:        &cfbdBetID,
-			AdminCreated:  common.IsAdmin(s, i),
-			Spread:        spreadValue,
-		}
+		dbBet.GuildID = guildID
+		dbBet.ChannelID = i.ChannelID
+		dbBet.AdminCreated = common.IsAdmin(s, i)
 		db.Create(&dbBet)
+		if _, err := RecordLine(db, dbBet); err != nil {
+			log.Printf("Error recording opening line for bet %d: %v", dbBet.ID, err)
+		}
 	}
 
 	buttons := messageService.GetBetOnlyButtonsList(dbBet.Option1, dbBet.Option2, dbBet.ID)
 
-	betTypeLabel := "ATS"
-	if dbBet.Spread == nil {
-		betTypeLabel = "Moneyline"
-	}
-
 	embed := &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("📢 New CFB %s Bet Created (Will Auto Close & Resolve)", betTypeLabel),
-		Description: dbBet.Description,
+		Title:       fmt.Sprintf("📢 New CFB %s Bet Created (Will Auto Close & Resolve)", betTypeLabel(dbBet)),
+		Description: common.GameBetDescription(dbBet),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:  fmt.Sprintf("1️⃣ %s", dbBet.Option1),
@@ -576,7 +533,9 @@ func CreateCFBBetFromGameID(s *discordgo.Session, i *discordgo.InteractionCreate
 	return nil
 }
 
-func AutoCreateCFBBet(s *discordgo.Session, db *gorm.DB, guildId string, channelId, gameId string) error {
+// AutoCreateCFBBet posts a bet of betType on a game to channelId unless the
+// guild already has one open.
+func AutoCreateCFBBet(s *discordgo.Session, db *gorm.DB, guildId string, channelId string, gameId string, betType string) error {
 	guild, err := guildService.GetGuildInfo(s, db, guildId, channelId)
 	if err != nil {
 		return err
@@ -586,107 +545,103 @@ func AutoCreateCFBBet(s *discordgo.Session, db *gorm.DB, guildId string, channel
 	}
 
 	var dbBet models.Bet
-	result := db.
-		Where("cfbd_id = ? AND paid = 0 AND guild_id = ? AND spread IS NOT NULL", gameId, guildId).
-		Find(&dbBet)
+	result := whereBetType(db.Where("cfbd_id = ? AND paid = 0 AND guild_id = ? AND link = ''", gameId, guildId), betType).Find(&dbBet)
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
 
 	gameInt, _ := strconv.Atoi(gameId)
-	if result.RowsAffected == 0 {
-		cfbdBet, err := extService.GetCfbdBet(gameInt)
-		if err != nil {
-			return err
-		}
-
-		line, err := common.PickLine(cfbdBet.Lines)
-		if err != nil {
-			return err
-		}
-
-		cfbdBetID := strconv.Itoa(cfbdBet.ID)
+	cfbdBet, err := extService.GetCfbdBet(gameInt)
+	if err != nil {
+		return err
+	}
 
-		var lineValue float64
-		if line.Spread != nil {
-			lineValue = *line.Spread
-		} else {
-			common.SendError(s, nil, err, db)
-			return err
-		}
+	line, err := common.PickLine(cfbdBet.Lines)
+	if err != nil {
+		return err
+	}
 
-		if lineValue == math.Trunc(lineValue) {
-			lineValue += 0.5
-		}
+	dbBet, err = newCFBBet(cfbdBet, line, betType, teamService.ForGuild(db, guild.GuildID))
+	if err != nil {
+		return err
+	}
+	dbBet.GuildID = guildId
+	dbBet.ChannelID = channelId
+	dbBet.AdminCreated = true
+	db.Create(&dbBet)
+	if _, err := RecordLine(db, dbBet); err != nil {
+		log.Printf("Error recording opening line for bet %d: %v", dbBet.ID, err)
+	}
 
-		odds1 := -110
-		odds2 := -110
+	return postAutoCreatedBet(s, db, dbBet, fmt.Sprintf("CFB %s", betTypeLabel(dbBet)))
+}
 
-		loc, err := time.LoadLocation("America/New_York")
-		if err != nil {
-			return err
+// newCFBBet builds a game bet of betType from a CFBD game and line, with the
+// teams named as teams resolves them. The start time isn't written into the
+// description; GameBetDescription shows it in each member's own timezone. The
+// caller fills in the guild, channel and creator.
+func newCFBBet(cfbdBet external.CFBD_BettingLines, line *external.CFBD_Line, betType string, teams *teamService.Resolver) (models.Bet, error) {
+	homeTeam, awayTeam := cfbTeamNames(teams, cfbdBet)
+	var option1, option2 string
+	odds1, odds2 := -110, -110
+	var spreadValue *float64
+	var totalValue *float64
+
+	switch normalizeBetType(betType) {
+	case models.BetTypeMoneyline:
+		if line.HomeMoneyline == nil || line.AwayMoneyline == nil {
+			return models.Bet{}, fmt.Errorf("moneyline odds are not available for this game")
 		}
-		t := cfbdBet.StartDate.In(loc)
-		formattedTime := t.Format("Mon 03:04 pm MST")
-
-		dbBet = models.Bet{
-			Description:   fmt.Sprintf("%s @ %s (%s)", cfbdBet.AwayTeam, cfbdBet.HomeTeam, formattedTime),
-			Option1:       fmt.Sprintf("%s %s", cfbdBet.HomeTeam, common.FormatOdds(lineValue)),
-			Option2:       fmt.Sprintf("%s %s", cfbdBet.AwayTeam, common.FormatOdds(lineValue*-1)),
-			Odds1:         odds1,
-			Odds2:         odds2,
-			Active:        true,
-			GuildID:       guildId,
-			ChannelID:     channelId,
-			GameStartDate: &cfbdBet.StartDate,
-			CfbdID:        &cfbdBetID,
-			AdminCreated:  true,
-			Spread:        &lineValue,
+		option1 = homeTeam
+		option2 = awayTeam
+		odds1 = *line.HomeMoneyline
+		odds2 = *line.AwayMoneyline
+	case models.BetTypeTotal:
+		if line.OverUnder == nil {
+			return models.Bet{}, fmt.Errorf("over/under is not available for this game")
 		}
-		db.Create(&dbBet)
-
-		buttons := messageService.GetBetOnlyButtonsList(dbBet.Option1, dbBet.Option2, dbBet.ID)
-		embed := &discordgo.MessageEmbed{
-			Title:       "📢 New CFB Bet Created (Will Auto Close & Resolve)",
-			Description: dbBet.Description,
-			Fields: []*discordgo.MessageEmbedField{
-				{
-					Name:  fmt.Sprintf("1️⃣ %s", dbBet.Option1),
-					Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(dbBet.Odds1))),
-				},
-				{
-					Name:  fmt.Sprintf("2️⃣ %s", dbBet.Option2),
-					Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(dbBet.Odds2))),
-				},
-			},
-			Color: 0x3498db,
+		lineValue := *line.OverUnder
+		if lineValue == math.Trunc(lineValue) {
+			lineValue += 0.5
 		}
-
-		msg, err := s.ChannelMessageSendComplex(guild.BetChannelID, &discordgo.MessageSend{
-			Embeds: []*discordgo.MessageEmbed{embed},
-			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: buttons,
-				},
-			},
-		})
-		if err != nil {
-			return err
+		option1 = common.FormatTotalOption(1, lineValue)
+		option2 = common.FormatTotalOption(2, lineValue)
+		totalValue = &lineValue
+	default:
+		if line.Spread == nil {
+			return models.Bet{}, fmt.Errorf("no spread available")
 		}
-
-		if dbBet.MessageID != nil {
-			db.Create(&models.BetMessage{
-				Active:    true,
-				BetID:     dbBet.ID,
-				MessageID: &msg.ID,
-				ChannelID: msg.ChannelID,
-			})
-		} else {
-			dbBet.MessageID = &msg.ID
+		lineValue := *line.Spread
+		if lineValue == math.Trunc(lineValue) {
+			lineValue += 0.5
 		}
+		option1 = fmt.Sprintf("%s %s", homeTeam, common.FormatOdds(lineValue))
+		option2 = fmt.Sprintf("%s %s", awayTeam, common.FormatOdds(lineValue*-1))
+		spreadValue = &lineValue
+	}
+
+	cfbdBetID := strconv.Itoa(cfbdBet.ID)
+	startDate := cfbdBet.StartDate
+
+	return models.Bet{
+		Description:   fmt.Sprintf("%s @ %s", awayTeam, homeTeam),
+		Option1:       option1,
+		Option2:       option2,
+		Odds1:         odds1,
+		Odds2:         odds2,
+		Active:        true,
+		GameStartDate: &startDate,
+		CfbdID:        &cfbdBetID,
+		Sport:         models.SportCFB,
+		Spread:        spreadValue,
+		Total:         totalValue,
+	}, nil
+}
 
-		db.Save(&dbBet)
-	}
-
-	return nil
+// cfbTeamNames returns a CFBD game's home and away teams as teams names them.
+func cfbTeamNames(teams *teamService.Resolver, game external.CFBD_BettingLines) (string, string) {
+	return teams.CFBD(game.HomeTeamID, game.HomeTeam), teams.CFBD(game.AwayTeamID, game.AwayTeam)
 }
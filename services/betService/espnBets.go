@@ -0,0 +1,628 @@
+package betService
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/messageService"
+	"perfectOddsBot/services/teamService"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+var (
+	espnPaginatedOptionsMap = make(map[string][][]discordgo.SelectMenuOption)
+	espnPaginatedOptionsMu  sync.RWMutex
+)
+
+// ParseESPNGameStartTime parses the start time of an ESPN event.
+func ParseESPNGameStartTime(date string) (time.Time, error) {
+	layouts := []string{time.RFC3339, "2006-01-02T15:04Z"}
+	for _, layout := range layouts {
+		parsedTime, err := time.Parse(layout, date)
+		if err == nil {
+			return parsedTime, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse game start time: %s", date)
+}
+
+func isFutureESPNGame(startTime time.Time) bool {
+	return startTime.After(time.Now().UTC())
+}
+
+func espnTotalOdds(line *external.ESPN_Line) (int, int) {
+	overOdds := -110
+	underOdds := -110
+	if line.OverOdds != 0 {
+		overOdds = int(line.OverOdds)
+	}
+	if line.UnderOdds != 0 {
+		underOdds = int(line.UnderOdds)
+	}
+	return overOdds, underOdds
+}
+
+func GetESPNPaginatedOptions(sessionID string) ([][]discordgo.SelectMenuOption, bool) {
+	espnPaginatedOptionsMu.RLock()
+	defer espnPaginatedOptionsMu.RUnlock()
+	options, exists := espnPaginatedOptionsMap[sessionID]
+	return options, exists
+}
+
+func CleanupESPNPaginatedOptions(sessionID string) {
+	espnPaginatedOptionsMu.Lock()
+	defer espnPaginatedOptionsMu.Unlock()
+	delete(espnPaginatedOptionsMap, sessionID)
+}
+
+// CreateESPNBetSelector lists a league's upcoming games today, in the guild's
+// timezone, with lines to create a bet for.
+func CreateESPNBetSelector(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport) {
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+	if !guild.PremiumEnabled {
+		common.SendError(s, i, fmt.Errorf("Your server must have the premium subscription in order to enable this feature"), db)
+		return
+	}
+
+	loc := common.GuildLocation(*guild)
+	events, err := extService.GetESPNGamesOn(sport, time.Now(), loc)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	teams := teamService.ForGuild(db, i.GuildID)
+	var selectOptions []discordgo.SelectMenuOption
+	for _, event := range events {
+		gameStartTime, timeErr := ParseESPNGameStartTime(event.Date)
+		if timeErr != nil || !isFutureESPNGame(gameStartTime) {
+			continue
+		}
+		if len(event.Competitions) > 0 && event.Competitions[0].Status.Type.Name != "STATUS_FINAL" {
+			homeTeam := ""
+			awayTeam := ""
+			for _, competitor := range event.Competitions[0].Competitors {
+				if competitor.HomeAway == "home" {
+					homeTeam = teams.ESPN(sport.Key, competitor.Team)
+				}
+				if competitor.HomeAway == "away" {
+					awayTeam = teams.ESPN(sport.Key, competitor.Team)
+				}
+			}
+
+			eventID, err := strconv.Atoi(event.ID)
+			if err != nil {
+				continue
+			}
+
+			linesList, err := extService.GetESPNLines(sport, eventID)
+			if err != nil {
+				continue
+			}
+
+			line, lineErr := common.PickESPNLine(linesList)
+			if lineErr != nil {
+				continue
+			}
+
+			label := fmt.Sprintf("%s @ %s", awayTeam, homeTeam)
+			if len(label) > 100 {
+				label = label[:97] + "..."
+			}
+
+			description := fmt.Sprintf("%s - %s", common.FormatGameTime(gameStartTime, loc), line.Details)
+			if len(description) > 100 {
+				description = description[:97] + "..."
+			}
+
+			selectOptions = append(selectOptions, discordgo.SelectMenuOption{
+				Label:       label,
+				Value:       event.ID,
+				Description: description,
+				Emoji:       nil,
+				Default:     false,
+			})
+		}
+	}
+
+	if len(selectOptions) == 0 {
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "There are no games available to create bets for.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
+	sessionID := i.Interaction.ID
+
+	var paginatedOptions [][]discordgo.SelectMenuOption
+	minValues := 1
+	for i := 0; i < len(selectOptions); i += 25 {
+		end := i + 25
+		if end > len(selectOptions) {
+			end = len(selectOptions)
+		}
+		paginatedOptions = append(paginatedOptions, selectOptions[i:end])
+	}
+
+	espnPaginatedOptionsMu.Lock()
+	espnPaginatedOptionsMap[sessionID] = paginatedOptions
+	espnPaginatedOptionsMu.Unlock()
+
+	currentPage := 0
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Select a game (Page %d/%d):", currentPage+1, len(paginatedOptions)),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							MenuType:    discordgo.StringSelectMenu,
+							CustomID:    fmt.Sprintf("create_%s_bet_submit_%s", sport.Key, sessionID),
+							Placeholder: "Select a game",
+							MinValues:   &minValues,
+							MaxValues:   1,
+							Options:     paginatedOptions[currentPage],
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "Previous",
+							CustomID: fmt.Sprintf("create_%s_bet_previous_page_%d_%s", sport.Key, currentPage, sessionID),
+							Style:    discordgo.PrimaryButton,
+							Disabled: true,
+						},
+						discordgo.Button{
+							Label:    "Next",
+							CustomID: fmt.Sprintf("create_%s_bet_next_page_%d_%s", sport.Key, currentPage, sessionID),
+							Style:    discordgo.PrimaryButton,
+							Disabled: currentPage == len(paginatedOptions)-1,
+						},
+						discordgo.Button{
+							Label:    "Cancel",
+							CustomID: fmt.Sprintf("create_%s_bet_cancel_%s", sport.Key, sessionID),
+							Style:    discordgo.DangerButton,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+}
+
+func ShowESPNBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, betID int) error {
+	linesList, err := extService.GetESPNLines(sport, betID)
+	if err != nil {
+		return err
+	}
+
+	line, err := common.PickESPNLine(linesList)
+	if err != nil {
+		return err
+	}
+
+	espnID := strconv.Itoa(betID)
+	espnEvent, err := extService.GetESPNGame(sport, espnID)
+	if err != nil {
+		return err
+	}
+
+	teams := teamService.ForGuild(db, i.GuildID)
+	homeTeam := ""
+	awayTeam := ""
+	for _, competitor := range espnEvent.Competitions[0].Competitors {
+		if competitor.HomeAway == "home" {
+			homeTeam = teams.ESPN(sport.Key, competitor.Team)
+		}
+		if competitor.HomeAway == "away" {
+			awayTeam = teams.ESPN(sport.Key, competitor.Team)
+		}
+	}
+
+	spreadValue := line.Spread
+	homeSpreadOdds := -110
+	awaySpreadOdds := -110
+	if line.HomeTeamOdds.SpreadOdds != 0 {
+		homeSpreadOdds = int(line.HomeTeamOdds.SpreadOdds)
+	}
+	if line.AwayTeamOdds.SpreadOdds != 0 {
+		awaySpreadOdds = int(line.AwayTeamOdds.SpreadOdds)
+	}
+
+	moneylineAvailable := line.HomeTeamOdds.MoneyLine != 0 && line.AwayTeamOdds.MoneyLine != 0
+
+	var homeMoneyline, awayMoneyline int
+	if moneylineAvailable {
+		homeMoneyline = line.HomeTeamOdds.MoneyLine
+		awayMoneyline = line.AwayTeamOdds.MoneyLine
+	}
+
+	spreadDisplay := common.FormatOdds(spreadValue)
+	if spreadValue > 0 {
+		spreadDisplay = "+" + spreadDisplay
+	}
+
+	description := fmt.Sprintf("**%s @ %s**\n\nSelect the type of bet you want to create:", awayTeam, homeTeam)
+
+	atsField := fmt.Sprintf("**ATS (Against The Spread)**\n1️⃣ %s %s (Odds: %s)\n2️⃣ %s %s (Odds: %s)",
+		homeTeam, common.FormatOdds(spreadValue), common.FormatOdds(float64(homeSpreadOdds)),
+		awayTeam, common.FormatOdds(spreadValue*-1), common.FormatOdds(float64(awaySpreadOdds)))
+
+	embedFields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "📊 ATS Bet",
+			Value: atsField,
+		},
+	}
+
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Create ATS Bet",
+			CustomID: fmt.Sprintf("%s_bet_type_ats_%d", sport.Key, betID),
+			Style:    discordgo.PrimaryButton,
+		},
+	}
+	if moneylineAvailable {
+		moneylineField := fmt.Sprintf("**Moneyline**\n1️⃣ %s (Odds: %s)\n2️⃣ %s (Odds: %s)",
+			homeTeam, common.FormatOdds(float64(homeMoneyline)),
+			awayTeam, common.FormatOdds(float64(awayMoneyline)))
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "💰 Moneyline Bet",
+			Value: moneylineField,
+		})
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Create Moneyline Bet",
+			CustomID: fmt.Sprintf("%s_bet_type_ml_%d", sport.Key, betID),
+			Style:    discordgo.SuccessButton,
+		})
+	} else {
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "💰 Moneyline Bet",
+			Value: "No moneyline bet available",
+		})
+	}
+
+	if line.OverUnder != 0 {
+		overOdds, underOdds := espnTotalOdds(line)
+		totalField := fmt.Sprintf("**Over/Under (Combined Score)**\n1️⃣ %s (Odds: %s)\n2️⃣ %s (Odds: %s)",
+			common.FormatTotalOption(1, line.OverUnder), common.FormatOdds(float64(overOdds)),
+			common.FormatTotalOption(2, line.OverUnder), common.FormatOdds(float64(underOdds)))
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "🔢 Over/Under Bet",
+			Value: totalField,
+		})
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Create Over/Under Bet",
+			CustomID: fmt.Sprintf("%s_bet_type_total_%d", sport.Key, betID),
+			Style:    discordgo.SecondaryButton,
+		})
+	} else {
+		embedFields = append(embedFields, &discordgo.MessageEmbedField{
+			Name:  "🔢 Over/Under Bet",
+			Value: "No over/under bet available",
+		})
+	}
+
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Cancel",
+		CustomID: fmt.Sprintf("%s_bet_type_cancel_%d", sport.Key, betID),
+		Style:    discordgo.DangerButton,
+	})
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Select Bet Type",
+		Description: description,
+		Fields:      embedFields,
+		Color:       0x3498db,
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: buttons,
+				},
+			},
+		},
+	})
+
+	return err
+}
+
+func CreateESPNBetFromGameID(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, betID int, betType string) error {
+	guildID := i.GuildID
+
+	guild, err := guildService.GetGuildInfo(s, db, guildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+	if !guild.PremiumEnabled {
+		return fmt.Errorf("Your server must have the premium subscription in order to enable this feature")
+	}
+
+	var dbBet models.Bet
+	result := whereBetType(db.Where("espn_id = ? AND sport = ? AND paid = 0 AND guild_id = ? AND link = ''", betID, sport.Key, i.GuildID), betType).Find(&dbBet)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		linesList, err := extService.GetESPNLines(sport, betID)
+		if err != nil {
+			return err
+		}
+
+		line, err := common.PickESPNLine(linesList)
+		if err != nil {
+			return err
+		}
+
+		espnID := strconv.Itoa(betID)
+		espnEvent, err := extService.GetESPNGame(sport, espnID)
+		if err != nil {
+			return err
+		}
+
+		gameStartTime, err := ParseESPNGameStartTime(espnEvent.Date)
+		if err != nil {
+			return err
+		}
+		if !isFutureESPNGame(gameStartTime) {
+			return fmt.Errorf("cannot create a bet for a game that has already started or finished")
+		}
+		teams := teamService.ForGuild(db, guildID)
+		homeTeam := ""
+		awayTeam := ""
+		for _, competitor := range espnEvent.Competitions[0].Competitors {
+			if competitor.HomeAway == "home" {
+				homeTeam = teams.ESPN(sport.Key, competitor.Team)
+			}
+			if competitor.HomeAway == "away" {
+				awayTeam = teams.ESPN(sport.Key, competitor.Team)
+			}
+		}
+
+		dbBet, err = newESPNBet(sport, espnEvent, line, homeTeam, awayTeam, gameStartTime, betType)
+		if err != nil {
+			return err
+		}
+		dbBet.GuildID = guildID
+		dbBet.ChannelID = i.ChannelID
+		dbBet.AdminCreated = common.IsAdmin(s, i)
+		db.Create(&dbBet)
+		if _, err := RecordLine(db, dbBet); err != nil {
+			log.Printf("Error recording opening line for bet %d: %v", dbBet.ID, err)
+		}
+	}
+
+	buttons := messageService.GetBetOnlyButtonsList(dbBet.Option1, dbBet.Option2, dbBet.ID)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📢 New %s %s Bet Created (Will Auto Close & Resolve)", sport.Name, betTypeLabel(dbBet)),
+		Description: common.GameBetDescription(dbBet),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  fmt.Sprintf("1️⃣ %s", dbBet.Option1),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(dbBet.Odds1))),
+			},
+			{
+				Name:  fmt.Sprintf("2️⃣ %s", dbBet.Option2),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(dbBet.Odds2))),
+			},
+		},
+		Color: 0x3498db,
+	}
+
+	interactionData := discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: buttons,
+			},
+		},
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &interactionData,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.InteractionResponse(i.Interaction)
+	if err != nil {
+		return err
+	}
+
+	if dbBet.MessageID != nil {
+		db.Create(&models.BetMessage{
+			Active:    true,
+			BetID:     dbBet.ID,
+			MessageID: &msg.ID,
+			ChannelID: msg.ChannelID,
+		})
+	} else {
+		dbBet.MessageID = &msg.ID
+	}
+
+	if common.IsAdmin(s, i) {
+		dbBet.AdminCreated = common.IsAdmin(s, i)
+	}
+
+	db.Save(&dbBet)
+
+	return nil
+}
+
+// AutoCreateESPNBet posts a bet of betType on a game to channelId unless the
+// guild already has one open.
+func AutoCreateESPNBet(s *discordgo.Session, db *gorm.DB, sport extService.Sport, guildId string, channelId string, gameId string, betType string) error {
+	guild, err := guildService.GetGuildInfo(s, db, guildId, channelId)
+	if err != nil {
+		return err
+	}
+	if !guild.PremiumEnabled {
+		return fmt.Errorf("Your server must have the premium subscription in order to enable this feature")
+	}
+
+	var dbBet models.Bet
+	result := whereBetType(db.Where("espn_id = ? AND sport = ? AND paid = 0 AND guild_id = ? AND link = ''", gameId, sport.Key, guildId), betType).Find(&dbBet)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	gameInt, _ := strconv.Atoi(gameId)
+	linesList, err := extService.GetESPNLines(sport, gameInt)
+	if err != nil {
+		return err
+	}
+
+	line, err := common.PickESPNLine(linesList)
+	if err != nil {
+		return err
+	}
+
+	espnEvent, err := extService.GetESPNGame(sport, gameId)
+	if err != nil {
+		return err
+	}
+	teams := teamService.ForGuild(db, guildId)
+	homeTeam := ""
+	awayTeam := ""
+	for _, competitor := range espnEvent.Competitions[0].Competitors {
+		isHome := false
+		if line.HomeTeamOdds.Team.Ref != "" {
+			if strings.Contains(line.HomeTeamOdds.Team.Ref, fmt.Sprintf("/teams/%s?", competitor.ID)) {
+				isHome = true
+			}
+		} else if competitor.HomeAway == "home" {
+			isHome = true
+		}
+
+		if isHome {
+			homeTeam = teams.ESPN(sport.Key, competitor.Team)
+		} else {
+			awayTeam = teams.ESPN(sport.Key, competitor.Team)
+		}
+	}
+
+	utcTime, err := ParseESPNGameStartTime(espnEvent.Date)
+	if err != nil {
+		return fmt.Errorf("err parsing time: %v", err)
+	}
+
+	dbBet, err = newESPNBet(sport, espnEvent, line, homeTeam, awayTeam, utcTime, betType)
+	if err != nil {
+		return err
+	}
+	dbBet.GuildID = guildId
+	dbBet.ChannelID = channelId
+	dbBet.AdminCreated = true
+	db.Create(&dbBet)
+	if _, err := RecordLine(db, dbBet); err != nil {
+		log.Printf("Error recording opening line for bet %d: %v", dbBet.ID, err)
+	}
+
+	return postAutoCreatedBet(s, db, dbBet, fmt.Sprintf("%s %s", sport.Name, betTypeLabel(dbBet)))
+}
+
+// newESPNBet builds a game bet of betType from an ESPN event and line. The
+// start time isn't written into the description; GameBetDescription shows it in
+// each member's own timezone. The caller fills in the guild, channel and
+// creator.
+func newESPNBet(sport extService.Sport, espnEvent external.ESPN_Event, line *external.ESPN_Line, homeTeam string, awayTeam string, startTime time.Time, betType string) (models.Bet, error) {
+	var option1, option2 string
+	var odds1, odds2 int
+	var spreadValue *float64
+	var totalValue *float64
+
+	switch normalizeBetType(betType) {
+	case models.BetTypeMoneyline:
+		if line.HomeTeamOdds.MoneyLine == 0 || line.AwayTeamOdds.MoneyLine == 0 {
+			return models.Bet{}, fmt.Errorf("moneyline odds are not available for this game")
+		}
+		option1 = homeTeam
+		option2 = awayTeam
+		odds1 = line.HomeTeamOdds.MoneyLine
+		odds2 = line.AwayTeamOdds.MoneyLine
+	case models.BetTypeTotal:
+		if line.OverUnder == 0 {
+			return models.Bet{}, fmt.Errorf("over/under is not available for this game")
+		}
+		lineValue := line.OverUnder
+		if lineValue == math.Trunc(lineValue) {
+			lineValue += 0.5
+		}
+		option1 = common.FormatTotalOption(1, lineValue)
+		option2 = common.FormatTotalOption(2, lineValue)
+		odds1, odds2 = espnTotalOdds(line)
+		totalValue = &lineValue
+	default:
+		lineValue := line.Spread
+		if lineValue == math.Trunc(lineValue) {
+			lineValue += 0.5
+		}
+		option1 = fmt.Sprintf("%s %s", homeTeam, common.FormatOdds(lineValue))
+		option2 = fmt.Sprintf("%s %s", awayTeam, common.FormatOdds(lineValue*-1))
+		odds1, odds2 = -110, -110
+		if line.HomeTeamOdds.SpreadOdds != 0 {
+			odds1 = int(line.HomeTeamOdds.SpreadOdds)
+		}
+		if line.AwayTeamOdds.SpreadOdds != 0 {
+			odds2 = int(line.AwayTeamOdds.SpreadOdds)
+		}
+		spreadValue = &lineValue
+	}
+
+	espnID := espnEvent.ID
+
+	return models.Bet{
+		Description:   fmt.Sprintf("%s @ %s\n- Broadcast: %s", awayTeam, homeTeam, espnEvent.Competitions[0].Broadcast),
+		Option1:       option1,
+		Option2:       option2,
+		Odds1:         odds1,
+		Odds2:         odds2,
+		Active:        true,
+		GameStartDate: &startTime,
+		EspnID:        &espnID,
+		Sport:         sport.Key,
+		Spread:        spreadValue,
+		Total:         totalValue,
+	}, nil
+}
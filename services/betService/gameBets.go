@@ -0,0 +1,82 @@
+package betService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/messageService"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// normalizeBetType maps the bet type names used by the bet type buttons and
+// subscriptions onto the models.BetType constants. Anything unrecognised is a
+// spread bet.
+func normalizeBetType(betType string) string {
+	switch betType {
+	case "moneyline", models.BetTypeMoneyline:
+		return models.BetTypeMoneyline
+	case models.BetTypeTotal:
+		return models.BetTypeTotal
+	}
+	return models.BetTypeSpread
+}
+
+// whereBetType narrows a game bet query to bets of betType.
+func whereBetType(query *gorm.DB, betType string) *gorm.DB {
+	switch normalizeBetType(betType) {
+	case models.BetTypeMoneyline:
+		return query.Where("spread IS NULL AND total IS NULL")
+	case models.BetTypeTotal:
+		return query.Where("total IS NOT NULL")
+	}
+	return query.Where("spread IS NOT NULL")
+}
+
+// betTypeLabel names a game bet's type for embeds.
+func betTypeLabel(bet models.Bet) string {
+	if bet.Total != nil {
+		return "Over/Under"
+	}
+	if bet.Spread == nil {
+		return "Moneyline"
+	}
+	return "ATS"
+}
+
+// postAutoCreatedBet posts a bet the scheduler created to the bet's channel and
+// records the message on it.
+func postAutoCreatedBet(s *discordgo.Session, db *gorm.DB, bet models.Bet, label string) error {
+	buttons := messageService.GetBetOnlyButtonsList(bet.Option1, bet.Option2, bet.ID)
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📢 New %s Bet Created (Will Auto Close & Resolve)", label),
+		Description: common.GameBetDescription(bet),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  fmt.Sprintf("1️⃣ %s", bet.Option1),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds1))),
+			},
+			{
+				Name:  fmt.Sprintf("2️⃣ %s", bet.Option2),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds2))),
+			},
+		},
+		Color: 0x3498db,
+	}
+
+	msg, err := s.ChannelMessageSendComplex(bet.ChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: buttons,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	bet.MessageID = &msg.ID
+	return db.Save(&bet).Error
+}
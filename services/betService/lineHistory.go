@@ -0,0 +1,325 @@
+package betService
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+
+	"gorm.io/gorm"
+)
+
+// LineQuote is the line a data provider currently offers for a game. Spread is
+// the home team's spread, matching Option1 on game bets.
+type LineQuote struct {
+	Spread         *float64
+	Total          *float64
+	HomeMoneyline  *int
+	AwayMoneyline  *int
+	HomeSpreadOdds *int
+	AwaySpreadOdds *int
+	OverOdds       *int
+	UnderOdds      *int
+}
+
+// CFBDQuote is the quote for a CFBD line. CFBD has no prices for spreads or
+// totals, so those stay at -110.
+func CFBDQuote(line *external.CFBD_Line) LineQuote {
+	return LineQuote{
+		Spread:        line.Spread,
+		Total:         line.OverUnder,
+		HomeMoneyline: line.HomeMoneyline,
+		AwayMoneyline: line.AwayMoneyline,
+	}
+}
+
+// ESPNQuote is the quote for an ESPN line. ESPN reports a missing value as 0.
+func ESPNQuote(line *external.ESPN_Line) LineQuote {
+	quote := LineQuote{
+		HomeSpreadOdds: nonZeroOdds(line.HomeTeamOdds.SpreadOdds),
+		AwaySpreadOdds: nonZeroOdds(line.AwayTeamOdds.SpreadOdds),
+		OverOdds:       nonZeroOdds(line.OverOdds),
+		UnderOdds:      nonZeroOdds(line.UnderOdds),
+	}
+	spread := line.Spread
+	quote.Spread = &spread
+	if line.OverUnder != 0 {
+		total := line.OverUnder
+		quote.Total = &total
+	}
+	if line.HomeTeamOdds.MoneyLine != 0 && line.AwayTeamOdds.MoneyLine != 0 {
+		home, away := line.HomeTeamOdds.MoneyLine, line.AwayTeamOdds.MoneyLine
+		quote.HomeMoneyline = &home
+		quote.AwayMoneyline = &away
+	}
+	return quote
+}
+
+func nonZeroOdds(odds float64) *int {
+	if odds == 0 {
+		return nil
+	}
+	value := int(odds)
+	return &value
+}
+
+func oddsOrStandard(odds *int) int {
+	if odds == nil {
+		return -110
+	}
+	return *odds
+}
+
+// halfPoint moves a whole-number line onto the hook so a game bet can't push,
+// the same way game bets are created.
+func halfPoint(line float64) float64 {
+	if line == math.Trunc(line) {
+		return line + 0.5
+	}
+	return line
+}
+
+// ApplyQuote updates a game bet's line, option names and odds from a quote and
+// reports whether anything changed. Spread bets take the spread, total bets the
+// total and moneyline bets the moneylines; a quote without the bet's market
+// leaves it alone.
+func ApplyQuote(bet *models.Bet, quote LineQuote) bool {
+	before := *bet
+	switch {
+	case bet.Total != nil:
+		if quote.Total == nil {
+			return false
+		}
+		total := halfPoint(*quote.Total)
+		bet.Total = &total
+		bet.Option1 = common.FormatTotalOption(1, total)
+		bet.Option2 = common.FormatTotalOption(2, total)
+		bet.Odds1 = oddsOrStandard(quote.OverOdds)
+		bet.Odds2 = oddsOrStandard(quote.UnderOdds)
+		return *before.Total != total || before.Odds1 != bet.Odds1 || before.Odds2 != bet.Odds2
+	case bet.Spread != nil:
+		if quote.Spread == nil {
+			return false
+		}
+		spread := halfPoint(*quote.Spread)
+		bet.Spread = &spread
+		bet.Option1 = fmt.Sprintf("%s %s", common.GetSchoolName(bet.Option1), common.FormatOdds(spread))
+		bet.Option2 = fmt.Sprintf("%s %s", common.GetSchoolName(bet.Option2), common.FormatOdds(spread*-1))
+		bet.Odds1 = oddsOrStandard(quote.HomeSpreadOdds)
+		bet.Odds2 = oddsOrStandard(quote.AwaySpreadOdds)
+		return *before.Spread != spread || before.Odds1 != bet.Odds1 || before.Odds2 != bet.Odds2
+	default:
+		if quote.HomeMoneyline == nil || quote.AwayMoneyline == nil {
+			return false
+		}
+		bet.Odds1 = *quote.HomeMoneyline
+		bet.Odds2 = *quote.AwayMoneyline
+		return before.Odds1 != bet.Odds1 || before.Odds2 != bet.Odds2
+	}
+}
+
+// RecordLine writes the bet's current line to its history.
+func RecordLine(db *gorm.DB, bet models.Bet) (models.LineHistory, error) {
+	row := models.LineHistory{
+		BetID:  bet.ID,
+		Spread: bet.Spread,
+		Total:  bet.Total,
+		Odds1:  bet.Odds1,
+		Odds2:  bet.Odds2,
+	}
+	err := db.Create(&row).Error
+	return row, err
+}
+
+// LatestLine returns the most recent line recorded for a bet, and false if none
+// has been.
+func LatestLine(db *gorm.DB, betID uint) (models.LineHistory, bool, error) {
+	var row models.LineHistory
+	err := db.Where("bet_id = ?", betID).Order("created_at DESC, id DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return row, false, nil
+	}
+	return row, err == nil, err
+}
+
+// LatestLines returns the most recent line for each of the bets.
+func LatestLines(db *gorm.DB, betIDs []uint) (map[uint]models.LineHistory, error) {
+	lines := make(map[uint]models.LineHistory)
+	if len(betIDs) == 0 {
+		return lines, nil
+	}
+	var rows []models.LineHistory
+	if err := db.Where("bet_id IN ?", betIDs).Order("created_at ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		lines[row.BetID] = row
+	}
+	return lines, nil
+}
+
+// LineMove describes a line that moved far enough to alert the guild.
+type LineMove struct {
+	From models.LineHistory
+	To   models.LineHistory
+}
+
+// RefreshLine applies a quote to a game bet, saves it and records the new line
+// when it changed. It returns whether the bet changed and, when the line has
+// moved past the guild's thresholds since the last alert (or since it opened),
+// the movement to alert on.
+func RefreshLine(db *gorm.DB, bet *models.Bet, quote LineQuote, guild models.Guild) (bool, *LineMove, error) {
+	if _, found, err := LatestLine(db, bet.ID); err != nil {
+		return false, nil, err
+	} else if !found {
+		// Bets created before line history have no opening line yet.
+		if _, err := RecordLine(db, *bet); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if !ApplyQuote(bet, quote) {
+		return false, nil, nil
+	}
+	err := db.Model(&models.Bet{}).Where("id = ?", bet.ID).Updates(map[string]interface{}{
+		"option1": bet.Option1,
+		"option2": bet.Option2,
+		"odds1":   bet.Odds1,
+		"odds2":   bet.Odds2,
+		"spread":  bet.Spread,
+		"total":   bet.Total,
+	}).Error
+	if err != nil {
+		return false, nil, err
+	}
+
+	current, err := RecordLine(db, *bet)
+	if err != nil {
+		return true, nil, err
+	}
+
+	var baseline models.LineHistory
+	err = db.Where("bet_id = ? AND id <> ?", bet.ID, current.ID).Order("alerted DESC, created_at DESC, id DESC").First(&baseline).Error
+	if err != nil {
+		return true, nil, err
+	}
+	if !baseline.Alerted {
+		if err := db.Where("bet_id = ?", bet.ID).Order("created_at ASC, id ASC").First(&baseline).Error; err != nil {
+			return true, nil, err
+		}
+	}
+
+	if !LineMovedPast(baseline, current, guild) {
+		return true, nil, nil
+	}
+	if err := db.Model(&current).Update("alerted", true).Error; err != nil {
+		return true, nil, err
+	}
+	return true, &LineMove{From: baseline, To: current}, nil
+}
+
+// LineMovedPast reports whether a line has moved from one observation to
+// another by at least the guild's threshold: points for spreads and totals, and
+// cents of American odds for moneylines. A zero threshold turns that alert off.
+func LineMovedPast(from models.LineHistory, to models.LineHistory, guild models.Guild) bool {
+	switch {
+	case from.Total != nil && to.Total != nil:
+		return guild.LineAlertThreshold > 0 && math.Abs(*to.Total-*from.Total) >= guild.LineAlertThreshold
+	case from.Spread != nil && to.Spread != nil:
+		return guild.LineAlertThreshold > 0 && math.Abs(*to.Spread-*from.Spread) >= guild.LineAlertThreshold
+	default:
+		return guild.LineAlertOddsThreshold > 0 && absInt(oddsCents(to.Odds1)-oddsCents(from.Odds1)) >= guild.LineAlertOddsThreshold
+	}
+}
+
+// oddsCents puts American odds on a continuous scale, so -105 to +105 is a
+// move of 10 cents rather than 210.
+func oddsCents(odds int) int {
+	if odds < 0 {
+		return odds + 100
+	}
+	return odds - 100
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// impliedProbability is the chance of winning American odds imply.
+func impliedProbability(odds int) float64 {
+	if odds < 0 {
+		return float64(-odds) / float64(-odds+100)
+	}
+	return 100 / float64(odds+100)
+}
+
+// ClosingLineValue compares the line an entry was placed at with a later line
+// for the same bet. It is positive when the entry beat that line: points for
+// spreads and totals, and percentage points of implied probability for
+// moneylines. It returns false when the entry has no recorded line to compare.
+func ClosingLineValue(entry models.BetEntry, closing models.LineHistory) (float64, string, bool) {
+	switch {
+	case entry.Total != nil && closing.Total != nil:
+		if entry.Option == 1 {
+			return *closing.Total - *entry.Total, "pts", true
+		}
+		return *entry.Total - *closing.Total, "pts", true
+	case entry.Spread != nil && closing.Spread != nil:
+		if entry.Option == 1 {
+			return *entry.Spread - *closing.Spread, "pts", true
+		}
+		return *closing.Spread - *entry.Spread, "pts", true
+	case entry.Odds != nil && *entry.Odds != 0:
+		closingOdds := closing.Odds1
+		if entry.Option == 2 {
+			closingOdds = closing.Odds2
+		}
+		if closingOdds == 0 {
+			return 0, "", false
+		}
+		return (impliedProbability(closingOdds) - impliedProbability(*entry.Odds)) * 100, "%", true
+	}
+	return 0, "", false
+}
+
+// FormatEntryLine describes the line an entry's option has in a line history
+// row: the option's spread or total, or its price for a moneyline.
+func FormatEntryLine(entry models.BetEntry, line models.LineHistory) string {
+	switch {
+	case entry.Total != nil && line.Total != nil:
+		return common.FormatTotalOption(entry.Option, *line.Total)
+	case entry.Spread != nil && line.Spread != nil:
+		if entry.Option == 1 {
+			return common.FormatOdds(*line.Spread)
+		}
+		return common.FormatOdds(*line.Spread * -1)
+	}
+	if entry.Option == 2 {
+		return common.FormatOdds(float64(line.Odds2))
+	}
+	return common.FormatOdds(float64(line.Odds1))
+}
+
+// FormatLine describes a line history row for a game bet, naming the teams from
+// the bet's options.
+func FormatLine(bet models.Bet, line models.LineHistory) string {
+	home := common.GetSchoolName(bet.Option1)
+	away := common.GetSchoolName(bet.Option2)
+	switch {
+	case line.Total != nil:
+		return fmt.Sprintf("%s (%s) / %s (%s)",
+			common.FormatTotalOption(1, *line.Total), common.FormatOdds(float64(line.Odds1)),
+			common.FormatTotalOption(2, *line.Total), common.FormatOdds(float64(line.Odds2)))
+	case line.Spread != nil:
+		return fmt.Sprintf("%s %s (%s) / %s %s (%s)",
+			home, common.FormatOdds(*line.Spread), common.FormatOdds(float64(line.Odds1)),
+			away, common.FormatOdds(*line.Spread*-1), common.FormatOdds(float64(line.Odds2)))
+	}
+	return fmt.Sprintf("%s %s / %s %s", home, common.FormatOdds(float64(line.Odds1)), away, common.FormatOdds(float64(line.Odds2)))
+}
@@ -0,0 +1,117 @@
+package betService
+
+import (
+	"math"
+	"perfectOddsBot/models"
+	"testing"
+)
+
+func TestApplyQuote(t *testing.T) {
+	t.Run("spread bet takes the new spread on the hook", func(t *testing.T) {
+		bet := models.Bet{Option1: "Alabama -3.5", Option2: "Auburn +3.5", Odds1: -110, Odds2: -110, Spread: floatPtr(-3.5)}
+		changed := ApplyQuote(&bet, LineQuote{Spread: floatPtr(-6)})
+
+		assertEqual(t, true, changed, "changed")
+		assertEqual(t, -5.5, *bet.Spread, "spread")
+		assertEqual(t, "Alabama -5.5", bet.Option1, "option1")
+		assertEqual(t, "Auburn +5.5", bet.Option2, "option2")
+		assertEqual(t, -110, bet.Odds1, "odds1")
+	})
+
+	t.Run("unchanged line reports no change", func(t *testing.T) {
+		bet := models.Bet{Option1: "Over 45.5", Option2: "Under 45.5", Odds1: -110, Odds2: -110, Total: floatPtr(45.5)}
+		changed := ApplyQuote(&bet, LineQuote{Total: floatPtr(45.5)})
+
+		assertEqual(t, false, changed, "changed")
+	})
+
+	t.Run("total bet ignores the spread", func(t *testing.T) {
+		bet := models.Bet{Option1: "Over 45.5", Option2: "Under 45.5", Odds1: -110, Odds2: -110, Total: floatPtr(45.5)}
+		changed := ApplyQuote(&bet, LineQuote{Spread: floatPtr(-3), Total: floatPtr(47.5), OverOdds: intPtr(-120)})
+
+		assertEqual(t, true, changed, "changed")
+		assertEqual(t, 47.5, *bet.Total, "total")
+		assertEqual(t, "Over 47.5", bet.Option1, "option1")
+		assertEqual(t, -120, bet.Odds1, "odds1")
+		assertEqual(t, -110, bet.Odds2, "odds2")
+	})
+
+	t.Run("moneyline bet takes the moneylines", func(t *testing.T) {
+		bet := models.Bet{Option1: "Alabama", Option2: "Auburn", Odds1: -150, Odds2: 130}
+		changed := ApplyQuote(&bet, LineQuote{Spread: floatPtr(-7.5), HomeMoneyline: intPtr(-200), AwayMoneyline: intPtr(170)})
+
+		assertEqual(t, true, changed, "changed")
+		assertEqual(t, -200, bet.Odds1, "odds1")
+		assertEqual(t, 170, bet.Odds2, "odds2")
+		assertEqual(t, "Alabama", bet.Option1, "option1")
+	})
+}
+
+func TestLineMovedPast(t *testing.T) {
+	guild := models.Guild{LineAlertThreshold: 1.5, LineAlertOddsThreshold: 25}
+
+	assertEqual(t, true, LineMovedPast(models.LineHistory{Spread: floatPtr(-3.5)}, models.LineHistory{Spread: floatPtr(-5.5)}, guild), "spread moved 2")
+	assertEqual(t, false, LineMovedPast(models.LineHistory{Spread: floatPtr(-3.5)}, models.LineHistory{Spread: floatPtr(-4.5)}, guild), "spread moved 1")
+	assertEqual(t, false, LineMovedPast(models.LineHistory{Odds1: -105}, models.LineHistory{Odds1: 105}, guild), "moneyline crossed even")
+	assertEqual(t, true, LineMovedPast(models.LineHistory{Odds1: -110}, models.LineHistory{Odds1: -140}, guild), "moneyline moved 30 cents")
+
+	guild.LineAlertThreshold = 0
+	assertEqual(t, false, LineMovedPast(models.LineHistory{Total: floatPtr(40.5)}, models.LineHistory{Total: floatPtr(50.5)}, guild), "alerts off")
+}
+
+func TestClosingLineValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   models.BetEntry
+		closing models.LineHistory
+		value   float64
+		unit    string
+	}{
+		{
+			name:    "home spread beat the close",
+			entry:   models.BetEntry{Option: 1, Spread: floatPtr(-3.5)},
+			closing: models.LineHistory{Spread: floatPtr(-5.5)},
+			value:   2,
+			unit:    "pts",
+		},
+		{
+			name:    "away spread lost to the close",
+			entry:   models.BetEntry{Option: 2, Spread: floatPtr(-3.5)},
+			closing: models.LineHistory{Spread: floatPtr(-5.5)},
+			value:   -2,
+			unit:    "pts",
+		},
+		{
+			name:    "under beat the close",
+			entry:   models.BetEntry{Option: 2, Total: floatPtr(50.5)},
+			closing: models.LineHistory{Total: floatPtr(48.5)},
+			value:   2,
+			unit:    "pts",
+		},
+		{
+			name:    "moneyline shortened after the bet",
+			entry:   models.BetEntry{Option: 2, Odds: intPtr(150)},
+			closing: models.LineHistory{Odds1: -140, Odds2: 120},
+			value:   5.45,
+			unit:    "%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, unit, ok := ClosingLineValue(tt.entry, tt.closing)
+			assertEqual(t, true, ok, "ok")
+			assertEqual(t, tt.unit, unit, "unit")
+			if math.Abs(value-tt.value) > 0.01 {
+				t.Errorf("value: expected %v, got %v", tt.value, value)
+			}
+		})
+	}
+
+	_, _, ok := ClosingLineValue(models.BetEntry{Option: 1}, models.LineHistory{Odds1: -110})
+	assertEqual(t, false, ok, "entry without a recorded line")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,229 @@
+package betService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/teamService"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// linkedGame is the game a custom bet is linked to, with its teams named as the
+// scheduler names them when grading.
+type linkedGame struct {
+	CfbdID    *string
+	EspnID    *string
+	Sport     string
+	StartDate time.Time
+	HomeTeam  string
+	AwayTeam  string
+}
+
+// LinkCustomBet links a custom bet to a CFBD or ESPN game, so it locks at
+// kickoff and, for a team winning or a total going over, resolves itself from
+// the final score.
+func LinkCustomBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		respondLinkBet(s, i, db, "You are not authorized to use this command.", true)
+		return
+	}
+
+	var betID uint
+	var sportKey, outcome, teamName string
+	var gameID int64
+	var total *float64
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "bet-id":
+			betID = uint(opt.IntValue())
+		case "sport":
+			sportKey = opt.StringValue()
+		case "game-id":
+			gameID = opt.IntValue()
+		case "outcome":
+			outcome = opt.StringValue()
+		case "team":
+			teamName = strings.TrimSpace(opt.StringValue())
+		case "total":
+			value := opt.FloatValue()
+			total = &value
+		}
+	}
+
+	var bet models.Bet
+	result := db.Preload("Options").First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID)
+	if result.Error != nil || bet.ID == 0 {
+		respondLinkBet(s, i, db, "Bet not found.", true)
+		return
+	}
+
+	if err := validateBetLink(bet, outcome, teamName, total); err != nil {
+		respondLinkBet(s, i, db, err.Error(), true)
+		return
+	}
+
+	game, err := fetchLinkedGame(sportKey, gameID)
+	if err != nil {
+		respondLinkBet(s, i, db, fmt.Sprintf("Couldn't find that game: %v", err), true)
+		return
+	}
+	if !game.StartDate.After(time.Now()) {
+		respondLinkBet(s, i, db, "That game has already started.", true)
+		return
+	}
+
+	linkTeam := ""
+	if outcome == models.LinkTeamWins {
+		teams := teamService.ForGuild(db, i.GuildID)
+		switch {
+		case teams.Same(teamName, game.HomeTeam):
+			linkTeam = game.HomeTeam
+		case teams.Same(teamName, game.AwayTeam):
+			linkTeam = game.AwayTeam
+		default:
+			respondLinkBet(s, i, db, fmt.Sprintf("%s isn't playing in %s @ %s.", teamName, game.AwayTeam, game.HomeTeam), true)
+			return
+		}
+	}
+
+	linkBet(&bet, game, outcome, linkTeam, total)
+	if err := db.Omit("Options").Save(&bet).Error; err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	content := fmt.Sprintf("Bet #%d '%s' is linked to %s @ %s. It locks %s and %s.",
+		bet.ID, bet.Description, game.AwayTeam, game.HomeTeam, common.DiscordTimestamp(*bet.LockAt, "F"), linkResolution(bet))
+	respondLinkBet(s, i, db, content, false)
+}
+
+// validateBetLink checks that a bet can be linked for outcome. Outcomes graded
+// from the score need a bet with exactly two options: the outcome happening,
+// then not.
+func validateBetLink(bet models.Bet, outcome string, teamName string, total *float64) error {
+	if bet.Paid || bet.Voided {
+		return fmt.Errorf("This bet has already been resolved.")
+	}
+	if bet.Link == "" && (bet.CfbdID != nil || bet.EspnID != nil) {
+		return fmt.Errorf("Only custom bets can be linked to a game; game bets already follow theirs.")
+	}
+
+	switch outcome {
+	case models.LinkManual:
+		return nil
+	case models.LinkTeamWins:
+		if teamName == "" {
+			return fmt.Errorf("A team is required to grade the bet on a team winning.")
+		}
+	case models.LinkTotalOver:
+		if total == nil || *total <= 0 {
+			return fmt.Errorf("A total is required to grade the bet on the total going over.")
+		}
+	default:
+		return fmt.Errorf("Unknown outcome %q.", outcome)
+	}
+
+	if len(common.GetBetOptions(bet)) != 2 {
+		return fmt.Errorf("Only bets with two options can be graded from the score. Use the manual outcome to just lock it at kickoff.")
+	}
+	return nil
+}
+
+// fetchLinkedGame finds a CFBD game, or an ESPN event of another sport.
+func fetchLinkedGame(sportKey string, gameID int64) (linkedGame, error) {
+	teams := teamService.Shared()
+	id := strconv.FormatInt(gameID, 10)
+
+	if sportKey == models.SportCFB {
+		game, err := extService.GetCfbdBet(int(gameID))
+		if err != nil {
+			return linkedGame{}, err
+		}
+		homeTeam, awayTeam := cfbTeamNames(teams, game)
+		return linkedGame{
+			CfbdID:    &id,
+			Sport:     models.SportCFB,
+			StartDate: game.StartDate,
+			HomeTeam:  homeTeam,
+			AwayTeam:  awayTeam,
+		}, nil
+	}
+
+	sport, err := extService.ESPNSport(sportKey)
+	if err != nil {
+		return linkedGame{}, err
+	}
+	event, err := extService.GetESPNGame(sport, id)
+	if err != nil {
+		return linkedGame{}, err
+	}
+	startDate, err := ParseESPNGameStartTime(event.Date)
+	if err != nil {
+		return linkedGame{}, err
+	}
+
+	game := linkedGame{EspnID: &id, Sport: sport.Key, StartDate: startDate}
+	if len(event.Competitions) > 0 {
+		for _, competitor := range event.Competitions[0].Competitors {
+			if competitor.HomeAway == "home" {
+				game.HomeTeam = teams.ESPN(sport.Key, competitor.Team)
+			} else {
+				game.AwayTeam = teams.ESPN(sport.Key, competitor.Team)
+			}
+		}
+	}
+	return game, nil
+}
+
+// linkBet points a custom bet at game. The bet locks at kickoff, or earlier if
+// it was already set to.
+func linkBet(bet *models.Bet, game linkedGame, outcome string, linkTeam string, total *float64) {
+	bet.CfbdID = game.CfbdID
+	bet.EspnID = game.EspnID
+	bet.Sport = game.Sport
+	startDate := game.StartDate
+	bet.GameStartDate = &startDate
+	if bet.LockAt == nil || bet.LockAt.After(startDate) {
+		bet.LockAt = &startDate
+	}
+
+	bet.Link = outcome
+	bet.LinkTeam = linkTeam
+	bet.Total = nil
+	if outcome == models.LinkTotalOver {
+		bet.Total = total
+	}
+}
+
+// linkResolution says how a linked bet will be resolved.
+func linkResolution(bet models.Bet) string {
+	switch bet.Link {
+	case models.LinkTeamWins:
+		return fmt.Sprintf("resolves itself: **%s** wins if %s wins, **%s** otherwise, and a tie pushes",
+			common.GetOptionName(bet, 1), bet.LinkTeam, common.GetOptionName(bet, 2))
+	case models.LinkTotalOver:
+		return fmt.Sprintf("resolves itself: **%s** wins if more than %s points are scored, **%s** if fewer, and landing on it pushes",
+			common.GetOptionName(bet, 1), formatPoints(*bet.Total), common.GetOptionName(bet, 2))
+	}
+	return "is resolved by an admin"
+}
+
+func respondLinkBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, content string, ephemeral bool) {
+	data := &discordgo.InteractionResponseData{Content: content}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
@@ -0,0 +1,62 @@
+package betService
+
+import (
+	"perfectOddsBot/models"
+	"testing"
+	"time"
+)
+
+func TestValidateBetLink(t *testing.T) {
+	twoOptions := models.Bet{Option1: "Yes", Option2: "No"}
+	threeOptions := models.Bet{Options: []models.BetOption{
+		{OptionNumber: 1, Name: "Win"}, {OptionNumber: 2, Name: "Lose"}, {OptionNumber: 3, Name: "Tie"},
+	}}
+	gameID := "401"
+	gameBet := models.Bet{Option1: "Ohio State", Option2: "Michigan", EspnID: &gameID}
+
+	tests := []struct {
+		name    string
+		bet     models.Bet
+		outcome string
+		team    string
+		total   *float64
+		valid   bool
+	}{
+		{"Team wins", twoOptions, models.LinkTeamWins, "Ohio State", nil, true},
+		{"Total over", twoOptions, models.LinkTotalOver, "", floatPtr(48.5), true},
+		{"Manual with many options", threeOptions, models.LinkManual, "", nil, true},
+		{"Team wins needs a team", twoOptions, models.LinkTeamWins, "", nil, false},
+		{"Total over needs a total", twoOptions, models.LinkTotalOver, "", nil, false},
+		{"Graded outcomes need two options", threeOptions, models.LinkTeamWins, "Ohio State", nil, false},
+		{"Game bets can't be linked", gameBet, models.LinkManual, "", nil, false},
+		{"Resolved bets can't be linked", models.Bet{Paid: true}, models.LinkManual, "", nil, false},
+		{"Unknown outcome", twoOptions, "spread", "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBetLink(tt.bet, tt.outcome, tt.team, tt.total)
+			assertEqual(t, tt.valid, err == nil, "valid link")
+		})
+	}
+}
+
+func TestLinkBet(t *testing.T) {
+	kickoff := time.Date(2026, 10, 24, 19, 30, 0, 0, time.UTC)
+	eventID := "401"
+	game := linkedGame{EspnID: &eventID, Sport: models.SportNFL, StartDate: kickoff, HomeTeam: "Bears", AwayTeam: "Packers"}
+
+	bet := models.Bet{Option1: "Over", Option2: "Under"}
+	linkBet(&bet, game, models.LinkTotalOver, "", floatPtr(44.5))
+	assertEqual(t, kickoff, *bet.LockAt, "locks at kickoff")
+	assertEqual(t, kickoff, *bet.GameStartDate, "game start recorded")
+	assertEqual(t, "401", *bet.EspnID, "linked event")
+	assertEqual(t, 44.5, *bet.Total, "total recorded")
+	assertEqual(t, models.SportNFL, bet.Sport, "sport recorded")
+
+	earlier := kickoff.Add(-time.Hour)
+	bet = models.Bet{Option1: "Bears win", Option2: "Bears don't win", LockAt: &earlier, Total: floatPtr(44.5)}
+	linkBet(&bet, game, models.LinkTeamWins, "Bears", nil)
+	assertEqual(t, earlier, *bet.LockAt, "an earlier lock time is kept")
+	assertEqual(t, "Bears", bet.LinkTeam, "linked team")
+	assertEqual(t, true, bet.Total == nil, "a team link has no total")
+}
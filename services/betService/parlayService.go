@@ -1,6 +1,7 @@
 package betService
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -8,15 +9,24 @@ import (
 	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/limitService"
+	"perfectOddsBot/services/teamService"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ParlaySelection struct {
+	// Type is the kind of parlay being built, one of the models.Parlay* kinds.
+	Type string
+	// Size is the number of legs in each parlay of a round robin.
+	Size            int
 	BetIDs          []uint
 	SelectedOptions map[uint]int
 	MessageID       string
@@ -69,20 +79,125 @@ func createProgressBar(selected, total int) string {
 	return fmt.Sprintf("%s %d%% (%d/%d)", bar, percentage, selected, total)
 }
 
+func parlayBetFieldValue(bet models.Bet, selectedOption int, hasSelection bool) string {
+	description := bet.Description
+	if len(description) > 60 {
+		description = description[:57] + "..."
+	}
+
+	lines := []string{fmt.Sprintf("**%s**", description)}
+	for _, option := range common.GetBetOptions(bet) {
+		odds := common.FormatOdds(float64(option.Odds))
+		if !hasSelection {
+			lines = append(lines, fmt.Sprintf("%s %s (%s)", common.GetOptionEmoji(option.OptionNumber), option.Name, odds))
+		} else if option.OptionNumber == selectedOption {
+			lines = append(lines, fmt.Sprintf("✅ **%s** (%s)", option.Name, odds))
+		} else {
+			lines = append(lines, fmt.Sprintf("⚪ %s (%s)", option.Name, odds))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func parlayOptionButtons(sessionID string, idx int, bet models.Bet, selectedOption int, hasSelection bool) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	for optIdx, option := range common.GetBetOptions(bet) {
+		label := option.Name
+		if len(label) > 70 {
+			label = label[:67] + "..."
+		}
+
+		style := discordgo.PrimaryButton
+		if optIdx%2 == 1 {
+			style = discordgo.SecondaryButton
+		}
+		if hasSelection && option.OptionNumber == selectedOption {
+			style = discordgo.SuccessButton
+		}
+
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("%d️⃣ %s", idx+1, label),
+			CustomID: fmt.Sprintf("parlay_option_%s_%d_%d", sessionID, bet.ID, option.OptionNumber),
+			Style:    style,
+		})
+	}
+	return buttons
+}
+
+// appendParlayButtons keeps a bet's option buttons on the same row where possible, starting
+// a new row once the current one holds two bets' worth of buttons.
+func appendParlayButtons(actionRows []discordgo.MessageComponent, currentRow []discordgo.MessageComponent, buttons []discordgo.MessageComponent) ([]discordgo.MessageComponent, []discordgo.MessageComponent) {
+	for len(buttons) > 0 {
+		if len(currentRow) > 0 && len(currentRow)+len(buttons) > 4 {
+			actionRows = append(actionRows, discordgo.ActionsRow{Components: currentRow})
+			currentRow = []discordgo.MessageComponent{}
+		}
+
+		n := len(buttons)
+		if n > 5 {
+			n = 5
+		}
+		currentRow = append(currentRow, buttons[:n]...)
+		buttons = buttons[n:]
+	}
+	return actionRows, currentRow
+}
+
 func CreateParlaySelector(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	selection := &ParlaySelection{
+		Type:            models.ParlayStandard,
+		Size:            2,
+		SelectedOptions: make(map[uint]int),
+	}
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "type":
+			selection.Type = opt.StringValue()
+		case "size":
+			selection.Size = int(opt.IntValue())
+		}
+	}
+
+	if selection.Type == models.ParlayRoundRobin && (selection.Size < 2 || selection.minLegs() > maxParlayLegs) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("Round robin parlays can have 2-%d legs each.", maxParlayLegs-1),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
 	var openBets []models.Bet
 
-	result := db.Where("active = ? AND paid = ? AND guild_id = ?", true, false, i.GuildID).Find(&openBets)
+	result := db.Preload("Options").Where("active = ? AND paid = ? AND guild_id = ? AND (lock_at IS NULL OR lock_at > ?)", true, false, i.GuildID, time.Now()).Find(&openBets)
 	if result.Error != nil {
 		common.SendError(s, i, result.Error, db)
 		return
 	}
 
-	if len(openBets) < 2 {
+	betKind := "open bet"
+	if selection.Type == models.ParlayTeaser {
+		betKind = "open spread bet"
+		var spreadBets []models.Bet
+		for _, bet := range openBets {
+			if teaserEligible(bet) {
+				spreadBets = append(spreadBets, bet)
+			}
+		}
+		openBets = spreadBets
+	}
+
+	if len(openBets) < selection.minLegs() {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "You need at least 2 open bets to create a parlay. Currently there are " + strconv.Itoa(len(openBets)) + " open bet(s).",
+				Content: fmt.Sprintf("You need at least %d %ss to create a %s. Currently there are %d %s(s).", selection.minLegs(), betKind, parlayTypeName(selection.Type), len(openBets), betKind),
 				Flags:   discordgo.MessageFlagsEphemeral,
 			},
 		})
@@ -99,7 +214,11 @@ func CreateParlaySelector(s *discordgo.Session, i *discordgo.InteractionCreate,
 			label = label[:97] + "..."
 		}
 
-		description := fmt.Sprintf("%s | %s", bet.Option1, bet.Option2)
+		var optionNames []string
+		for _, option := range common.GetBetOptions(bet) {
+			optionNames = append(optionNames, option.Name)
+		}
+		description := strings.Join(optionNames, " | ")
 		if len(description) > 100 {
 			description = description[:97] + "..."
 		}
@@ -112,14 +231,15 @@ func CreateParlaySelector(s *discordgo.Session, i *discordgo.InteractionCreate,
 	}
 
 	sessionID := i.Interaction.ID
+	StoreParlaySelection(sessionID, selection)
 
-	minValues := 2
+	minValues := selection.minLegs()
 	maxValues := len(openBets)
-	if maxValues > 10 {
-		maxValues = 10
+	if maxValues > maxParlayLegs {
+		maxValues = maxParlayLegs
 	}
 
-	content := fmt.Sprintf("Select **2-%d** bets to include in your parlay (you'll choose options for each bet next):", maxValues)
+	content := fmt.Sprintf("Select **%d-%d** bets to include in your %s (you'll choose options for each bet next):%s", minValues, maxValues, parlayTypeName(selection.Type), selection.note())
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -131,7 +251,7 @@ func CreateParlaySelector(s *discordgo.Session, i *discordgo.InteractionCreate,
 						discordgo.SelectMenu{
 							MenuType:    discordgo.StringSelectMenu,
 							CustomID:    fmt.Sprintf("parlay_select_bets_%s", sessionID),
-							Placeholder: "Select bets for parlay (min 2)",
+							Placeholder: fmt.Sprintf("Select bets for %s (min %d)", parlayTypeName(selection.Type), minValues),
 							MinValues:   &minValues,
 							MaxValues:   maxValues,
 							Options:     selectOptions,
@@ -160,11 +280,16 @@ func HandleParlayBetSelection(s *discordgo.Session, i *discordgo.InteractionCrea
 	sessionID := strings.TrimPrefix(customID, "parlay_select_bets_")
 	selectedBetIDs := i.MessageComponentData().Values
 
-	if len(selectedBetIDs) < 2 {
+	selection, exists := GetParlaySelection(sessionID)
+	if !exists {
+		selection = &ParlaySelection{Type: models.ParlayStandard}
+	}
+
+	if len(selectedBetIDs) < selection.minLegs() {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "You must select at least 2 bets for a parlay.",
+				Content: fmt.Sprintf("You must select at least %d bets for a %s.", selection.minLegs(), parlayTypeName(selection.Type)),
 				Flags:   discordgo.MessageFlagsEphemeral,
 			},
 		})
@@ -184,7 +309,7 @@ func HandleParlayBetSelection(s *discordgo.Session, i *discordgo.InteractionCrea
 		betIDs = append(betIDs, uint(id))
 	}
 
-	result := db.Where("id IN ? AND active = ? AND paid = ? AND guild_id = ?", betIDs, true, false, i.GuildID).Find(&bets)
+	result := db.Preload("Options").Where("id IN ? AND active = ? AND paid = ? AND guild_id = ?", betIDs, true, false, i.GuildID).Find(&bets)
 	if result.Error != nil || len(bets) != len(selectedBetIDs) {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -199,43 +324,43 @@ func HandleParlayBetSelection(s *discordgo.Session, i *discordgo.InteractionCrea
 		return nil
 	}
 
-	selection := &ParlaySelection{
-		BetIDs:          betIDs,
-		SelectedOptions: make(map[uint]int),
+	if selection.Type == models.ParlayTeaser {
+		for _, bet := range bets {
+			if teaserEligible(bet) {
+				continue
+			}
+			err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: "Teasers can only include spread bets. Please try again.",
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			return nil
+		}
 	}
+
+	selection.BetIDs = betIDs
+	selection.SelectedOptions = make(map[uint]int)
 	StoreParlaySelection(sessionID, selection)
 
 	var fields []*discordgo.MessageEmbedField
 	for idx, bet := range bets {
-		fieldName := fmt.Sprintf("Bet %d", idx+1)
-		if len(bet.Description) > 60 {
-			fieldValue := fmt.Sprintf("**%s**\n1️⃣ %s (%s) | 2️⃣ %s (%s)",
-				bet.Description[:57]+"...",
-				bet.Option1, common.FormatOdds(float64(bet.Odds1)),
-				bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-			fields = append(fields, &discordgo.MessageEmbedField{
-				Name:   fieldName,
-				Value:  fieldValue,
-				Inline: true,
-			})
-		} else {
-			fieldValue := fmt.Sprintf("**%s**\n1️⃣ %s (%s)\n2️⃣ %s (%s)",
-				bet.Description,
-				bet.Option1, common.FormatOdds(float64(bet.Odds1)),
-				bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-			fields = append(fields, &discordgo.MessageEmbedField{
-				Name:   fieldName,
-				Value:  fieldValue,
-				Inline: true,
-			})
-		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Bet %d", idx+1),
+			Value:  parlayBetFieldValue(bet, 0, false),
+			Inline: true,
+		})
 	}
 
 	progressBar := createProgressBar(0, len(bets))
-	description := fmt.Sprintf("%s\n\n📋 Select an option for each of the **%d** bets below:", progressBar, len(bets))
+	description := fmt.Sprintf("%s\n\n📋 Select an option for each of the **%d** bets below:%s", progressBar, len(bets), selection.note())
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "🎯 Create Parlay",
+		Title:       selection.title(),
 		Description: description,
 		Fields:      fields,
 		Color:       0x5865F2,
@@ -248,34 +373,7 @@ func HandleParlayBetSelection(s *discordgo.Session, i *discordgo.InteractionCrea
 	var currentRowComponents []discordgo.MessageComponent
 
 	for idx, bet := range bets {
-		if len(currentRowComponents) >= 4 {
-			actionRows = append(actionRows, discordgo.ActionsRow{Components: currentRowComponents})
-			currentRowComponents = []discordgo.MessageComponent{}
-		}
-
-		betIDStr := strconv.Itoa(int(bet.ID))
-
-		label1 := bet.Option1
-		if len(label1) > 70 {
-			label1 = label1[:67] + "..."
-		}
-		label2 := bet.Option2
-		if len(label2) > 70 {
-			label2 = label2[:67] + "..."
-		}
-
-		currentRowComponents = append(currentRowComponents,
-			discordgo.Button{
-				Label:    fmt.Sprintf("%d️⃣ %s", idx+1, label1),
-				CustomID: fmt.Sprintf("parlay_option_%s_%s_1", sessionID, betIDStr),
-				Style:    discordgo.PrimaryButton,
-			},
-			discordgo.Button{
-				Label:    fmt.Sprintf("%d️⃣ %s", idx+1, label2),
-				CustomID: fmt.Sprintf("parlay_option_%s_%s_2", sessionID, betIDStr),
-				Style:    discordgo.SecondaryButton,
-			},
-		)
+		actionRows, currentRowComponents = appendParlayButtons(actionRows, currentRowComponents, parlayOptionButtons(sessionID, idx, bet, 0, false))
 	}
 
 	if len(currentRowComponents) > 0 {
@@ -329,7 +427,13 @@ func HandleParlayOptionSelection(s *discordgo.Session, i *discordgo.InteractionC
 	}
 
 	option, err := strconv.Atoi(optionStr)
-	if err != nil || (option != 1 && option != 2) {
+	if err != nil {
+		return fmt.Errorf("invalid option value")
+	}
+
+	var bet models.Bet
+	db.Preload("Options").First(&bet, betID)
+	if !common.IsValidBetOption(bet, option) {
 		return fmt.Errorf("invalid option value")
 	}
 
@@ -358,115 +462,25 @@ func HandleParlayOptionSelection(s *discordgo.Session, i *discordgo.InteractionC
 
 	allSelected := len(selection.SelectedOptions) == len(selection.BetIDs)
 
-	var bet models.Bet
-	db.First(&bet, betID)
-
-	optionName := bet.Option1
-	if option == 2 {
-		optionName = bet.Option2
-	}
+	optionName := common.GetOptionName(bet, option)
 
 	var betFields []*discordgo.MessageEmbedField
 	var actionRows []discordgo.MessageComponent
 	var currentRowComponents []discordgo.MessageComponent
 
 	var bets []models.Bet
-	db.Where("id IN ?", selection.BetIDs).Find(&bets)
+	db.Preload("Options").Where("id IN ?", selection.BetIDs).Find(&bets)
 
 	for idx, bet := range bets {
 		selectedOption, hasSelection := selection.SelectedOptions[bet.ID]
 
-		var fieldValue string
-		fieldName := fmt.Sprintf("Bet %d", idx+1)
-
-		if hasSelection {
-			selectedOptionName := bet.Option1
-			if selectedOption == 2 {
-				selectedOptionName = bet.Option2
-			}
-			odds := bet.Odds1
-			if selectedOption == 2 {
-				odds = bet.Odds2
-			}
-
-			if len(bet.Description) > 60 {
-				fieldValue = fmt.Sprintf("**%s**\n✅ **%s** (%s)\n⚪ %s (%s)",
-					bet.Description[:57]+"...",
-					selectedOptionName, common.FormatOdds(float64(odds)),
-					bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-				if selectedOption == 2 {
-					fieldValue = fmt.Sprintf("**%s**\n✅ **%s** (%s)\n⚪ %s (%s)",
-						bet.Description[:57]+"...",
-						selectedOptionName, common.FormatOdds(float64(odds)),
-						bet.Option1, common.FormatOdds(float64(bet.Odds1)))
-				}
-			} else {
-				fieldValue = fmt.Sprintf("**%s**\n✅ **%s** (%s)\n⚪ %s (%s)",
-					bet.Description,
-					selectedOptionName, common.FormatOdds(float64(odds)),
-					bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-				if selectedOption == 2 {
-					fieldValue = fmt.Sprintf("**%s**\n✅ **%s** (%s)\n⚪ %s (%s)",
-						bet.Description,
-						selectedOptionName, common.FormatOdds(float64(odds)),
-						bet.Option1, common.FormatOdds(float64(bet.Odds1)))
-				}
-			}
-		} else {
-			if len(bet.Description) > 60 {
-				fieldValue = fmt.Sprintf("**%s**\n1️⃣ %s (%s)\n2️⃣ %s (%s)",
-					bet.Description[:57]+"...",
-					bet.Option1, common.FormatOdds(float64(bet.Odds1)),
-					bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-			} else {
-				fieldValue = fmt.Sprintf("**%s**\n1️⃣ %s (%s)\n2️⃣ %s (%s)",
-					bet.Description,
-					bet.Option1, common.FormatOdds(float64(bet.Odds1)),
-					bet.Option2, common.FormatOdds(float64(bet.Odds2)))
-			}
-		}
-
 		betFields = append(betFields, &discordgo.MessageEmbedField{
-			Name:   fieldName,
-			Value:  fieldValue,
+			Name:   fmt.Sprintf("Bet %d", idx+1),
+			Value:  parlayBetFieldValue(bet, selectedOption, hasSelection),
 			Inline: true,
 		})
 
-		if len(currentRowComponents) >= 4 {
-			actionRows = append(actionRows, discordgo.ActionsRow{Components: currentRowComponents})
-			currentRowComponents = []discordgo.MessageComponent{}
-		}
-
-		betIDStr := strconv.Itoa(int(bet.ID))
-		label1 := bet.Option1
-		if len(label1) > 70 {
-			label1 = label1[:67] + "..."
-		}
-		label2 := bet.Option2
-		if len(label2) > 70 {
-			label2 = label2[:67] + "..."
-		}
-
-		style1 := discordgo.PrimaryButton
-		style2 := discordgo.SecondaryButton
-		if hasSelection && selectedOption == 1 {
-			style1 = discordgo.SuccessButton
-		} else if hasSelection && selectedOption == 2 {
-			style2 = discordgo.SuccessButton
-		}
-
-		currentRowComponents = append(currentRowComponents,
-			discordgo.Button{
-				Label:    fmt.Sprintf("%d️⃣ %s", idx+1, label1),
-				CustomID: fmt.Sprintf("parlay_option_%s_%s_1", sessionID, betIDStr),
-				Style:    style1,
-			},
-			discordgo.Button{
-				Label:    fmt.Sprintf("%d️⃣ %s", idx+1, label2),
-				CustomID: fmt.Sprintf("parlay_option_%s_%s_2", sessionID, betIDStr),
-				Style:    style2,
-			},
-		)
+		actionRows, currentRowComponents = appendParlayButtons(actionRows, currentRowComponents, parlayOptionButtons(sessionID, idx, bet, selectedOption, hasSelection))
 	}
 
 	if len(currentRowComponents) > 0 {
@@ -503,8 +517,8 @@ func HandleParlayOptionSelection(s *discordgo.Session, i *discordgo.InteractionC
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "🎯 Create Parlay",
-		Description: description,
+		Title:       selection.title(),
+		Description: description + selection.note(),
 		Fields:      betFields,
 		Color:       embedColor,
 		Footer: &discordgo.MessageEmbedFooter{
@@ -571,30 +585,38 @@ func HandleParlaySubmit(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return result.Error
 	}
 
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+	maxAmount, _, err := limitService.AllowedStake(db, *guild, user, nil)
+	if err != nil {
+		return err
+	}
+
 	var bets []models.Bet
-	db.Where("id IN ?", selection.BetIDs).Find(&bets)
+	db.Preload("Options").Where("id IN ?", selection.BetIDs).Find(&bets)
 
-	var oddsList []int
-	for _, bet := range bets {
-		option := selection.SelectedOptions[bet.ID]
-		odds := common.GetOddsFromBet(bet, option)
-		oddsList = append(oddsList, odds)
-	}
+	parlays := ticketParlays(selection.Type, selection.Size, parlayLegs(selection, bets))
 
-	oddsMultiplier := common.CalculateParlayOddsMultiplier(oddsList)
-	totalOddsStr := fmt.Sprintf("%.2fx", oddsMultiplier)
+	title := fmt.Sprintf("Enter Parlay Amount (Odds: %.2fx)", parlayLegsOdds(parlays[0]))
+	amountLabel := fmt.Sprintf("Bet Amount (Max: %.0f)", math.Floor(maxAmount))
+	if selection.Type == models.ParlayRoundRobin {
+		title = fmt.Sprintf("Round Robin Amount (%d Parlays)", len(parlays))
+		amountLabel = fmt.Sprintf("Total Amount, Split Evenly (Max: %.0f)", math.Floor(maxAmount))
+	}
 
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
-			Title:    fmt.Sprintf("Enter Parlay Amount (Odds: %s)", totalOddsStr),
+			Title:    title,
 			CustomID: fmt.Sprintf("parlay_amount_%s", sessionID),
 			Components: []discordgo.MessageComponent{
 				discordgo.ActionsRow{
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "parlay_amount_input",
-							Label:       fmt.Sprintf("Bet Amount (Max: %.0f)", math.Floor(user.Points)),
+							Label:       amountLabel,
 							Style:       discordgo.TextInputShort,
 							Placeholder: "Enter amount",
 							Required:    true,
@@ -608,6 +630,10 @@ func HandleParlaySubmit(s *discordgo.Session, i *discordgo.InteractionCreate, db
 	return err
 }
 
+// errParlayLegClosed is returned from HandleParlayAmount's transaction when a
+// leg's bet stopped taking entries after it was first checked.
+var errParlayLegClosed = errors.New("parlay leg closed")
+
 func HandleParlayAmount(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
 	sessionID := strings.TrimPrefix(customID, "parlay_amount_")
 
@@ -662,9 +688,7 @@ func HandleParlayAmount(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return nil
 	}
 
-	var bets []models.Bet
-	result = db.Where("id IN ? AND active = ? AND paid = ? AND guild_id = ?", selection.BetIDs, true, false, i.GuildID).Find(&bets)
-	if result.Error != nil || len(bets) != len(selection.BetIDs) {
+	unavailable := func() error {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -679,64 +703,164 @@ func HandleParlayAmount(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return nil
 	}
 
-	var oddsList []int
-	for _, bet := range bets {
-		option := selection.SelectedOptions[bet.ID]
-		odds := common.GetOddsFromBet(bet, option)
-		oddsList = append(oddsList, odds)
+	var bets []models.Bet
+	result = db.Preload("Options").Where("id IN ? AND active = ? AND paid = ? AND guild_id = ? AND (lock_at IS NULL OR lock_at > ?)", selection.BetIDs, true, false, i.GuildID, time.Now()).Find(&bets)
+	if result.Error != nil || len(bets) != len(selection.BetIDs) {
+		return unavailable()
 	}
 
-	oddsMultiplier := common.CalculateParlayOddsMultiplier(oddsList)
+	legs := parlayLegs(selection, bets)
+	parlays := ticketParlays(selection.Type, selection.Size, legs)
 
-	parlay := models.Parlay{
-		UserID:        user.ID,
-		GuildID:       i.GuildID,
-		Amount:        amount,
-		TotalOdds:     oddsMultiplier,
-		Status:        "pending",
-		ParlayEntries: []models.ParlayEntry{},
+	// A round robin splits the amount evenly across its parlays. Any
+	// remainder that doesn't split evenly is not wagered.
+	stake := amount / len(parlays)
+	if stake < 1 {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("A round robin of %d parlays needs at least %d points.", len(parlays), len(parlays)),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return nil
 	}
 
-	result = db.Create(&parlay)
-	if result.Error != nil {
-		return result.Error
+	// A ticket is one wager, so the limits apply to everything it stakes.
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+	total := float64(stake * len(parlays))
+	rejected := func(limitErr *limitService.LimitError) error {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("🚫 %s rejected. %s", parlayTypeTitle(selection.Type), limitErr.Reason),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+	err = limitService.CheckStake(db, *guild, user, total, nil)
+	var limitErr *limitService.LimitError
+	if errors.As(err, &limitErr) {
+		return rejected(limitErr)
+	}
+	if err != nil {
+		return err
 	}
 
-	for _, bet := range bets {
-		option := selection.SelectedOptions[bet.ID]
-		parlayEntry := models.ParlayEntry{
-			ParlayID:       parlay.ID,
-			BetID:          bet.ID,
-			SelectedOption: option,
-			Spread:         bet.Spread,
-			Resolved:       false,
-			Won:            nil,
-		}
-		db.Create(&parlayEntry)
+	ticketID := ""
+	if selection.Type == models.ParlayRoundRobin {
+		ticketID = sessionID
+	}
+	teaserPoints := 0.0
+	if selection.Type == models.ParlayTeaser {
+		teaserPoints = TeaserPoints
 	}
 
-	user.Points -= float64(amount)
-	db.Save(&user)
+	// Every parlay on the ticket, its legs and its stake commit together, so a
+	// failure part way through doesn't leave half a round robin placed. The
+	// legs' bets and the user are locked and checked again, as a bet may have
+	// closed or settled and another bet may have been placed since the checks
+	// above. The legs take their lines from the locked bets.
+	potentialPayout := 0.0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var locked []models.Bet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Options").
+			Where("id IN ? AND guild_id = ?", selection.BetIDs, i.GuildID).Order("id").Find(&locked).Error; err != nil {
+			return err
+		}
+		if len(locked) != len(selection.BetIDs) {
+			return errParlayLegClosed
+		}
+		now := time.Now()
+		for _, bet := range locked {
+			if !common.BetOpen(bet, now) {
+				return errParlayLegClosed
+			}
+		}
+		legs = parlayLegs(selection, locked)
+		parlays = ticketParlays(selection.Type, selection.Size, legs)
 
-	potentialPayout := common.CalculateParlayPayout(amount, oddsMultiplier)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, user.ID).Error; err != nil {
+			return err
+		}
+		if user.Points < total {
+			return &limitService.LimitError{Reason: "You do not have enough points to place this parlay."}
+		}
+		if err := limitService.CheckStake(tx, *guild, user, total, nil); err != nil {
+			return err
+		}
 
+		for _, parlayEntries := range parlays {
+			oddsMultiplier := parlayLegsOdds(parlayEntries)
+			parlay := models.Parlay{
+				UserID:        user.ID,
+				GuildID:       i.GuildID,
+				Amount:        stake,
+				TotalOdds:     oddsMultiplier,
+				Status:        "pending",
+				Type:          selection.Type,
+				TicketID:      ticketID,
+				TeaserPoints:  teaserPoints,
+				ParlayEntries: []models.ParlayEntry{},
+			}
+			if err := tx.Create(&parlay).Error; err != nil {
+				return err
+			}
+
+			for _, parlayEntry := range parlayEntries {
+				parlayEntry.ParlayID = parlay.ID
+				if err := tx.Omit("Bet").Create(&parlayEntry).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := ledgerService.Apply(tx, &user, -float64(stake), ledgerService.Parlay(ledgerService.ReasonParlayPlaced, parlay.ID)); err != nil {
+				return err
+			}
+
+			potentialPayout += common.CalculateParlayPayout(stake, oddsMultiplier)
+		}
+		return nil
+	})
+	if errors.Is(err, errParlayLegClosed) {
+		return unavailable()
+	}
+	if errors.As(err, &limitErr) {
+		return rejected(limitErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	teams := teamService.ForGuild(db, i.GuildID)
 	var summary strings.Builder
-	summary.WriteString("**Parlay Created Successfully!**\n\n")
-	for idx, bet := range bets {
-		option := selection.SelectedOptions[bet.ID]
-		optionName := bet.Option1
-		if option == 2 {
-			optionName = bet.Option2
+	summary.WriteString(fmt.Sprintf("**%s Created Successfully!**\n\n", parlayTypeTitle(selection.Type)))
+	for idx, leg := range legs {
+		summary.WriteString(fmt.Sprintf("%d. %s: **%s**\n", idx+1, leg.Bet.Description, parlayLegOptionName(teams, leg)))
+	}
+	switch selection.Type {
+	case models.ParlayRoundRobin:
+		summary.WriteString(fmt.Sprintf("\n**Parlays:** %d × %d legs\n", len(parlays), selection.Size))
+		summary.WriteString(fmt.Sprintf("**Amount:** %d points each (%d total)\n", stake, stake*len(parlays)))
+		summary.WriteString(fmt.Sprintf("**Potential Payout:** %.1f points if every parlay hits\n", potentialPayout))
+	default:
+		if selection.Type == models.ParlayTeaser {
+			summary.WriteString(fmt.Sprintf("\n**Teaser:** %s points on every spread", formatPoints(teaserPoints)))
 		}
-		summary.WriteString(fmt.Sprintf("%d. %s: **%s**\n", idx+1, bet.Description, optionName))
+		summary.WriteString(fmt.Sprintf("\n**Amount:** %d points\n", stake))
+		summary.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx\n", parlayLegsOdds(legs)))
+		summary.WriteString(fmt.Sprintf("**Potential Payout:** %.1f points\n", potentialPayout))
 	}
-	summary.WriteString(fmt.Sprintf("\n**Amount:** %d points\n", amount))
-	summary.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx\n", oddsMultiplier))
-	summary.WriteString(fmt.Sprintf("**Potential Payout:** %.1f points\n", potentialPayout))
 	summary.WriteString(fmt.Sprintf("**Remaining Points:** %.1f", user.Points))
 
 	successEmbed := &discordgo.MessageEmbed{
-		Title:       "✅ Parlay Placed Successfully",
+		Title:       fmt.Sprintf("✅ %s Placed Successfully", parlayTypeTitle(selection.Type)),
 		Description: summary.String(),
 		Color:       0x00ff00,
 	}
@@ -761,7 +885,7 @@ func HandleParlayAmount(s *discordgo.Session, i *discordgo.InteractionCreate, db
 				})
 			} else {
 				_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-					Content: "✅ Parlay created successfully!",
+					Content: fmt.Sprintf("✅ %s created successfully!", parlayTypeTitle(selection.Type)),
 				})
 			}
 		} else {
@@ -808,21 +932,20 @@ func HandleParlayCancel(s *discordgo.Session, i *discordgo.InteractionCreate, db
 	return err
 }
 
-func UpdateParlaysOnBetResolution(s *discordgo.Session, db *gorm.DB, betID uint, winningOption int, scoreDiff int) error {
+// updateParlaysOnBetResolution grades every open parlay leg on a settled bet. It
+// runs inside the settlement transaction; notifications are queued until commit.
+func updateParlaysOnBetResolution(s *discordgo.Session, db *gorm.DB, notifications *notificationQueue, bet models.Bet, betResult BetResult) error {
 	var parlayEntries []models.ParlayEntry
-	result := db.Where("bet_id = ? AND resolved = ?", betID, false).Find(&parlayEntries)
+	result := db.Where("bet_id = ? AND resolved = ?", bet.ID, false).Find(&parlayEntries)
 	if result.Error != nil {
 		return result.Error
 	}
 
-	var bet models.Bet
-	if err := db.First(&bet, betID).Error; err != nil {
-		return err
-	}
-
 	for _, entry := range parlayEntries {
 		var parlay models.Parlay
-		db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").First(&parlay, entry.ParlayID)
+		if err := db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").Preload("ParlayEntries.Bet.Options").First(&parlay, entry.ParlayID).Error; err != nil {
+			return err
+		}
 		previousStatus := parlay.Status
 
 		allResolved := true
@@ -841,84 +964,307 @@ func UpdateParlaysOnBetResolution(s *discordgo.Session, db *gorm.DB, betID uint,
 		}
 		if hasLoss {
 			entry.Resolved = true
-			db.Save(&entry)
+			if err := db.Save(&entry).Error; err != nil {
+				return err
+			}
 			continue
 		}
 
-		var won bool
-		if bet.Spread == nil {
-			if scoreDiff == 0 {
-				won = false
-			} else {
-				won = entry.SelectedOption == winningOption
-			}
-		} else {
-			if scoreDiff == 0 {
-				won = entry.SelectedOption == winningOption
-			} else {
-				var entrySpread float64
-				if entry.Spread != nil {
-					entrySpread = *entry.Spread
-				} else {
-					entrySpread = *bet.Spread
-				}
-				won = common.CalculateBetEntryWin(entry.SelectedOption, scoreDiff, entrySpread)
-			}
-		}
+		won, push := parlayEntryOutcome(bet, entry, betResult)
 		entry.Resolved = true
+		entry.Push = push
+		if !push {
+			entry.Won = &won
+		}
+		if err := db.Save(&entry).Error; err != nil {
+			return err
+		}
+		syncParlayEntry(&parlay, entry)
 
-		entry.Won = &won
-		db.Save(&entry)
+		if push {
+			if err := settleParlayAfterDroppedLeg(s, db, notifications, &parlay, previousStatus, "push"); err != nil {
+				return err
+			}
+			continue
+		}
 
 		if !won {
 			parlay.Status = "lost"
-			db.Save(&parlay)
+			if err := db.Save(&parlay).Error; err != nil {
+				return err
+			}
 
 			if previousStatus != "lost" && previousStatus != "won" {
 				var user models.User
-				db.First(&user, parlay.UserID)
+				if err := db.First(&user, parlay.UserID).Error; err != nil {
+					return err
+				}
 				user.TotalBetsLost++
 				user.TotalPointsLost += float64(parlay.Amount)
-				db.Save(&user)
+				if err := db.Omit("points").Save(&user).Error; err != nil {
+					return err
+				}
 
-				guild, guildErr := guildService.GetGuildInfo(s, db, parlay.GuildID, "")
-				if guildErr == nil {
-					db.Model(&models.Guild{}).Where("id = ?", guild.ID).UpdateColumn("pool", gorm.Expr("pool + ?", float64(parlay.Amount)))
+				if err := db.Model(&models.Guild{}).Where("guild_id = ?", parlay.GuildID).UpdateColumn("pool", gorm.Expr("pool + ?", float64(parlay.Amount))).Error; err != nil {
+					return err
 				}
-				SendParlayResolutionNotification(s, db, parlay, false)
+				lostParlay := parlay
+				notifications.add(func(db *gorm.DB) {
+					SendParlayResolutionNotification(s, db, lostParlay, false)
+				})
 			}
 		} else if allResolved {
-			if !hasLoss {
-				parlay.Status = "won"
-				db.Save(&parlay)
-
-				var user models.User
-				db.First(&user, parlay.UserID)
-				payout := common.CalculateParlayPayout(parlay.Amount, parlay.TotalOdds)
-				var err error
-				payout, _, err = cardService.ApplyHeismanCampaignIfApplicable(db, user, payout)
-				if err != nil {
-					log.Printf("Error applying Heisman Campaign card for parlay ID %d, user ID %d, payout %.2f: %v", parlay.ID, parlay.UserID, payout, err)
-					return fmt.Errorf("failed to apply Heisman Campaign card for parlay %d (user %d): %w", parlay.ID, parlay.UserID, err)
-				}
-				user.Points += payout
-				user.TotalBetsWon++
-				user.TotalPointsWon += payout
-				db.Save(&user)
-
-				if previousStatus != "lost" && previousStatus != "won" {
-					SendParlayResolutionNotification(s, db, parlay, true, payout)
-				}
+			if err := payParlay(s, db, notifications, &parlay, previousStatus); err != nil {
+				return err
 			}
 		} else {
 			parlay.Status = "partial"
-			db.Save(&parlay)
+			if err := db.Save(&parlay).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// voidParlayLegs drops every unresolved parlay leg on a voided bet. Affected
+// parlays are repriced from their remaining legs, refunded when no legs remain,
+// and paid out when the dropped leg was the last one outstanding.
+func voidParlayLegs(s *discordgo.Session, db *gorm.DB, notifications *notificationQueue, betID uint) error {
+	var parlayEntries []models.ParlayEntry
+	result := db.Where("bet_id = ? AND resolved = ?", betID, false).Find(&parlayEntries)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, entry := range parlayEntries {
+		var parlay models.Parlay
+		if err := db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").Preload("ParlayEntries.Bet.Options").First(&parlay, entry.ParlayID).Error; err != nil {
+			return err
+		}
+		previousStatus := parlay.Status
+
+		entry.Resolved = true
+		entry.Void = true
+		if err := db.Save(&entry).Error; err != nil {
+			return err
+		}
+		syncParlayEntry(&parlay, entry)
+
+		if previousStatus == "lost" || previousStatus == "won" {
+			continue
+		}
+
+		if err := settleParlayAfterDroppedLeg(s, db, notifications, &parlay, previousStatus, "void"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func syncParlayEntry(parlay *models.Parlay, entry models.ParlayEntry) {
+	for idx := range parlay.ParlayEntries {
+		if parlay.ParlayEntries[idx].ID == entry.ID {
+			parlay.ParlayEntries[idx].Resolved = entry.Resolved
+			parlay.ParlayEntries[idx].Won = entry.Won
+			parlay.ParlayEntries[idx].Push = entry.Push
+			parlay.ParlayEntries[idx].Void = entry.Void
+		}
+	}
+}
+
+// settleParlayAfterDroppedLeg reprices a parlay once a leg pushes or is voided.
+// refundStatus is the status recorded when no priced legs remain.
+func settleParlayAfterDroppedLeg(s *discordgo.Session, db *gorm.DB, notifications *notificationQueue, parlay *models.Parlay, previousStatus string, refundStatus string) error {
+	var remainingOdds []int
+	allResolved := true
+	for _, pe := range parlay.ParlayEntries {
+		if !pe.Resolved {
+			allResolved = false
+		}
+		if pe.Push || pe.Void {
+			continue
 		}
+		remainingOdds = append(remainingOdds, common.GetParlayEntryOdds(pe))
 	}
 
+	if len(remainingOdds) == 0 {
+		parlay.Status = refundStatus
+		if err := db.Save(parlay).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := db.First(&user, parlay.UserID).Error; err != nil {
+			return err
+		}
+		if err := ledgerService.Apply(db, &user, float64(parlay.Amount), ledgerService.Parlay(ledgerService.ReasonParlayRefund, parlay.ID)); err != nil {
+			return err
+		}
+
+		refundedParlay := *parlay
+		notifications.add(func(db *gorm.DB) {
+			SendParlayRefundNotification(s, db, refundedParlay)
+		})
+		return nil
+	}
+
+	parlay.TotalOdds = common.CalculateParlayOddsMultiplier(remainingOdds)
+	if allResolved {
+		return payParlay(s, db, notifications, parlay, previousStatus)
+	}
+
+	parlay.Status = "partial"
+	return db.Save(parlay).Error
+}
+
+func payParlay(s *discordgo.Session, db *gorm.DB, notifications *notificationQueue, parlay *models.Parlay, previousStatus string) error {
+	parlay.Status = "won"
+	if err := db.Save(parlay).Error; err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := db.First(&user, parlay.UserID).Error; err != nil {
+		return err
+	}
+	payout := common.CalculateParlayPayout(parlay.Amount, parlay.TotalOdds)
+	var err error
+	payout, _, err = cardService.ApplyHeismanCampaignIfApplicable(db, user, payout)
+	if err != nil {
+		log.Printf("Error applying Heisman Campaign card for parlay ID %d, user ID %d, payout %.2f: %v", parlay.ID, parlay.UserID, payout, err)
+		return fmt.Errorf("failed to apply Heisman Campaign card for parlay %d (user %d): %w", parlay.ID, parlay.UserID, err)
+	}
+	if err := ledgerService.Apply(db, &user, payout, ledgerService.Parlay(ledgerService.ReasonParlayPayout, parlay.ID)); err != nil {
+		return err
+	}
+	user.TotalBetsWon++
+	user.TotalPointsWon += payout
+	if err := db.Omit("points").Save(&user).Error; err != nil {
+		return err
+	}
+
+	if previousStatus != "lost" && previousStatus != "won" {
+		wonParlay := *parlay
+		notifications.add(func(db *gorm.DB) {
+			SendParlayResolutionNotification(s, db, wonParlay, true, payout)
+		})
+	}
 	return nil
 }
 
+// parlayEntryOutcome grades a parlay leg. Bets resolved by hand only know the
+// winning option, so their legs never push.
+func parlayEntryOutcome(bet models.Bet, entry models.ParlayEntry, result BetResult) (won bool, push bool) {
+	winningOption, scoreDiff, totalScore := result.WinningOption, result.ScoreDiff, result.TotalScore
+	if !result.Scored {
+		if winningOption == PushOption {
+			return false, true
+		}
+		return entry.SelectedOption == winningOption, false
+	}
+
+	if bet.Total != nil {
+		entryTotal := *bet.Total
+		if entry.Total != nil {
+			entryTotal = *entry.Total
+		}
+		if common.CalculateTotalEntryPush(totalScore, entryTotal) {
+			return false, true
+		}
+		return common.CalculateTotalEntryWin(entry.SelectedOption, totalScore, entryTotal), false
+	}
+
+	if bet.Spread == nil {
+		if scoreDiff == 0 {
+			return false, true
+		}
+		return entry.SelectedOption == winningOption, false
+	}
+
+	entrySpread := *bet.Spread
+	if entry.Spread != nil {
+		entrySpread = *entry.Spread
+	}
+	if common.CalculateBetEntryPush(scoreDiff, entrySpread) {
+		return false, true
+	}
+	return common.CalculateBetEntryWin(entry.SelectedOption, scoreDiff, entrySpread), false
+}
+
+// parlayLegOptionName names a leg's pick with the spread or total it was placed
+// at, which may differ from the bet's current line, and the team as teams
+// names it.
+func parlayLegOptionName(teams *teamService.Resolver, entry models.ParlayEntry) string {
+	if entry.Total != nil {
+		return common.FormatTotalOption(entry.SelectedOption, *entry.Total)
+	}
+	optionName := common.GetOptionName(entry.Bet, entry.SelectedOption)
+	if entry.Spread != nil {
+		spread := *entry.Spread
+		if entry.SelectedOption == 2 {
+			spread *= -1
+		}
+		return fmt.Sprintf("%s %s", teams.Name(common.GetSchoolName(optionName)), common.FormatOdds(spread))
+	}
+	return teams.Option(optionName)
+}
+
+// parlayRepricing describes how a parlay's odds changed as legs pushed or were
+// voided, such as " (repriced from 6.96x after 1 push)". It is empty when no
+// leg was dropped.
+func parlayRepricing(parlay models.Parlay) string {
+	var allOdds []int
+	pushes, voids := 0, 0
+	for _, entry := range parlay.ParlayEntries {
+		allOdds = append(allOdds, common.GetParlayEntryOdds(entry))
+		switch {
+		case entry.Void:
+			voids++
+		case entry.Push:
+			pushes++
+		}
+	}
+	if pushes == 0 && voids == 0 {
+		return ""
+	}
+
+	var dropped []string
+	if pushes > 0 {
+		dropped = append(dropped, pluralize(pushes, "push", "pushes"))
+	}
+	if voids > 0 {
+		dropped = append(dropped, pluralize(voids, "void", "voids"))
+	}
+	original := common.CalculateParlayOddsMultiplier(allOdds)
+	return fmt.Sprintf(" (repriced from %.2fx after %s)", original, strings.Join(dropped, " and "))
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+func parlayEntryStatus(entry models.ParlayEntry) string {
+	if !entry.Resolved {
+		return "⏳ Pending"
+	}
+	if entry.Void {
+		return "🚫 Void"
+	}
+	if entry.Push {
+		return "➖ Push"
+	}
+	if entry.Won != nil && *entry.Won {
+		return "✅ Won"
+	}
+	return "❌ Lost"
+}
+
 func SendParlayResolutionNotification(s *discordgo.Session, db *gorm.DB, parlay models.Parlay, won bool, actualPayoutWhenWon ...float64) {
 	guild, err := guildService.GetGuildInfo(s, db, parlay.GuildID, "")
 	if err != nil || guild.BetChannelID == "" {
@@ -942,31 +1288,24 @@ func SendParlayResolutionNotification(s *discordgo.Session, db *gorm.DB, parlay
 		if len(actualPayoutWhenWon) > 0 {
 			payout = actualPayoutWhenWon[0]
 		}
-		description.WriteString(fmt.Sprintf("<@%s> Your parlay has been **won**!\n\n", user.DiscordID))
+		description.WriteString(fmt.Sprintf("<@%s> Your %s has been **won**!\n\n", user.DiscordID, parlayTypeName(parlay.Type)))
 		description.WriteString(fmt.Sprintf("**Amount Wagered:** %d points\n", parlay.Amount))
-		description.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx\n", parlay.TotalOdds))
+		description.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx%s\n", parlay.TotalOdds, parlayRepricing(parlay)))
 		description.WriteString(fmt.Sprintf("**Payout:** %.1f points\n", payout))
 	} else {
 		title = "💔 Parlay Lost"
 		color = 0xED4245
-		description.WriteString(fmt.Sprintf("<@%s> Your parlay has been **lost**.\n\n", user.DiscordID))
+		description.WriteString(fmt.Sprintf("<@%s> Your %s has been **lost**.\n\n", user.DiscordID, parlayTypeName(parlay.Type)))
 		description.WriteString(fmt.Sprintf("**Amount Wagered:** %d points\n", parlay.Amount))
-		description.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx\n", parlay.TotalOdds))
+		description.WriteString(fmt.Sprintf("**Combined Odds:** %.2fx%s\n", parlay.TotalOdds, parlayRepricing(parlay)))
 	}
 
+	teams := teamService.ForGuild(db, parlay.GuildID)
 	description.WriteString("\n**Parlay Details:**\n")
 	for idx, entry := range parlay.ParlayEntries {
-		optionName := entry.Bet.Option1
-		if entry.SelectedOption == 2 {
-			optionName = entry.Bet.Option2
-		}
+		optionName := teams.Option(common.GetOptionName(entry.Bet, entry.SelectedOption))
 
-		status := "✅ Won"
-		if entry.Won != nil && !*entry.Won {
-			status = "❌ Lost"
-		} else if !entry.Resolved {
-			status = "⏳ Pending"
-		}
+		status := parlayEntryStatus(entry)
 
 		description.WriteString(fmt.Sprintf("%d. %s: **%s** - %s\n", idx+1, entry.Bet.Description, optionName, status))
 	}
@@ -985,6 +1324,48 @@ func SendParlayResolutionNotification(s *discordgo.Session, db *gorm.DB, parlay
 	}
 }
 
+func SendParlayRefundNotification(s *discordgo.Session, db *gorm.DB, parlay models.Parlay) {
+	guild, err := guildService.GetGuildInfo(s, db, parlay.GuildID, "")
+	if err != nil || guild.BetChannelID == "" {
+		return
+	}
+
+	var user models.User
+	db.First(&user, parlay.UserID)
+	if user.ID == 0 {
+		return
+	}
+
+	title := "➖ Parlay Pushed"
+	if parlay.Status == "void" {
+		title = "🚫 Parlay Voided"
+	}
+
+	var description strings.Builder
+	description.WriteString(fmt.Sprintf("<@%s> No priced legs are left on your %s, so your wager has been **refunded**.\n\n", user.DiscordID, parlayTypeName(parlay.Type)))
+	description.WriteString(fmt.Sprintf("**Amount Refunded:** %d points\n", parlay.Amount))
+
+	teams := teamService.ForGuild(db, parlay.GuildID)
+	description.WriteString("\n**Parlay Details:**\n")
+	for idx, entry := range parlay.ParlayEntries {
+		optionName := teams.Option(common.GetOptionName(entry.Bet, entry.SelectedOption))
+		description.WriteString(fmt.Sprintf("%d. %s: **%s** - %s\n", idx+1, entry.Bet.Description, optionName, parlayEntryStatus(entry)))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description.String(),
+		Color:       0x95A5A6,
+	}
+
+	_, err = s.ChannelMessageSendComplex(guild.BetChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		fmt.Printf("Error sending parlay refund notification: %v\n", err)
+	}
+}
+
 func MyParlays(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: i.Member.User.ID, GuildID: i.GuildID})
@@ -994,7 +1375,7 @@ func MyParlays(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 	}
 
 	var parlays []models.Parlay
-	result = db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").
+	result = db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").Preload("ParlayEntries.Bet.Options").
 		Where("user_id = ? AND guild_id = ? AND status IN ?", user.ID, i.GuildID, []string{"pending", "partial"}).
 		Find(&parlays)
 
@@ -1003,6 +1384,12 @@ func MyParlays(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return
 	}
 
+	parlays, err := loadTicketParlays(db, parlays)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
 	if len(parlays) == 0 {
 		embed := &discordgo.MessageEmbed{
 			Title:       "🎯 Your Active Parlays",
@@ -1022,53 +1409,31 @@ func MyParlays(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return
 	}
 
+	teams := teamService.ForGuild(db, i.GuildID)
+	tickets := groupParlayTickets(parlays)
 	var embeds []*discordgo.MessageEmbed
-
-	for parlayIdx, parlay := range parlays {
-		potentialPayout := common.CalculateParlayPayout(parlay.Amount, parlay.TotalOdds)
-
-		description := fmt.Sprintf("**Amount:** %d points\n**Odds:** %.2fx\n**Potential Payout:** %.1f points", parlay.Amount, parlay.TotalOdds, potentialPayout)
-
-		var fields []*discordgo.MessageEmbedField
-		for entryIdx, entry := range parlay.ParlayEntries {
-			optionName := entry.Bet.Option1
-			if entry.SelectedOption == 2 {
-				optionName = entry.Bet.Option2
-			}
-
-			status := "⏳ Pending"
-			if entry.Resolved {
-				if entry.Won != nil && *entry.Won {
-					status = "✅ Won"
-				} else {
-					status = "❌ Lost"
-				}
-			}
-
-			fieldValue := fmt.Sprintf("**%s**\n%s", optionName, status)
-			fields = append(fields, &discordgo.MessageEmbedField{
-				Name:   fmt.Sprintf("Leg %d: %s", entryIdx+1, entry.Bet.Description),
-				Value:  fieldValue,
-				Inline: false,
-			})
+	var cashOutButtons []discordgo.MessageComponent
+	for ticketIdx, ticket := range tickets {
+		embed := parlayTicketEmbed(teams, ticketIdx+1, ticket)
+		offer, parlayID, err := ticketCashOut(ticket)
+		var blocked *CashOutBlockedError
+		switch {
+		case err == nil:
+			embed.Description += fmt.Sprintf("\n**Cash Out:** %.1f points", offer)
+			cashOutButtons = append(cashOutButtons, cashOutButton(fmt.Sprintf("Cash Out #%d (%.1f)", ticketIdx+1, offer), "parlay", parlayID, offer))
+		case errors.As(err, &blocked):
+			embed.Description += fmt.Sprintf("\n**Cash Out:** unavailable, %s", blocked.Error())
 		}
-
-		embed := &discordgo.MessageEmbed{
-			Title:       fmt.Sprintf("🎯 Parlay #%d", parlayIdx+1),
-			Description: description,
-			Fields:      fields,
-			Color:       0x5865F2,
-		}
-
 		embeds = append(embeds, embed)
 	}
 
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("You have %d active parlay(s):", len(parlays)),
-			Embeds:  embeds,
-			Flags:   discordgo.MessageFlagsEphemeral,
+			Content:    fmt.Sprintf("You have %d active parlay(s):", len(tickets)),
+			Embeds:     embeds,
+			Components: cashOutButtonRows(cashOutButtons),
+			Flags:      discordgo.MessageFlagsEphemeral,
 		},
 	})
 	if err != nil {
@@ -1,6 +1,7 @@
 package betService
 
 import (
+	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
 	"testing"
 )
@@ -584,3 +585,151 @@ func TestAllEntriesLoseParlayResolution(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiOptionBetOdds(t *testing.T) {
+	bet := models.Bet{
+		Option1: "Team A",
+		Option2: "Team B",
+		Odds1:   -110,
+		Odds2:   -110,
+		Options: []models.BetOption{
+			{OptionNumber: 3, Name: "Team C", Odds: 300},
+			{OptionNumber: 1, Name: "Team A", Odds: 150},
+			{OptionNumber: 4, Name: "Field", Odds: 500},
+			{OptionNumber: 2, Name: "Team B", Odds: 200},
+		},
+	}
+
+	options := common.GetBetOptions(bet)
+	assertEqual(t, 4, len(options), "all options returned")
+	for idx, option := range options {
+		assertEqual(t, idx+1, option.OptionNumber, "options ordered by option number")
+	}
+
+	assertEqual(t, 300, common.GetOddsFromBet(bet, 3), "odds for option 3")
+	assertEqual(t, "Field", common.GetOptionName(bet, 4), "name for option 4")
+	assertEqual(t, true, common.IsValidBetOption(bet, 4), "option 4 is valid")
+	assertEqual(t, false, common.IsValidBetOption(bet, 5), "option 5 is invalid")
+	assertEqual(t, 400.0, common.CalculatePayout(100, 3, bet), "payout for option 3 at +300")
+
+	multiplier := common.CalculateParlayOddsMultiplier([]int{common.GetOddsFromBet(bet, 4), common.GetOddsFromBet(bet, 1)})
+	assertEqual(t, 15.0, multiplier, "parlay multiplier with a multi-option leg")
+
+	legacy := models.Bet{Option1: "Home", Option2: "Away", Odds1: -110, Odds2: 120}
+	assertEqual(t, 2, len(common.GetBetOptions(legacy)), "legacy bets expose two options")
+	assertEqual(t, "Away", common.GetOptionName(legacy, 2), "legacy option 2 name")
+	assertEqual(t, 120, common.GetOddsFromBet(legacy, 2), "legacy option 2 odds")
+	assertEqual(t, false, common.IsValidBetOption(legacy, 3), "legacy bets have no option 3")
+}
+
+func TestParlayEntryOutcome_Push(t *testing.T) {
+	tests := []struct {
+		name          string
+		bet           models.Bet
+		entry         models.ParlayEntry
+		winningOption int
+		scoreDiff     int
+		totalScore    int
+		expectedWon   bool
+		expectedPush  bool
+	}{
+		{
+			name:          "Moneyline tie pushes",
+			bet:           models.Bet{Spread: nil},
+			entry:         models.ParlayEntry{SelectedOption: 1},
+			winningOption: 0,
+			scoreDiff:     0,
+			totalScore:    48,
+			expectedPush:  true,
+		},
+		{
+			name:          "Spread lands on the number",
+			bet:           models.Bet{Spread: floatPtr(-7.5)},
+			entry:         models.ParlayEntry{SelectedOption: 2, Spread: floatPtr(-7.0)},
+			winningOption: 1,
+			scoreDiff:     7,
+			totalScore:    45,
+			expectedPush:  true,
+		},
+		{
+			name:          "Spread entry uses its own line",
+			bet:           models.Bet{Spread: floatPtr(-7.0)},
+			entry:         models.ParlayEntry{SelectedOption: 1, Spread: floatPtr(-6.5)},
+			winningOption: 1,
+			scoreDiff:     7,
+			totalScore:    45,
+			expectedWon:   true,
+		},
+		{
+			name:          "Total lands on the number",
+			bet:           models.Bet{Total: floatPtr(48.5)},
+			entry:         models.ParlayEntry{SelectedOption: 1, Total: floatPtr(48.0)},
+			winningOption: 2,
+			scoreDiff:     3,
+			totalScore:    48,
+			expectedPush:  true,
+		},
+		{
+			name:          "Manual resolution never pushes",
+			bet:           models.Bet{},
+			entry:         models.ParlayEntry{SelectedOption: 2},
+			winningOption: 2,
+			expectedWon:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			won, push := parlayEntryOutcome(tt.bet, tt.entry, BetResult{WinningOption: tt.winningOption, ScoreDiff: tt.scoreDiff, TotalScore: tt.totalScore, Scored: tt.totalScore != 0})
+			assertEqual(t, tt.expectedPush, push, "push")
+			assertEqual(t, tt.expectedWon, won, "won")
+		})
+	}
+}
+
+func TestParlayPushReprice(t *testing.T) {
+	pushedLeg := -110
+	remaining := []int{-110, 150}
+
+	before := common.CalculateParlayOddsMultiplier(append([]int{pushedLeg}, remaining...))
+	after := common.CalculateParlayOddsMultiplier(remaining)
+
+	if after >= before {
+		t.Errorf("expected repriced odds %.4f to be lower than original %.4f", after, before)
+	}
+
+	expected := (100.0/110.0 + 1.0) * 2.5
+	if diff := after - expected; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("expected repriced odds %.4f, got %.4f", expected, after)
+	}
+}
+
+func TestParlayRepricing(t *testing.T) {
+	even, favorite, underdog := 100, -200, 150
+	parlay := models.Parlay{ParlayEntries: []models.ParlayEntry{
+		{Odds: &even},
+		{Odds: &favorite},
+		{Odds: &underdog},
+	}}
+	assertEqual(t, "", parlayRepricing(parlay), "no dropped legs")
+
+	parlay.ParlayEntries[0].Resolved = true
+	parlay.ParlayEntries[0].Push = true
+	assertEqual(t, " (repriced from 7.50x after 1 push)", parlayRepricing(parlay), "one push")
+
+	parlay.ParlayEntries[1].Resolved = true
+	parlay.ParlayEntries[1].Void = true
+	assertEqual(t, " (repriced from 7.50x after 1 push and 1 void)", parlayRepricing(parlay), "push and void")
+}
+
+func TestParlayLegLockedOdds(t *testing.T) {
+	locked := -120
+	bet := models.Bet{Option1: "Alabama -6.5", Option2: "Auburn +6.5", Odds1: -150, Odds2: 130, Spread: floatPtr(-6.5)}
+
+	leg := models.ParlayEntry{Bet: bet, SelectedOption: 2, Spread: floatPtr(-3.5), Odds: &locked}
+	assertEqual(t, -120, common.GetParlayEntryOdds(leg), "locked odds")
+	assertEqual(t, "Auburn +3.5", parlayLegOptionName(nil, leg), "locked option name")
+
+	legacy := models.ParlayEntry{Bet: bet, SelectedOption: 2}
+	assertEqual(t, 130, common.GetParlayEntryOdds(legacy), "legacy leg uses the bet's odds")
+}
@@ -0,0 +1,317 @@
+package betService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/teamService"
+	"sort"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+const (
+	// TeaserPoints is how far a teaser moves each spread toward the bettor.
+	TeaserPoints = 6.0
+	// TeaserLegOdds prices every teaser leg, so two legs pay about -120 and
+	// three about +150.
+	TeaserLegOdds = -280
+	// maxParlayLegs is the most bets the parlay selector offers at once.
+	maxParlayLegs = 10
+)
+
+// parlayTypeName is how a kind of parlay is named in messages.
+func parlayTypeName(parlayType string) string {
+	switch parlayType {
+	case models.ParlayRoundRobin:
+		return "round robin parlay"
+	case models.ParlayTeaser:
+		return "teaser"
+	default:
+		return "parlay"
+	}
+}
+
+func parlayTypeTitle(parlayType string) string {
+	switch parlayType {
+	case models.ParlayRoundRobin:
+		return "Round Robin"
+	case models.ParlayTeaser:
+		return "Teaser"
+	default:
+		return "Parlay"
+	}
+}
+
+func (selection *ParlaySelection) title() string {
+	switch selection.Type {
+	case models.ParlayRoundRobin:
+		return "🔄 Create Round Robin"
+	case models.ParlayTeaser:
+		return "🎯 Create Teaser"
+	default:
+		return "🎯 Create Parlay"
+	}
+}
+
+// minLegs is the fewest bets the selection can be built from. A round robin
+// needs more bets than legs per parlay, or it would be a single parlay.
+func (selection *ParlaySelection) minLegs() int {
+	if selection.Type == models.ParlayRoundRobin {
+		return selection.Size + 1
+	}
+	return 2
+}
+
+// note explains how the selection's kind of parlay is priced, or is empty for
+// a standard parlay.
+func (selection *ParlaySelection) note() string {
+	switch selection.Type {
+	case models.ParlayRoundRobin:
+		return fmt.Sprintf("\n\n🔄 Every %d-bet combination becomes its own parlay, with your stake split evenly across them.", selection.Size)
+	case models.ParlayTeaser:
+		return fmt.Sprintf("\n\n🔧 Every spread moves %s points your way, and each leg is priced at %s.", formatPoints(TeaserPoints), common.FormatOdds(TeaserLegOdds))
+	default:
+		return ""
+	}
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// teaserEligible reports whether a bet can be a teaser leg. Only spreads can be
+// moved, so moneylines and totals are left out.
+func teaserEligible(bet models.Bet) bool {
+	return bet.Spread != nil && bet.Total == nil
+}
+
+// teaseSpread moves a spread points toward the selected option. Spreads are
+// stored as option 1's line, so a larger spread favors option 1.
+func teaseSpread(spread float64, option int, points float64) float64 {
+	if option == 1 {
+		return spread + points
+	}
+	return spread - points
+}
+
+// roundRobinCombinations returns every way to choose size of n legs, as leg
+// indexes in ascending order.
+func roundRobinCombinations(n int, size int) [][]int {
+	var combinations [][]int
+	if size <= 0 || size > n {
+		return combinations
+	}
+
+	combination := make([]int, size)
+	var choose func(start int, depth int)
+	choose = func(start int, depth int) {
+		if depth == size {
+			combinations = append(combinations, append([]int(nil), combination...))
+			return
+		}
+		for idx := start; idx <= n-(size-depth); idx++ {
+			combination[depth] = idx
+			choose(idx+1, depth+1)
+		}
+	}
+	choose(0, 0)
+	return combinations
+}
+
+// parlayLegs builds a leg for each selected bet at the line and odds it is
+// placed at. Teaser legs are moved TeaserPoints and priced at TeaserLegOdds.
+func parlayLegs(selection *ParlaySelection, bets []models.Bet) []models.ParlayEntry {
+	var legs []models.ParlayEntry
+	for _, bet := range bets {
+		option := selection.SelectedOptions[bet.ID]
+		odds := common.GetOddsFromBet(bet, option)
+		spread := bet.Spread
+		if selection.Type == models.ParlayTeaser && bet.Spread != nil {
+			teased := teaseSpread(*bet.Spread, option, TeaserPoints)
+			spread = &teased
+			odds = TeaserLegOdds
+		}
+		legs = append(legs, models.ParlayEntry{
+			BetID:          bet.ID,
+			Bet:            bet,
+			SelectedOption: option,
+			Spread:         spread,
+			Total:          bet.Total,
+			Odds:           &odds,
+		})
+	}
+	return legs
+}
+
+// ticketParlays splits a ticket's legs into the parlays it is made of: one
+// for each combination of a round robin, or a single parlay otherwise.
+func ticketParlays(parlayType string, size int, legs []models.ParlayEntry) [][]models.ParlayEntry {
+	if parlayType != models.ParlayRoundRobin {
+		return [][]models.ParlayEntry{legs}
+	}
+
+	var parlays [][]models.ParlayEntry
+	for _, combination := range roundRobinCombinations(len(legs), size) {
+		parlay := make([]models.ParlayEntry, 0, len(combination))
+		for _, idx := range combination {
+			parlay = append(parlay, legs[idx])
+		}
+		parlays = append(parlays, parlay)
+	}
+	return parlays
+}
+
+func parlayLegsOdds(legs []models.ParlayEntry) float64 {
+	var oddsList []int
+	for _, leg := range legs {
+		oddsList = append(oddsList, common.GetParlayEntryOdds(leg))
+	}
+	return common.CalculateParlayOddsMultiplier(oddsList)
+}
+
+// loadTicketParlays adds the rest of each round robin ticket to a user's
+// active parlays, so a ticket shows every parlay it was split into.
+func loadTicketParlays(db *gorm.DB, parlays []models.Parlay) ([]models.Parlay, error) {
+	loaded := make(map[uint]bool)
+	var ticketIDs []string
+	for _, parlay := range parlays {
+		loaded[parlay.ID] = true
+		if parlay.TicketID != "" {
+			ticketIDs = append(ticketIDs, parlay.TicketID)
+		}
+	}
+	if len(ticketIDs) == 0 {
+		return parlays, nil
+	}
+
+	var ticketParlays []models.Parlay
+	result := db.Preload("ParlayEntries").Preload("ParlayEntries.Bet").Preload("ParlayEntries.Bet.Options").
+		Where("ticket_id IN ?", ticketIDs).
+		Find(&ticketParlays)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, parlay := range ticketParlays {
+		if !loaded[parlay.ID] {
+			parlays = append(parlays, parlay)
+			loaded[parlay.ID] = true
+		}
+	}
+	return parlays, nil
+}
+
+// groupParlayTickets groups a round robin's parlays into one ticket, ordered
+// by ID. Every other parlay is a ticket of its own.
+func groupParlayTickets(parlays []models.Parlay) [][]models.Parlay {
+	var tickets [][]models.Parlay
+	ticketIdx := make(map[string]int)
+	for _, parlay := range parlays {
+		if parlay.TicketID == "" {
+			tickets = append(tickets, []models.Parlay{parlay})
+			continue
+		}
+		if idx, found := ticketIdx[parlay.TicketID]; found {
+			tickets[idx] = append(tickets[idx], parlay)
+			continue
+		}
+		ticketIdx[parlay.TicketID] = len(tickets)
+		tickets = append(tickets, []models.Parlay{parlay})
+	}
+
+	for _, ticket := range tickets {
+		sort.Slice(ticket, func(a, b int) bool { return ticket[a].ID < ticket[b].ID })
+	}
+	return tickets
+}
+
+// ticketLegs returns each bet on a ticket once, in the order it was picked.
+// A leg is marked resolved without being graded when its parlay has already
+// lost, so a graded copy of the leg is preferred.
+func ticketLegs(ticket []models.Parlay) []models.ParlayEntry {
+	var legs []models.ParlayEntry
+	legIdx := make(map[uint]int)
+	for _, parlay := range ticket {
+		for _, entry := range parlay.ParlayEntries {
+			idx, found := legIdx[entry.BetID]
+			if !found {
+				legIdx[entry.BetID] = len(legs)
+				legs = append(legs, entry)
+				continue
+			}
+			if legGraded(entry) && !legGraded(legs[idx]) {
+				legs[idx] = entry
+			}
+		}
+	}
+	return legs
+}
+
+func legGraded(entry models.ParlayEntry) bool {
+	return !entry.Resolved || entry.Won != nil || entry.Push || entry.Void
+}
+
+func parlayLegField(teams *teamService.Resolver, legNumber int, entry models.ParlayEntry) *discordgo.MessageEmbedField {
+	optionName := parlayLegOptionName(teams, entry)
+
+	status := parlayEntryStatus(entry)
+
+	odds := common.FormatOdds(float64(common.GetParlayEntryOdds(entry)))
+	if entry.Push || entry.Void {
+		// Dropped legs no longer count toward the parlay's odds.
+		odds = "~~" + odds + "~~"
+	}
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Leg %d: %s", legNumber, entry.Bet.Description),
+		Value:  fmt.Sprintf("**%s** (%s)\n%s", optionName, odds, status),
+		Inline: false,
+	}
+}
+
+// parlayTicketEmbed shows one ticket in /my-parlays. A round robin is shown as
+// a single ticket with the parlays it was split into summed up.
+func parlayTicketEmbed(teams *teamService.Resolver, ticketNumber int, ticket []models.Parlay) *discordgo.MessageEmbed {
+	first := ticket[0]
+
+	var fields []*discordgo.MessageEmbedField
+	for legIdx, entry := range ticketLegs(ticket) {
+		fields = append(fields, parlayLegField(teams, legIdx+1, entry))
+	}
+
+	if first.Type == models.ParlayRoundRobin {
+		alive, totalAmount := 0, 0
+		potentialPayout := 0.0
+		for _, parlay := range ticket {
+			totalAmount += parlay.Amount
+			if parlay.Status == "pending" || parlay.Status == "partial" {
+				alive++
+				potentialPayout += common.CalculateParlayPayout(parlay.Amount, parlay.TotalOdds)
+			}
+		}
+
+		description := fmt.Sprintf("**Parlays:** %d × %d legs, %d still alive\n**Amount:** %d points each (%d total)\n**Potential Payout:** %.1f points",
+			len(ticket), len(first.ParlayEntries), alive, first.Amount, totalAmount, potentialPayout)
+		return &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("🔄 Round Robin #%d", ticketNumber),
+			Description: description,
+			Fields:      fields,
+			Color:       0x5865F2,
+		}
+	}
+
+	potentialPayout := common.CalculateParlayPayout(first.Amount, first.TotalOdds)
+	description := fmt.Sprintf("**Amount:** %d points\n**Odds:** %.2fx%s\n**Potential Payout:** %.1f points", first.Amount, first.TotalOdds, parlayRepricing(first), potentialPayout)
+	title := fmt.Sprintf("🎯 Parlay #%d", ticketNumber)
+	if first.Type == models.ParlayTeaser {
+		description = fmt.Sprintf("**Teaser:** %s points on every spread\n%s", formatPoints(first.TeaserPoints), description)
+		title = fmt.Sprintf("🎯 Teaser #%d", ticketNumber)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description,
+		Fields:      fields,
+		Color:       0x5865F2,
+	}
+}
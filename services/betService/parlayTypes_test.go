@@ -0,0 +1,116 @@
+package betService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"testing"
+)
+
+func TestRoundRobinCombinations(t *testing.T) {
+	tests := []struct {
+		n        int
+		size     int
+		expected []string
+	}{
+		{4, 2, []string{"[0 1]", "[0 2]", "[0 3]", "[1 2]", "[1 3]", "[2 3]"}},
+		{4, 3, []string{"[0 1 2]", "[0 1 3]", "[0 2 3]", "[1 2 3]"}},
+		{3, 3, []string{"[0 1 2]"}},
+		{2, 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d choose %d", tt.n, tt.size), func(t *testing.T) {
+			combinations := roundRobinCombinations(tt.n, tt.size)
+			if len(combinations) != len(tt.expected) {
+				t.Fatalf("expected %d combinations, got %d: %v", len(tt.expected), len(combinations), combinations)
+			}
+			for idx, combination := range combinations {
+				assertEqual(t, tt.expected[idx], fmt.Sprint(combination), "combination")
+			}
+		})
+	}
+}
+
+func TestTeaseSpread(t *testing.T) {
+	// Home -7.5 teased 6 points is home -1.5, and away +7.5 is away +13.5,
+	// which is stored as option 1's line of -13.5.
+	assertEqual(t, -1.5, teaseSpread(-7.5, 1, TeaserPoints), "home favorite teased")
+	assertEqual(t, -13.5, teaseSpread(-7.5, 2, TeaserPoints), "away underdog teased")
+
+	// A teased leg grades at its moved line: home wins by 3, covering -1.5.
+	home := teaseSpread(-7.5, 1, TeaserPoints)
+	assertEqual(t, true, common.CalculateBetEntryWin(1, 3, home), "teased home leg covers")
+	away := teaseSpread(-7.5, 2, TeaserPoints)
+	assertEqual(t, true, common.CalculateBetEntryWin(2, 12, away), "teased away leg covers")
+}
+
+func TestTicketParlays(t *testing.T) {
+	bets := []models.Bet{
+		{ID: 1, Odds1: -110, Odds2: -110, Spread: floatPtr(-7.5)},
+		{ID: 2, Odds1: 150, Odds2: -180, Spread: floatPtr(3.5)},
+		{ID: 3, Odds1: -110, Odds2: -110, Spread: floatPtr(-1)},
+	}
+	selection := &ParlaySelection{
+		Type:            models.ParlayRoundRobin,
+		Size:            2,
+		SelectedOptions: map[uint]int{1: 1, 2: 1, 3: 2},
+	}
+
+	parlays := ticketParlays(selection.Type, selection.Size, parlayLegs(selection, bets))
+	if len(parlays) != 3 {
+		t.Fatalf("expected 3 parlays, got %d", len(parlays))
+	}
+	for _, parlay := range parlays {
+		assertEqual(t, 2, len(parlay), "legs per parlay")
+	}
+	assertEqual(t, uint(2), parlays[0][1].BetID, "second leg of the first parlay")
+	assertEqual(t, uint(3), parlays[2][1].BetID, "second leg of the last parlay")
+	assertEqual(t, 150, *parlays[0][1].Odds, "round robin legs keep their odds")
+	assertEqual(t, -7.5, *parlays[0][0].Spread, "round robin legs keep their line")
+
+	selection.Type = models.ParlayTeaser
+	legs := parlayLegs(selection, bets)
+	parlays = ticketParlays(selection.Type, selection.Size, legs)
+	if len(parlays) != 1 || len(parlays[0]) != 3 {
+		t.Fatalf("expected one teaser of 3 legs, got %v", parlays)
+	}
+	assertEqual(t, -1.5, *legs[0].Spread, "teased home leg")
+	assertEqual(t, 9.5, *legs[1].Spread, "teased home underdog")
+	assertEqual(t, -7.0, *legs[2].Spread, "teased away leg")
+	for _, leg := range legs {
+		assertEqual(t, TeaserLegOdds, *leg.Odds, "teaser leg odds")
+	}
+	assertEqual(t, -7.5, *bets[0].Spread, "the bet's own line is unchanged")
+}
+
+func TestGroupParlayTickets(t *testing.T) {
+	won := true
+	lost := false
+	parlays := []models.Parlay{
+		{ID: 7, TicketID: "rr", Status: "pending", ParlayEntries: []models.ParlayEntry{
+			{BetID: 2, Resolved: true, Won: &won},
+			{BetID: 3},
+		}},
+		{ID: 4, Status: "pending"},
+		{ID: 5, TicketID: "rr", Status: "lost", ParlayEntries: []models.ParlayEntry{
+			{BetID: 1, Resolved: true, Won: &lost},
+			{BetID: 2, Resolved: true},
+		}},
+	}
+
+	tickets := groupParlayTickets(parlays)
+	if len(tickets) != 2 {
+		t.Fatalf("expected 2 tickets, got %d", len(tickets))
+	}
+	assertEqual(t, 2, len(tickets[0]), "round robin parlays grouped")
+	assertEqual(t, uint(5), tickets[0][0].ID, "round robin parlays ordered by ID")
+	assertEqual(t, uint(4), tickets[1][0].ID, "standard parlay on its own")
+
+	legs := ticketLegs(tickets[0])
+	if len(legs) != 3 {
+		t.Fatalf("expected 3 distinct legs, got %d", len(legs))
+	}
+	assertEqual(t, uint(1), legs[0].BetID, "legs in the order they were picked")
+	assertEqual(t, "✅ Won", parlayEntryStatus(legs[1]), "a graded copy of a leg is shown")
+}
@@ -0,0 +1,336 @@
+package betService
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/messageService"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBetNotSettled is returned when reversing a bet that has not been paid out.
+var ErrBetNotSettled = errors.New("bet has not been settled")
+
+// ErrNoSettlementJournal is returned when a paid bet has no settlement left to
+// reverse, either because it was paid before settlements were journaled or
+// because its settlement was already reversed.
+var ErrNoSettlementJournal = errors.New("no settlement journal to reverse")
+
+// ReverseSettlement undoes a bet's latest settlement from its journal. User
+// points and betting stats, the cards it consumed, created or changed, the pool
+// and parlay state are put back in one transaction, and the bet is left unpaid so it can be resolved
+// again. Points a user has spent since the settlement are not clawed back in any
+// other way, so a balance can go negative.
+func ReverseSettlement(db *gorm.DB, betID uint) (*models.SettlementJournal, error) {
+	var journal *models.SettlementJournal
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		journal, err = reverseInTransaction(tx, betID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// ReverseAndResettle reverses a bet's latest settlement and settles the bet
+// again in the same transaction, so if settling again fails the original
+// payout stays in place. A bet that was graded from its final score is graded
+// from that score again, with each entry's own line; any other bet is resolved
+// on winningOption, or pushed when it is PushOption.
+func ReverseAndResettle(s *discordgo.Session, db *gorm.DB, betID uint, winningOption int) (*models.SettlementJournal, *Settlement, error) {
+	var bet models.Bet
+	if err := db.First(&bet, betID).Error; err != nil {
+		return nil, nil, err
+	}
+	guild, err := guildService.GetGuildInfo(s, db, bet.GuildID, bet.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var journal *models.SettlementJournal
+	var settlement *Settlement
+	var notifications notificationQueue
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		journal, err = reverseInTransaction(tx, betID)
+		if err != nil {
+			return err
+		}
+
+		settlement, err = settleInTransaction(s, tx, betID, *guild, resettleResult(bet, *journal, winningOption), false, &notifications)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notifications.flush(db)
+	return journal, settlement, nil
+}
+
+// resettleResult is the result a reversed bet is settled on again: the score it
+// was graded from, or else the admin's pick.
+func resettleResult(bet models.Bet, journal models.SettlementJournal, winningOption int) BetResult {
+	if journal.Scored {
+		return ScoreResult(bet, journal.ScoreDiff, journal.TotalScore)
+	}
+	return ManualResult(winningOption)
+}
+
+func reverseInTransaction(tx *gorm.DB, betID uint) (*models.SettlementJournal, error) {
+	var bet models.Bet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bet, betID).Error; err != nil {
+		return nil, err
+	}
+	if !bet.Paid || bet.Voided {
+		return nil, ErrBetNotSettled
+	}
+
+	var journal models.SettlementJournal
+	err := tx.Preload("Entries").Where("bet_id = ? AND reversed_at IS NULL", bet.ID).Order("id DESC").First(&journal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoSettlementJournal
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range journal.Entries {
+		if err := reverseJournalEntry(tx, bet.ID, line); err != nil {
+			return nil, fmt.Errorf("error reversing %s journal entry %d: %v", line.Kind, line.ID, err)
+		}
+	}
+
+	if journal.PoolDelta != 0 {
+		if err := tx.Model(&models.Guild{}).Where("guild_id = ?", bet.GuildID).UpdateColumn("pool", gorm.Expr("pool - ?", journal.PoolDelta)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Model(&bet).Updates(map[string]interface{}{"paid": false, "active": journal.BetWasActive}).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	journal.ReversedAt = &now
+	if err := tx.Model(&journal).Update("reversed_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &journal, nil
+}
+
+func reverseJournalEntry(tx *gorm.DB, betID uint, line models.SettlementJournalEntry) error {
+	switch line.Kind {
+	case models.JournalEntryUser:
+		if line.UserID == nil {
+			return nil
+		}
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, *line.UserID).Error; err != nil {
+			return err
+		}
+		if err := ledgerService.Apply(tx, &user, -line.PointsDelta, ledgerService.Bet(ledgerService.ReasonSettlementReversal, betID)); err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{
+			"total_bets_won":    gorm.Expr("total_bets_won - ?", line.TotalBetsWonDelta),
+			"total_bets_lost":   gorm.Expr("total_bets_lost - ?", line.TotalBetsLostDelta),
+			"total_points_won":  gorm.Expr("total_points_won - ?", line.TotalPointsWonDelta),
+			"total_points_lost": gorm.Expr("total_points_lost - ?", line.TotalPointsLostDelta),
+		}).Error
+	case models.JournalEntryInventory, models.JournalEntryInventoryUpdated:
+		if line.InventoryID == nil {
+			return nil
+		}
+		restored := map[string]interface{}{
+			"target_bet_id":  line.InventoryTargetBetID,
+			"target_user_id": line.InventoryTargetUserID,
+			"bet_amount":     line.InventoryBetAmount,
+			"times_applied":  line.InventoryTimesApplied,
+			"expires_at":     line.InventoryExpiresAt,
+		}
+		if line.Kind == models.JournalEntryInventory {
+			restored["deleted_at"] = nil
+		}
+		return tx.Unscoped().Model(&models.UserInventory{}).Where("id = ?", *line.InventoryID).Updates(restored).Error
+	case models.JournalEntryInventoryCreated:
+		if line.InventoryID == nil {
+			return nil
+		}
+		return tx.Delete(&models.UserInventory{}, *line.InventoryID).Error
+	case models.JournalEntryParlay:
+		if line.ParlayID == nil {
+			return nil
+		}
+		return tx.Model(&models.Parlay{}).Where("id = ?", *line.ParlayID).Updates(map[string]interface{}{
+			"status":     line.ParlayStatus,
+			"total_odds": line.ParlayTotalOdds,
+		}).Error
+	case models.JournalEntryParlayEntry:
+		if line.ParlayEntryID == nil {
+			return nil
+		}
+		return tx.Model(&models.ParlayEntry{}).Where("id = ?", *line.ParlayEntryID).Updates(map[string]interface{}{
+			"resolved": line.Resolved,
+			"won":      line.Won,
+			"push":     line.Push,
+		}).Error
+	case models.JournalEntryBetEntry:
+		if line.BetEntryID == nil {
+			return nil
+		}
+		return tx.Model(&models.BetEntry{}).Where("id = ?", *line.BetEntryID).Updates(map[string]interface{}{
+			"auto_close_win":  line.AutoCloseWin,
+			"auto_close_push": line.AutoClosePush,
+		}).Error
+	}
+	return nil
+}
+
+// ReverseBet handles /reverse-bet. The bet's settlement is reversed and, when an
+// option is given, the bet is resolved again in the same transaction: on that
+// option, or from the final score for a bet that was graded from one.
+func ReverseBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		respondEphemeral(s, i, db, "You are not authorized to use this command.")
+		return
+	}
+
+	commandOptions := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		commandOptions[opt.Name] = opt
+	}
+
+	betID := commandOptions["bet-id"].IntValue()
+
+	var bet models.Bet
+	if err := db.Preload("Options").First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID).Error; err != nil {
+		respondEphemeral(s, i, db, fmt.Sprintf("Bet #%d not found.", betID))
+		return
+	}
+
+	reResolveOpt, reResolve := commandOptions["re-resolve"]
+	reResolveOption := PushOption
+	if reResolve {
+		reResolveOption = int(reResolveOpt.IntValue())
+		if reResolveOption != PushOption && !common.IsValidBetOption(bet, reResolveOption) {
+			respondEphemeral(s, i, db, fmt.Sprintf("Bet #%d has no option %d.", bet.ID, reResolveOption))
+			return
+		}
+	}
+
+	var journal *models.SettlementJournal
+	var settlement *Settlement
+	var err error
+	if reResolve {
+		journal, settlement, err = ReverseAndResettle(s, db, bet.ID, reResolveOption)
+	} else {
+		journal, err = ReverseSettlement(db, bet.ID)
+	}
+	if errors.Is(err, ErrBetNotSettled) {
+		respondEphemeral(s, i, db, fmt.Sprintf("Bet #%d has not been paid out, so there is nothing to reverse.", bet.ID))
+		return
+	}
+	if errors.Is(err, ErrNoSettlementJournal) {
+		respondEphemeral(s, i, db, fmt.Sprintf("Bet #%d has no settlement on record to reverse.", bet.ID))
+		return
+	}
+	if err != nil {
+		common.SendError(s, i, fmt.Errorf("error reversing bet %d, so its payout was left in place: %v", bet.ID, err), db)
+		return
+	}
+
+	corrections, returnedCards := describeReversal(s, db, bet.GuildID, journal)
+	subtitle := fmt.Sprintf("Reversed the payout on **%s**. The bet is open for resolution again.", common.GetOptionName(bet, journal.WinningOption))
+	if journal.WinningOption == 0 {
+		subtitle = "Reversed the payout. The bet is open for resolution again."
+	}
+	embeds := []*discordgo.MessageEmbed{
+		messageService.BuildSettlementReversalEmbed(bet.Description, subtitle, -journal.PoolDelta, corrections, returnedCards),
+	}
+
+	if settlement != nil {
+		resolution := ResultSubtitle(settlement.Bet, reResolveOption)
+		if journal.Scored {
+			resolution = "Graded again from the final score."
+		}
+		embeds = append(embeds, messageService.BuildBetResolutionEmbed(
+			settlement.Bet.Description,
+			resolution,
+			settlement.TotalPayout,
+			settlement.Winners,
+			settlement.Losers,
+			settlement.Pushes,
+		))
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: embeds,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
+
+// describeReversal lists the point corrections and returned cards for the
+// reversal embed.
+func describeReversal(s *discordgo.Session, db *gorm.DB, guildID string, journal *models.SettlementJournal) (string, string) {
+	var corrections, returnedCards strings.Builder
+	for _, line := range journal.Entries {
+		switch {
+		case line.Kind == models.JournalEntryUser && line.UserID != nil && line.PointsDelta != 0:
+			var user models.User
+			if err := db.First(&user, *line.UserID).Error; err != nil {
+				continue
+			}
+			corrections.WriteString(fmt.Sprintf("%s - **%+.1f** points\n", common.GetUsernameWithDB(db, s, guildID, user.DiscordID), -line.PointsDelta))
+		case (line.Kind == models.JournalEntryInventory || line.Kind == models.JournalEntryInventoryCreated) && line.InventoryID != nil:
+			var inventory models.UserInventory
+			if err := db.Unscoped().First(&inventory, *line.InventoryID).Error; err != nil {
+				continue
+			}
+			var user models.User
+			if err := db.First(&user, inventory.UserID).Error; err != nil {
+				continue
+			}
+			cardName := "Card"
+			if card := cardService.GetCardByID(inventory.CardID); card != nil {
+				cardName = card.Name
+			}
+			change := "returned to inventory"
+			if line.Kind == models.JournalEntryInventoryCreated {
+				change = "taken back from inventory"
+			}
+			returnedCards.WriteString(fmt.Sprintf("%s - **%s** %s\n", common.GetUsernameWithDB(db, s, guildID, user.DiscordID), cardName, change))
+		}
+	}
+	return strings.TrimSpace(corrections.String()), strings.TrimSpace(returnedCards.String())
+}
+
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
@@ -0,0 +1,694 @@
+package betService
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBetAlreadySettled is returned when a bet has already been paid out or voided.
+var ErrBetAlreadySettled = errors.New("bet has already been settled")
+
+// errDryRun rolls back the transaction used to preview a settlement.
+var errDryRun = errors.New("settlement preview")
+
+// BetResult describes how a bet finished. A bet resolved by an admin only knows
+// its winning option. A bet graded from a final score also carries the score, and
+// each entry is graded against the line it was placed at.
+type BetResult struct {
+	WinningOption int
+	ScoreDiff     int
+	TotalScore    int
+	Scored        bool
+}
+
+// PushOption is the winning option of a bet that pushed: no option won, and
+// every stake is refunded.
+const PushOption = 0
+
+// ManualResult is the result of an admin picking the winning option, or
+// PushOption to push the bet.
+func ManualResult(winningOption int) BetResult {
+	return BetResult{WinningOption: winningOption}
+}
+
+// ResultSubtitle describes a bet's result for the resolution embed.
+func ResultSubtitle(bet models.Bet, winningOption int) string {
+	if winningOption == PushOption {
+		return "Result: **Push**. Every stake is refunded."
+	}
+	return fmt.Sprintf("Winning option: **%s**", common.GetOptionName(bet, winningOption))
+}
+
+// ScoreResult is the result of a game bet graded from its final score.
+func ScoreResult(bet models.Bet, scoreDiff int, totalScore int) BetResult {
+	return BetResult{
+		WinningOption: winningOptionForScore(bet, scoreDiff, totalScore),
+		ScoreDiff:     scoreDiff,
+		TotalScore:    totalScore,
+		Scored:        true,
+	}
+}
+
+// Settlement is the outcome of settling a bet, ready to be posted.
+type Settlement struct {
+	Bet         models.Bet
+	Guild       models.Guild
+	Entries     int
+	TotalPayout float64
+	Winners     string
+	Losers      string
+	Pushes      string
+	// BasePayouts lists each winner's payout before cards are applied.
+	BasePayouts string
+	// CardsPlayed lists the inventory cards that trigger, per user.
+	CardsPlayed string
+	// ParlayLegs lists the parlay legs on the bet and the parlay they leave behind.
+	ParlayLegs string
+	PoolDelta  float64
+}
+
+type entryGrade int
+
+const (
+	gradeLoss entryGrade = iota
+	gradeWin
+	gradePush
+)
+
+// notificationQueue holds Discord posts raised while a settlement transaction is
+// open. They are sent once the transaction commits, so nobody is told about a
+// payout that was rolled back.
+type notificationQueue []func(db *gorm.DB)
+
+func (q *notificationQueue) add(fn func(db *gorm.DB)) {
+	*q = append(*q, fn)
+}
+
+func (q notificationQueue) flush(db *gorm.DB) {
+	for _, fn := range q {
+		fn(db)
+	}
+}
+
+// SettleBet pays out a bet. Every entry, card effect, pool movement and parlay
+// leg is settled inside a single transaction, so an error part way through
+// leaves the bet untouched. The changes are journaled so the settlement can be
+// undone with ReverseSettlement. Card and parlay notifications are posted only after
+// the transaction commits; posting the resolution embed is left to the caller.
+func SettleBet(s *discordgo.Session, db *gorm.DB, betID uint, result BetResult) (*Settlement, error) {
+	return settleBet(s, db, betID, result, false)
+}
+
+// PreviewSettlement works out what SettleBet would do for result without
+// keeping anything. The settlement runs in a transaction that is rolled back,
+// and no Discord messages are sent. It reads and writes the same rows under the
+// same locks as SettleBet, so a confirmed settlement of an unchanged bet matches
+// its preview; like SettleBet, it holds up bets in the guild until it finishes.
+func PreviewSettlement(s *discordgo.Session, db *gorm.DB, betID uint, result BetResult) (*Settlement, error) {
+	return settleBet(s, db, betID, result, true)
+}
+
+func settleBet(s *discordgo.Session, db *gorm.DB, betID uint, result BetResult, dryRun bool) (*Settlement, error) {
+	var bet models.Bet
+	if err := db.First(&bet, betID).Error; err != nil {
+		return nil, err
+	}
+	if bet.Paid || bet.Voided {
+		return nil, ErrBetAlreadySettled
+	}
+
+	guild, err := guildService.GetGuildInfo(s, db, bet.GuildID, bet.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var settlement *Settlement
+	var notifications notificationQueue
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		settlement, err = settleInTransaction(s, tx, betID, *guild, result, dryRun, &notifications)
+		return err
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	if !dryRun {
+		notifications.flush(db)
+	}
+	return settlement, nil
+}
+
+// settleInTransaction settles a bet inside tx and journals the settlement. The
+// caller posts the queued notifications once tx commits. A dry run returns the
+// settlement along with errDryRun, so the caller's transaction rolls back.
+func settleInTransaction(s *discordgo.Session, tx *gorm.DB, betID uint, guild models.Guild, result BetResult, dryRun bool, notifications *notificationQueue) (*Settlement, error) {
+	var locked models.Bet
+	if err := forUpdate(tx).Preload("Options").First(&locked, betID).Error; err != nil {
+		return nil, err
+	}
+	if locked.Paid || locked.Voided {
+		return nil, ErrBetAlreadySettled
+	}
+
+	snapshot, err := takeSettlementSnapshot(tx, locked)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &settlementRun{
+		s:                s,
+		tx:               tx,
+		bet:              locked,
+		result:           result,
+		dryRun:           dryRun,
+		notifications:    notifications,
+		winnerDiscordIDs: make(map[string]float64),
+	}
+	if err := run.settle(); err != nil {
+		return nil, err
+	}
+
+	journal, err := snapshot.journal(tx, run.bet, result)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &Settlement{
+		Bet:         run.bet,
+		Guild:       guild,
+		Entries:     run.entries,
+		TotalPayout: run.totalPayout,
+		Winners:     strings.TrimSpace(run.winners),
+		Losers:      strings.TrimSpace(run.losers),
+		Pushes:      strings.TrimSpace(run.pushes),
+		BasePayouts: strings.TrimSpace(run.basePayouts),
+		CardsPlayed: strings.TrimSpace(run.cardsPlayed),
+		ParlayLegs:  strings.TrimSpace(run.parlayLegs),
+		PoolDelta:   journal.PoolDelta,
+	}
+
+	if dryRun {
+		return settlement, errDryRun
+	}
+	return settlement, tx.Create(journal).Error
+}
+
+// forUpdate locks the rows tx reads until the transaction ends. A preview takes
+// the same locks, since its writes lock the rows anyway and a preview built from
+// unlocked reads could differ from the settlement it describes.
+func forUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+func guildPool(tx *gorm.DB, guildID string) (float64, error) {
+	var guild models.Guild
+	if err := tx.Select("pool").Where("guild_id = ?", guildID).First(&guild).Error; err != nil {
+		return 0, err
+	}
+	return guild.Pool, nil
+}
+
+type settlementRun struct {
+	s             *discordgo.Session
+	tx            *gorm.DB
+	bet           models.Bet
+	result        BetResult
+	dryRun        bool
+	notifications *notificationQueue
+
+	entries     int
+	winners     string
+	losers      string
+	pushes      string
+	basePayouts string
+	cardsPlayed string
+	parlayLegs  string
+
+	totalPayout         float64
+	totalWinningPayouts float64
+	lostPoolAmount      float64
+	winnerDiscordIDs    map[string]float64
+}
+
+func (r *settlementRun) settle() error {
+	var entries []models.BetEntry
+	if err := r.tx.Where("bet_id = ? AND deleted_at IS NULL", r.bet.ID).Find(&entries).Error; err != nil {
+		return err
+	}
+	r.entries = len(entries)
+
+	for _, entry := range entries {
+		var user models.User
+		if err := forUpdate(r.tx).First(&user, "id = ?", entry.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+
+		grade := gradeBetEntry(r.bet, entry, r.result)
+		if r.result.Scored && grade != gradeLoss {
+			entry.AutoClosePush = grade == gradePush
+			entry.AutoCloseWin = grade == gradeWin
+			if err := r.tx.Save(&entry).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch grade {
+		case gradePush:
+			err = r.settlePush(user, entry)
+		case gradeWin:
+			err = r.settleWin(user, entry)
+		default:
+			err = r.settleLoss(user, entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.totalWinningPayouts > 0 {
+		if err := r.applyWinnerPoolCards(); err != nil {
+			return err
+		}
+	}
+
+	if r.lostPoolAmount > 0 {
+		if err := r.tx.Model(&models.Guild{}).Where("guild_id = ?", r.bet.GuildID).UpdateColumn("pool", gorm.Expr("pool + ?", r.lostPoolAmount)).Error; err != nil {
+			return err
+		}
+	}
+
+	r.bet.Active = false
+	r.bet.Paid = true
+	if err := r.tx.Model(&r.bet).Updates(map[string]interface{}{"paid": true, "active": false}).Error; err != nil {
+		return err
+	}
+
+	var openLegIDs []uint
+	if err := r.tx.Model(&models.ParlayEntry{}).Where("bet_id = ? AND resolved = ?", r.bet.ID, false).Pluck("id", &openLegIDs).Error; err != nil {
+		return err
+	}
+
+	if err := updateParlaysOnBetResolution(r.s, r.tx, r.notifications, r.bet, r.result); err != nil {
+		return fmt.Errorf("error updating parlays: %v", err)
+	}
+
+	if len(openLegIDs) > 0 {
+		var legs []models.ParlayEntry
+		if err := r.tx.Preload("Parlay").Preload("Parlay.User").Where("id IN ?", openLegIDs).Find(&legs).Error; err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			r.parlayLegs += fmt.Sprintf("%s - Parlay #%d: %s - %s → parlay %s\n", r.username(leg.Parlay.User.DiscordID), leg.ParlayID, common.GetOptionName(r.bet, leg.SelectedOption), parlayEntryStatus(leg), leg.Parlay.Status)
+		}
+	}
+
+	return nil
+}
+
+// recordCardsPlayed notes the cards that triggered for a user, for previews.
+func (r *settlementRun) recordCardsPlayed(user models.User, cardIDs ...uint) {
+	if len(cardIDs) == 0 {
+		return
+	}
+
+	var names []string
+	for _, cardID := range cardIDs {
+		name := fmt.Sprintf("Card %d", cardID)
+		if card := cardService.GetCardByID(cardID); card != nil {
+			name = card.Name
+		}
+		names = append(names, name)
+	}
+	r.cardsPlayed += fmt.Sprintf("%s - %s\n", r.username(user.DiscordID), strings.Join(names, ", "))
+}
+
+// consumeCard plays a card from inventory inside the settlement transaction and
+// queues the "card played" post for after commit.
+func (r *settlementRun) consumeCard(tx *gorm.DB, user models.User, cardID uint) error {
+	if err := cardService.PlayCardFromInventoryInTransaction(tx, user, cardID); err != nil {
+		return err
+	}
+	if r.dryRun {
+		return nil
+	}
+
+	s := r.s
+	r.notifications.add(func(db *gorm.DB) {
+		card := cardService.GetCardByID(cardID)
+		if card == nil {
+			return
+		}
+		if err := cardService.NotifyCardPlayed(s, db, user, card); err != nil {
+			log.Printf("Error notifying card played for user %d: %v", user.ID, err)
+		}
+	})
+	return nil
+}
+
+func (r *settlementRun) username(discordID string) string {
+	return common.GetUsernameWithDB(r.tx, r.s, r.bet.GuildID, discordID)
+}
+
+// entryLabel describes the pick on an entry, e.g. "Bet: Alabama -7.5".
+func (r *settlementRun) entryLabel(entry models.BetEntry) string {
+	if r.bet.Total != nil {
+		total := *r.bet.Total
+		if entry.Total != nil {
+			total = *entry.Total
+		}
+		return "Bet: " + common.FormatTotalOption(entry.Option, total)
+	}
+
+	optionName := common.GetOptionName(r.bet, entry.Option)
+	if r.bet.Spread == nil {
+		return "Bet: " + optionName
+	}
+
+	spread := *r.bet.Spread
+	if entry.Spread != nil {
+		spread = *entry.Spread
+	}
+	if entry.Option == 2 {
+		spread = spread * -1
+	}
+	return fmt.Sprintf("Bet: %s %s", common.GetSchoolName(optionName), common.FormatOdds(spread))
+}
+
+// settlePush refunds the stake. No built-in payout modifier runs on a push, so
+// cards that trigger on a win or a loss stay in inventory for the next bet; an
+// Uno Reverse played on this bet is used up.
+func (r *settlementRun) settlePush(user models.User, entry models.BetEntry) error {
+	unoApplied, _, err := cardService.ApplyUnoReverseIfApplicable(r.tx, user, r.bet.ID, false)
+	if err != nil {
+		return fmt.Errorf("error checking Uno Reverse: %v", err)
+	}
+
+	state := &cardService.PayoutState{Amount: float64(entry.Amount)}
+	if unoApplied {
+		state.Annotations = append(state.Annotations, "Uno Reverse: no effect on a push")
+		state.Triggered = append(state.Triggered, cards.UnoReverseCardID)
+	}
+	if err := cardService.ApplyPayoutModifiers(r.payoutContext(user, entry, cardService.PhasePush), state); err != nil {
+		return err
+	}
+	r.recordCardsPlayed(user, state.Triggered...)
+
+	if err := ledgerService.Apply(r.tx, &user, state.Amount+state.Refund+state.PoolRefund, ledgerService.Bet(ledgerService.ReasonBetRefund, r.bet.ID)); err != nil {
+		return err
+	}
+
+	r.pushes += fmt.Sprintf("%s - %s - **Refunded $%.0f**%s\n", r.username(user.DiscordID), r.entryLabel(entry), state.Amount, state.Annotation())
+	return nil
+}
+
+func (r *settlementRun) settleWin(user models.User, entry models.BetEntry) error {
+	unoApplied, isWinAfterUno, err := cardService.ApplyUnoReverseIfApplicable(r.tx, user, r.bet.ID, true)
+	if err != nil {
+		return fmt.Errorf("error checking Uno Reverse: %v", err)
+	}
+
+	if unoApplied && !isWinAfterUno {
+		r.recordCardsPlayed(user, cards.UnoReverseCardID)
+		user.TotalBetsLost++
+		user.TotalPointsLost += float64(entry.Amount)
+		if err := r.tx.Omit("points").Save(&user).Error; err != nil {
+			return err
+		}
+		r.lostPoolAmount += float64(entry.Amount)
+		r.losers += fmt.Sprintf("%s - %s - **Lost $%d** (Uno Reverse!)\n", r.username(user.DiscordID), r.entryLabel(entry), entry.Amount)
+		return nil
+	}
+
+	_, _, antiAntiBetLosers, antiAntiBetApplied, err := cardService.ApplyAntiAntiBetIfApplicable(r.tx, user, true)
+	if err != nil {
+		return fmt.Errorf("error checking Anti-Anti-Bet: %v", err)
+	}
+	if antiAntiBetApplied {
+		for _, loser := range antiAntiBetLosers {
+			r.losers += fmt.Sprintf("%s - **Lost $%.1f** (Anti-Anti-Bet!)\n", r.username(loser.DiscordID), loser.Payout)
+		}
+	}
+
+	return r.payWinner(user, entry, common.CalculateEntryPayout(entry, entry.Option, r.bet), "")
+}
+
+func (r *settlementRun) settleLoss(user models.User, entry models.BetEntry) error {
+	unoApplied, isWinAfterUno, err := cardService.ApplyUnoReverseIfApplicable(r.tx, user, r.bet.ID, false)
+	if err != nil {
+		return fmt.Errorf("error checking Uno Reverse: %v", err)
+	}
+
+	if unoApplied && isWinAfterUno {
+		// On bets with more than two options a reversed loss is paid at the
+		// winning option's price rather than the long odds of the original pick.
+		payoutOption := entry.Option
+		if len(common.GetBetOptions(r.bet)) > 2 {
+			payoutOption = r.result.WinningOption
+		}
+		r.recordCardsPlayed(user, cards.UnoReverseCardID)
+		return r.payWinner(user, entry, common.CalculateEntryPayout(entry, payoutOption, r.bet), " (Uno Reverse!)")
+	}
+
+	antiAntiBetPayout, antiAntiBetWinners, _, antiAntiBetApplied, err := cardService.ApplyAntiAntiBetIfApplicable(r.tx, user, false)
+	if err != nil {
+		return fmt.Errorf("error checking Anti-Anti-Bet: %v", err)
+	}
+	if antiAntiBetApplied && antiAntiBetPayout > 0 {
+		r.totalPayout += antiAntiBetPayout
+		for _, winner := range antiAntiBetWinners {
+			r.winners += fmt.Sprintf("%s - **Won $%.1f** (Anti-Anti-Bet!)\n", r.username(winner.DiscordID), winner.Payout)
+		}
+	}
+
+	state := &cardService.PayoutState{Amount: float64(entry.Amount)}
+	if err := cardService.ApplyPayoutModifiers(r.payoutContext(user, entry, cardService.PhaseLoss), state); err != nil {
+		return err
+	}
+	r.recordCardsPlayed(user, state.Triggered...)
+
+	if err := ledgerService.Apply(r.tx, &user, state.Refund+state.PoolRefund, ledgerService.Bet(ledgerService.ReasonBetRefund, r.bet.ID)); err != nil {
+		return err
+	}
+	if !state.Stopped {
+		user.TotalBetsLost++
+		user.TotalPointsLost += state.Amount
+		r.lostPoolAmount += state.Amount - state.PoolRefund
+	}
+	if err := r.tx.Omit("points").Save(&user).Error; err != nil {
+		return err
+	}
+
+	r.losers += fmt.Sprintf("%s - %s - **Lost $%.0f**%s\n", r.username(user.DiscordID), r.entryLabel(entry), state.Amount, state.Annotation())
+	return nil
+}
+
+// payWinner runs the payout modifiers for a winning entry and credits the user.
+// tag is appended to the amount, e.g. " (Uno Reverse!)".
+func (r *settlementRun) payWinner(user models.User, entry models.BetEntry, payout float64, tag string) error {
+	r.basePayouts += fmt.Sprintf("%s - %s - Base $%.1f\n", r.username(user.DiscordID), r.entryLabel(entry), payout)
+
+	state := &cardService.PayoutState{Amount: payout}
+	if err := cardService.ApplyPayoutModifiers(r.payoutContext(user, entry, cardService.PhaseWin), state); err != nil {
+		return err
+	}
+	r.recordCardsPlayed(user, state.Triggered...)
+
+	if err := ledgerService.Apply(r.tx, &user, state.Amount+state.Refund+state.PoolRefund, ledgerService.Bet(ledgerService.ReasonBetPayout, r.bet.ID)); err != nil {
+		return err
+	}
+	user.TotalBetsWon++
+	user.TotalPointsWon += state.Amount
+	if err := r.tx.Omit("points").Save(&user).Error; err != nil {
+		return err
+	}
+
+	credited := state.Amount + state.Refund + state.PoolRefund
+	r.totalPayout += credited
+	r.totalWinningPayouts += credited
+	r.winnerDiscordIDs[user.DiscordID] += credited
+
+	if state.Amount > 0 {
+		r.winners += fmt.Sprintf("%s - %s - **Won $%.1f**%s%s\n", r.username(user.DiscordID), r.entryLabel(entry), state.Amount, tag, state.Annotation())
+	}
+	return nil
+}
+
+func (r *settlementRun) payoutContext(user models.User, entry models.BetEntry, phase cardService.PayoutPhase) *cardService.PayoutContext {
+	return &cardService.PayoutContext{
+		DB:        r.tx,
+		Consumer:  r.consumeCard,
+		User:      user,
+		Bet:       r.bet,
+		Entry:     entry,
+		Phase:     phase,
+		ScoreDiff: r.result.ScoreDiff,
+		Scored:    r.result.Scored,
+		DryRun:    r.dryRun,
+	}
+}
+
+// applyWinnerPoolCards runs the cards that act on the bet's winners as a group.
+func (r *settlementRun) applyWinnerPoolCards() error {
+	vampirePayout, vampireWinners, vampireApplied, err := cardService.ApplyVampireIfApplicable(r.tx, r.bet.GuildID, r.totalWinningPayouts, r.winnerDiscordIDs)
+	if err != nil {
+		return fmt.Errorf("error checking Vampire: %v", err)
+	}
+	if vampireApplied && vampirePayout > 0 {
+		r.totalPayout += vampirePayout
+		for _, winner := range vampireWinners {
+			r.winners += fmt.Sprintf("%s - **Won $%.1f** (Vampire)\n", r.username(winner.DiscordID), winner.Payout)
+		}
+	}
+
+	loversPayout, loversWinners, loversApplied, err := cardService.ApplyTheLoversIfApplicable(r.tx, r.bet.GuildID, r.winnerDiscordIDs)
+	if err != nil {
+		return fmt.Errorf("error checking The Lovers: %v", err)
+	}
+	if loversApplied && loversPayout > 0 {
+		r.totalPayout += loversPayout
+		for _, winner := range loversWinners {
+			r.winners += fmt.Sprintf("%s - **Won $%.1f** (The Lovers)\n", r.username(winner.DiscordID), winner.Payout)
+		}
+	}
+
+	devilDiverted, devilDivertedList, devilApplied, err := cardService.ApplyTheDevilIfApplicable(r.tx, r.bet.GuildID, r.winnerDiscordIDs)
+	if err != nil {
+		return fmt.Errorf("error checking The Devil: %v", err)
+	}
+	if devilApplied && devilDiverted > 0 {
+		r.totalPayout -= devilDiverted
+		for _, diverted := range devilDivertedList {
+			r.markDiverted(diverted.DiscordID, diverted.Diverted, "The Devil")
+		}
+	}
+
+	emperorDiverted, emperorDivertedList, emperorApplied, err := cardService.ApplyTheEmperorIfApplicable(r.tx, r.bet.GuildID, r.winnerDiscordIDs)
+	if err != nil {
+		return fmt.Errorf("error checking The Emperor: %v", err)
+	}
+	if emperorApplied && emperorDiverted > 0 {
+		r.totalPayout -= emperorDiverted
+		for _, diverted := range emperorDivertedList {
+			r.markDiverted(diverted.DiscordID, diverted.Diverted, "The Emperor")
+		}
+	}
+
+	return nil
+}
+
+func (r *settlementRun) markDiverted(discordID string, diverted float64, cardName string) {
+	netAmount := r.winnerDiscordIDs[discordID]
+	grossAmount := netAmount + diverted
+	oldStr := fmt.Sprintf("**Won $%.1f**", grossAmount)
+	newStr := fmt.Sprintf("**Won $%.1f** ($%.1f diverted to pool via %s)", netAmount, diverted, cardName)
+	r.winners = replaceWonAmountInUserLine(r.winners, r.username(discordID), oldStr, newStr)
+}
+
+// replaceWonAmountInUserLine finds the line for the given username that contains oldStr and replaces oldStr with newStr.
+// Winner lines may be bet-based (e.g. "username - Bet: ... - **Won $X**") or card-based (e.g. "username - **Won $X** (Vampire)").
+func replaceWonAmountInUserLine(winnersList, username, oldStr, newStr string) string {
+	prefix := username + " - "
+	lines := strings.Split(winnersList, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, prefix) && strings.Contains(line, oldStr) {
+			lines[i] = strings.Replace(line, oldStr, newStr, 1)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// gradeBetEntry grades an entry. Bets resolved by hand win on the chosen option,
+// or push when the admin pushes them; scored bets use the line captured on the
+// entry.
+func gradeBetEntry(bet models.Bet, entry models.BetEntry, result BetResult) entryGrade {
+	if !result.Scored {
+		if result.WinningOption == PushOption {
+			return gradePush
+		}
+		if entry.Option == result.WinningOption {
+			return gradeWin
+		}
+		return gradeLoss
+	}
+
+	if bet.Total != nil {
+		total := *bet.Total
+		if entry.Total != nil {
+			total = *entry.Total
+		}
+		if common.CalculateTotalEntryPush(result.TotalScore, total) {
+			return gradePush
+		}
+		if common.CalculateTotalEntryWin(entry.Option, result.TotalScore, total) {
+			return gradeWin
+		}
+		return gradeLoss
+	}
+
+	if bet.Spread == nil {
+		if result.ScoreDiff == 0 {
+			return gradePush
+		}
+		if (entry.Option == 1) == (result.ScoreDiff > 0) {
+			return gradeWin
+		}
+		return gradeLoss
+	}
+
+	spread := *bet.Spread
+	if entry.Spread != nil {
+		spread = *entry.Spread
+	}
+	if common.CalculateBetEntryPush(result.ScoreDiff, spread) {
+		return gradePush
+	}
+	if common.CalculateBetEntryWin(entry.Option, result.ScoreDiff, spread) {
+		return gradeWin
+	}
+	return gradeLoss
+}
+
+// winningOptionForScore returns the option that wins a game bet for the final
+// score, or 0 when a moneyline game ends tied.
+func winningOptionForScore(bet models.Bet, scoreDiff int, totalScore int) int {
+	if bet.Total != nil {
+		// Option 1 is over, Option 2 is under
+		if common.CalculateTotalEntryWin(1, totalScore, *bet.Total) {
+			return 1
+		}
+		return 2
+	}
+	if bet.Spread == nil {
+		// Option 1 is home team, Option 2 is away team
+		if scoreDiff > 0 {
+			return 1
+		} else if scoreDiff < 0 {
+			return 2
+		}
+		return 0
+	}
+	// Option 1 is home team + spread, Option 2 is away team - spread
+	if common.CalculateBetEntryWin(1, scoreDiff, *bet.Spread) {
+		return 1
+	}
+	return 2
+}
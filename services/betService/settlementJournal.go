@@ -0,0 +1,230 @@
+package betService
+
+import (
+	"time"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+
+	"gorm.io/gorm"
+)
+
+// settlementSnapshot is the state a settlement can touch, read before it runs.
+// Card effects move points between users and the pool on their own, so rather
+// than journal each write the settlement compares this snapshot with the state
+// it leaves behind.
+type settlementSnapshot struct {
+	betActive  bool
+	users      map[uint]models.User
+	inventory  []models.UserInventory
+	pool       float64
+	legs       []models.ParlayEntry
+	parlays    map[uint]models.Parlay
+	betEntries []models.BetEntry
+}
+
+// takeSettlementSnapshot locks the guild's users so no other point changes land
+// between the snapshot and the journal.
+func takeSettlementSnapshot(tx *gorm.DB, bet models.Bet) (*settlementSnapshot, error) {
+	snapshot := &settlementSnapshot{
+		betActive: bet.Active,
+		users:     make(map[uint]models.User),
+		parlays:   make(map[uint]models.Parlay),
+	}
+
+	var users []models.User
+	if err := forUpdate(tx).Where("guild_id = ?", bet.GuildID).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		snapshot.users[user.ID] = user
+	}
+
+	if err := tx.Where("guild_id = ?", bet.GuildID).Order("id").Find(&snapshot.inventory).Error; err != nil {
+		return nil, err
+	}
+
+	pool, err := guildPool(tx, bet.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.pool = pool
+
+	if err := tx.Preload("Parlay").Where("bet_id = ? AND resolved = ?", bet.ID, false).Find(&snapshot.legs).Error; err != nil {
+		return nil, err
+	}
+	for _, leg := range snapshot.legs {
+		snapshot.parlays[leg.ParlayID] = leg.Parlay
+	}
+
+	if err := tx.Where("bet_id = ? AND deleted_at IS NULL", bet.ID).Find(&snapshot.betEntries).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// journal compares the snapshot with the current state and returns the journal
+// for the settlement. It is not saved.
+func (snapshot *settlementSnapshot) journal(tx *gorm.DB, bet models.Bet, result BetResult) (*models.SettlementJournal, error) {
+	journal := &models.SettlementJournal{
+		BetID:         bet.ID,
+		GuildID:       bet.GuildID,
+		WinningOption: result.WinningOption,
+		ScoreDiff:     result.ScoreDiff,
+		TotalScore:    result.TotalScore,
+		Scored:        result.Scored,
+		BetWasActive:  snapshot.betActive,
+	}
+
+	var users []models.User
+	if err := tx.Where("guild_id = ?", bet.GuildID).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		before, ok := snapshot.users[user.ID]
+		if !ok {
+			continue
+		}
+		if line, changed := userJournalEntry(before, user); changed {
+			journal.Entries = append(journal.Entries, line)
+		}
+	}
+
+	var inventory []models.UserInventory
+	if err := tx.Where("guild_id = ?", bet.GuildID).Order("id").Find(&inventory).Error; err != nil {
+		return nil, err
+	}
+	journal.Entries = append(journal.Entries, inventoryJournalEntries(snapshot.inventory, inventory)...)
+
+	pool, err := guildPool(tx, bet.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	journal.PoolDelta = common.RoundPoints(pool - snapshot.pool)
+
+	for _, parlay := range snapshot.parlays {
+		parlayID := parlay.ID
+		journal.Entries = append(journal.Entries, models.SettlementJournalEntry{
+			Kind:            models.JournalEntryParlay,
+			ParlayID:        &parlayID,
+			ParlayStatus:    parlay.Status,
+			ParlayTotalOdds: parlay.TotalOdds,
+		})
+	}
+	for _, leg := range snapshot.legs {
+		legID := leg.ID
+		journal.Entries = append(journal.Entries, models.SettlementJournalEntry{
+			Kind:          models.JournalEntryParlayEntry,
+			ParlayEntryID: &legID,
+			Resolved:      leg.Resolved,
+			Won:           leg.Won,
+			Push:          leg.Push,
+		})
+	}
+	for _, entry := range snapshot.betEntries {
+		entryID := entry.ID
+		journal.Entries = append(journal.Entries, models.SettlementJournalEntry{
+			Kind:          models.JournalEntryBetEntry,
+			BetEntryID:    &entryID,
+			AutoCloseWin:  entry.AutoCloseWin,
+			AutoClosePush: entry.AutoClosePush,
+		})
+	}
+
+	return journal, nil
+}
+
+// inventoryJournalEntries compares a guild's cards before and after a
+// settlement. Cards that are gone were consumed, and are journaled with their
+// old state so they can be put back as they were; new cards are journaled so
+// they can be taken away; cards changed in place are journaled with their old
+// state.
+func inventoryJournalEntries(before []models.UserInventory, after []models.UserInventory) []models.SettlementJournalEntry {
+	live := make(map[uint]models.UserInventory, len(after))
+	for _, card := range after {
+		live[card.ID] = card
+	}
+
+	var lines []models.SettlementJournalEntry
+	existed := make(map[uint]bool, len(before))
+	for _, card := range before {
+		existed[card.ID] = true
+		now, ok := live[card.ID]
+		switch {
+		case !ok:
+			lines = append(lines, inventoryJournalEntry(models.JournalEntryInventory, card))
+		case inventoryChanged(card, now):
+			lines = append(lines, inventoryJournalEntry(models.JournalEntryInventoryUpdated, card))
+		}
+	}
+	for _, card := range after {
+		if !existed[card.ID] {
+			lines = append(lines, inventoryJournalEntry(models.JournalEntryInventoryCreated, card))
+		}
+	}
+	return lines
+}
+
+func inventoryJournalEntry(kind string, card models.UserInventory) models.SettlementJournalEntry {
+	inventoryID := card.ID
+	return models.SettlementJournalEntry{
+		Kind:                  kind,
+		InventoryID:           &inventoryID,
+		InventoryTargetBetID:  card.TargetBetID,
+		InventoryTargetUserID: card.TargetUserID,
+		InventoryBetAmount:    card.BetAmount,
+		InventoryTimesApplied: card.TimesApplied,
+		InventoryExpiresAt:    card.ExpiresAt,
+	}
+}
+
+// inventoryChanged reports whether a card's state differs between two reads.
+func inventoryChanged(before models.UserInventory, after models.UserInventory) bool {
+	return !equalUintPtr(before.TargetBetID, after.TargetBetID) ||
+		!equalStringPtr(before.TargetUserID, after.TargetUserID) ||
+		common.RoundPoints(before.BetAmount) != common.RoundPoints(after.BetAmount) ||
+		before.TimesApplied != after.TimesApplied ||
+		!equalTimePtr(before.ExpiresAt, after.ExpiresAt)
+}
+
+func equalUintPtr(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalStringPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTimePtr(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// userJournalEntry returns the journal line for a user's changes during a
+// settlement, and false if nothing changed.
+func userJournalEntry(before models.User, after models.User) (models.SettlementJournalEntry, bool) {
+	line := models.SettlementJournalEntry{
+		Kind:                 models.JournalEntryUser,
+		PointsDelta:          common.RoundPoints(after.Points - before.Points),
+		TotalBetsWonDelta:    after.TotalBetsWon - before.TotalBetsWon,
+		TotalBetsLostDelta:   after.TotalBetsLost - before.TotalBetsLost,
+		TotalPointsWonDelta:  common.RoundPoints(after.TotalPointsWon - before.TotalPointsWon),
+		TotalPointsLostDelta: common.RoundPoints(after.TotalPointsLost - before.TotalPointsLost),
+	}
+	if line.PointsDelta == 0 && line.TotalBetsWonDelta == 0 && line.TotalBetsLostDelta == 0 &&
+		line.TotalPointsWonDelta == 0 && line.TotalPointsLostDelta == 0 {
+		return line, false
+	}
+	userID := after.ID
+	line.UserID = &userID
+	return line, true
+}
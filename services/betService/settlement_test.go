@@ -0,0 +1,561 @@
+package betService
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/cardService/cards"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestGradeBetEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		bet      models.Bet
+		entry    models.BetEntry
+		result   BetResult
+		expected entryGrade
+	}{
+		{
+			name:     "Manual resolution wins on the chosen option",
+			bet:      models.Bet{},
+			entry:    models.BetEntry{Option: 3},
+			result:   ManualResult(3),
+			expected: gradeWin,
+		},
+		{
+			name:     "Manual resolution loses on any other option",
+			bet:      models.Bet{},
+			entry:    models.BetEntry{Option: 1},
+			result:   ManualResult(3),
+			expected: gradeLoss,
+		},
+		{
+			name:     "Manual push refunds every option",
+			bet:      models.Bet{},
+			entry:    models.BetEntry{Option: 2},
+			result:   ManualResult(PushOption),
+			expected: gradePush,
+		},
+		{
+			name:     "Spread entry graded on its own line",
+			bet:      models.Bet{Spread: floatPtr(-7.0)},
+			entry:    models.BetEntry{Option: 1, Spread: floatPtr(-6.5)},
+			result:   ScoreResult(models.Bet{Spread: floatPtr(-7.0)}, 7, 45),
+			expected: gradeWin,
+		},
+		{
+			name:     "Spread entry lands on the number",
+			bet:      models.Bet{Spread: floatPtr(-7.5)},
+			entry:    models.BetEntry{Option: 2, Spread: floatPtr(-7.0)},
+			result:   ScoreResult(models.Bet{Spread: floatPtr(-7.5)}, 7, 45),
+			expected: gradePush,
+		},
+		{
+			name:     "Moneyline tie pushes",
+			bet:      models.Bet{},
+			entry:    models.BetEntry{Option: 2},
+			result:   ScoreResult(models.Bet{}, 0, 48),
+			expected: gradePush,
+		},
+		{
+			name:     "Moneyline away win",
+			bet:      models.Bet{},
+			entry:    models.BetEntry{Option: 2},
+			result:   ScoreResult(models.Bet{}, -3, 41),
+			expected: gradeWin,
+		},
+		{
+			name:     "Under hits",
+			bet:      models.Bet{Total: floatPtr(48.5)},
+			entry:    models.BetEntry{Option: 2},
+			result:   ScoreResult(models.Bet{Total: floatPtr(48.5)}, 3, 45),
+			expected: gradeWin,
+		},
+		{
+			name:     "Total lands on the entry's number",
+			bet:      models.Bet{Total: floatPtr(48.5)},
+			entry:    models.BetEntry{Option: 1, Total: floatPtr(48.0)},
+			result:   ScoreResult(models.Bet{Total: floatPtr(48.5)}, 3, 48),
+			expected: gradePush,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.expected, gradeBetEntry(tt.bet, tt.entry, tt.result), "grade")
+		})
+	}
+}
+
+func TestScoreResultWinningOption(t *testing.T) {
+	assertEqual(t, 1, ScoreResult(models.Bet{Spread: floatPtr(-3.5)}, 7, 41).WinningOption, "home covers")
+	assertEqual(t, 2, ScoreResult(models.Bet{Spread: floatPtr(-3.5)}, 3, 41).WinningOption, "away covers")
+	assertEqual(t, 0, ScoreResult(models.Bet{}, 0, 42).WinningOption, "moneyline tie")
+	assertEqual(t, 1, ScoreResult(models.Bet{Total: floatPtr(44.5)}, 0, 50).WinningOption, "over")
+	assertEqual(t, false, ManualResult(2).Scored, "manual results are not scored")
+}
+
+func TestInventoryJournalEntries(t *testing.T) {
+	expires := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	extended := expires.Add(24 * time.Hour)
+	card := func(id uint, timesApplied int, expiresAt *time.Time) models.UserInventory {
+		inventory := models.UserInventory{CardID: 5, TimesApplied: timesApplied, ExpiresAt: expiresAt}
+		inventory.ID = id
+		return inventory
+	}
+
+	before := []models.UserInventory{card(1, 0, nil), card(2, 1, &expires), card(3, 0, &expires)}
+	after := []models.UserInventory{card(2, 2, &expires), card(3, 0, &extended), card(4, 0, nil), card(5, 0, nil)}
+
+	lines := inventoryJournalEntries(before, after)
+	expected := []struct {
+		kind string
+		id   uint
+	}{
+		{models.JournalEntryInventory, 1},
+		{models.JournalEntryInventoryUpdated, 2},
+		{models.JournalEntryInventoryUpdated, 3},
+		{models.JournalEntryInventoryCreated, 4},
+		{models.JournalEntryInventoryCreated, 5},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d journal lines, got %d", len(expected), len(lines))
+	}
+	for idx, want := range expected {
+		assertEqual(t, want.kind, lines[idx].Kind, "kind")
+		assertEqual(t, want.id, *lines[idx].InventoryID, "inventory id")
+	}
+	assertEqual(t, 1, lines[1].InventoryTimesApplied, "times applied before the settlement")
+	assertEqual(t, true, lines[2].InventoryExpiresAt.Equal(expires), "expiry before the settlement")
+
+	assertEqual(t, 0, len(inventoryJournalEntries(before, before)), "untouched inventory")
+}
+
+func TestUserJournalEntry(t *testing.T) {
+	before := models.User{ID: 7, Points: 1000, TotalBetsWon: 3, TotalPointsWon: 250}
+
+	_, changed := userJournalEntry(before, before)
+	assertEqual(t, false, changed, "untouched user")
+
+	after := before
+	after.Points = 1190.9
+	after.TotalBetsWon = 4
+	after.TotalPointsWon = 440.9
+	line, changed := userJournalEntry(before, after)
+	assertEqual(t, true, changed, "winner")
+	assertEqual(t, uint(7), *line.UserID, "user id")
+	assertEqual(t, 1, line.TotalBetsWonDelta, "bets won delta")
+	assertEqual(t, 0, line.TotalBetsLostDelta, "bets lost delta")
+	if line.PointsDelta < 190.89 || line.PointsDelta > 190.91 {
+		t.Errorf("Expected points delta 190.9, got %.2f", line.PointsDelta)
+	}
+}
+
+func TestResettleResult(t *testing.T) {
+	bet := models.Bet{Spread: floatPtr(-3.5)}
+
+	scored := resettleResult(bet, models.SettlementJournal{Scored: true, ScoreDiff: 3, TotalScore: 41}, 1)
+	assertEqual(t, true, scored.Scored, "game bets are graded from the score again")
+	assertEqual(t, 2, scored.WinningOption, "away covers")
+	assertEqual(t, 3, scored.ScoreDiff, "score diff")
+
+	manual := resettleResult(bet, models.SettlementJournal{WinningOption: 1}, 2)
+	assertEqual(t, false, manual.Scored, "manual bets use the admin's pick")
+	assertEqual(t, 2, manual.WinningOption, "winning option")
+}
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm db: %v", err)
+	}
+
+	return gormDB, mock
+}
+
+// discordRequests stands in for Discord. It answers guild lookups, fails
+// everything else, and records every request.
+type discordRequests struct {
+	paths []string
+}
+
+func (d *discordRequests) RoundTrip(req *http.Request) (*http.Response, error) {
+	d.paths = append(d.paths, req.Method+" "+req.URL.Path)
+	if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/guilds/guild1") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"guild1","name":"Test Guild"}`)),
+			Request:    req,
+		}, nil
+	}
+	return nil, errors.New("unexpected Discord request")
+}
+
+func newTestSession(t *testing.T) (*discordgo.Session, *discordRequests) {
+	t.Helper()
+
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	requests := &discordRequests{}
+	s.Client = &http.Client{Transport: requests}
+	return s, requests
+}
+
+// loadTestDeck loads Get Out of Jail Free into the card deck, so card-played
+// notifications get as far as looking up the guild.
+func loadTestDeck(t *testing.T) {
+	t.Helper()
+
+	db, mock := newMockDB(t)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT \\* FROM `cards`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "handler_name", "rarity_id", "active"}).
+			AddRow(cards.GetOutOfJailCardID, "Get Out of Jail Free", "handleGetOutOfJail", 1, true))
+	mock.ExpectQuery("SELECT \\* FROM `card_rarities`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Mythic"))
+	mock.ExpectQuery("SELECT \\* FROM `card_options`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "card_id"}))
+
+	if err := cardService.LoadDeckFromDB(db); err != nil {
+		t.Fatalf("failed to load deck: %v", err)
+	}
+	if cardService.GetCardByID(cards.GetOutOfJailCardID) == nil {
+		t.Fatal("Get Out of Jail Free was not loaded")
+	}
+}
+
+var (
+	betCols       = []string{"id", "guild_id", "channel_id", "description", "option1", "option2", "odds1", "odds2", "active", "paid", "voided"}
+	betOptionCols = []string{"id", "bet_id", "option_number", "name", "odds"}
+	guildCols     = []string{"id", "guild_id", "guild_name", "pool"}
+	userCols      = []string{"id", "discord_id", "guild_id", "points", "total_bets_lost"}
+	entryCols     = []string{"id", "user_id", "bet_id", "option", "amount"}
+	inventoryCols = []string{"id", "user_id", "guild_id", "card_id"}
+)
+
+func TestSettleBetRollsBackOnFailure(t *testing.T) {
+	loadTestDeck(t)
+	db, mock := newMockDB(t)
+	s, requests := newTestSession(t)
+
+	mock.ExpectQuery("SELECT \\* FROM `bets`").
+		WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, false, false))
+	mock.ExpectQuery("SELECT \\* FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows(guildCols).AddRow(1, "guild1", "Test Guild", 500))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `bets` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, false, false))
+	mock.ExpectQuery("SELECT \\* FROM `bet_options`").
+		WillReturnRows(sqlmock.NewRows(betOptionCols).AddRow(1, 1, 1, "Yes", -110).AddRow(2, 1, 2, "No", -110))
+
+	// Snapshot.
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE guild_id = \\? .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 900, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols).AddRow(30, 7, "guild1", cards.GetOutOfJailCardID))
+	mock.ExpectQuery("SELECT `pool` FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows([]string{"pool"}).AddRow(500))
+	mock.ExpectQuery("SELECT \\* FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT \\* FROM `bet_entries`").
+		WillReturnRows(sqlmock.NewRows(entryCols).AddRow(20, 7, 1, 2, 100))
+
+	// The losing entry plays Get Out of Jail Free for a full refund.
+	mock.ExpectQuery("SELECT \\* FROM `bet_entries`").
+		WillReturnRows(sqlmock.NewRows(entryCols).AddRow(20, 7, 1, 2, 100))
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE id = \\? .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 900, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols).AddRow(30, 7, "guild1", cards.GetOutOfJailCardID))
+	mock.ExpectExec("UPDATE `user_inventories` SET `deleted_at`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE \\(discord_id = \\?").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 900, 0))
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(7, 900))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\?").
+		WithArgs(1000.0, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `users`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE \\(discord_id = \\?").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 1000, 0))
+	mock.ExpectExec("UPDATE `bets`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `bet_options`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `id` FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT \\* FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Journal.
+	mock.ExpectQuery("SELECT \\* FROM `users`").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 1000, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	mock.ExpectQuery("SELECT `pool` FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows([]string{"pool"}).AddRow(500))
+	mock.ExpectExec("INSERT INTO `settlement_journals`").
+		WillReturnError(errors.New("connection lost"))
+	mock.ExpectRollback()
+
+	// Posting the queued Get Out of Jail Free notification would start by
+	// looking the guild up again. This lookup must be left unmet.
+	mock.ExpectQuery("SELECT \\* FROM `guilds` WHERE guild_id = \\?").
+		WillReturnRows(sqlmock.NewRows(guildCols).AddRow(1, "guild1", "Test Guild", 500))
+
+	settlement, err := SettleBet(s, db, 1, ManualResult(1))
+	if err == nil {
+		t.Fatal("expected the settlement to fail")
+	}
+	if settlement != nil {
+		t.Errorf("expected no settlement, got %+v", settlement)
+	}
+	if len(requests.paths) != 1 {
+		t.Errorf("expected only the guild lookup before settling, got %v", requests.paths)
+	}
+	err = mock.ExpectationsWereMet()
+	if err == nil || !strings.Contains(err.Error(), "`guilds` WHERE guild_id") {
+		t.Errorf("expected every query up to the rollback and no notification, got %v", err)
+	}
+}
+
+func TestSettleBetAlreadySettled(t *testing.T) {
+	t.Run("Paid before settling", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		s, _ := newTestSession(t)
+
+		mock.ExpectQuery("SELECT \\* FROM `bets`").
+			WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, true, false))
+
+		settlement, err := SettleBet(s, db, 1, ManualResult(1))
+		if !errors.Is(err, ErrBetAlreadySettled) {
+			t.Fatalf("expected ErrBetAlreadySettled, got %v", err)
+		}
+		if settlement != nil {
+			t.Errorf("expected no settlement, got %+v", settlement)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("Paid while waiting for the lock", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		s, _ := newTestSession(t)
+
+		mock.ExpectQuery("SELECT \\* FROM `bets`").
+			WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, false, false))
+		mock.ExpectQuery("SELECT \\* FROM `guilds`").
+			WillReturnRows(sqlmock.NewRows(guildCols).AddRow(1, "guild1", "Test Guild", 500))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT \\* FROM `bets` .* FOR UPDATE").
+			WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, true, false))
+		mock.ExpectQuery("SELECT \\* FROM `bet_options`").
+			WillReturnRows(sqlmock.NewRows(betOptionCols).AddRow(1, 1, 1, "Yes", -110).AddRow(2, 1, 2, "No", -110))
+		mock.ExpectRollback()
+
+		settlement, err := SettleBet(s, db, 1, ManualResult(1))
+		if !errors.Is(err, ErrBetAlreadySettled) {
+			t.Fatalf("expected ErrBetAlreadySettled, got %v", err)
+		}
+		if settlement != nil {
+			t.Errorf("expected no settlement, got %+v", settlement)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+}
+
+// expectWinLossSettlement expects bet 1 to settle for option 1, with user 7
+// winning on a 110 stake and user 8 losing a 50 stake, and nobody holding any
+// cards. A preview takes the same locks and makes the same writes as the
+// settlement, then rolls back where the settlement journals and commits.
+func expectWinLossSettlement(mock sqlmock.Sqlmock, preview bool) {
+	noCard := func(count int) {
+		for i := 0; i < count; i++ {
+			mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		}
+	}
+	username := func(discordID, name string) {
+		mock.ExpectQuery("SELECT \\* FROM `users` WHERE \\(discord_id = \\?").
+			WithArgs(discordID, "guild1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "discord_id", "guild_id", "username"}).AddRow(1, discordID, "guild1", name))
+	}
+
+	mock.ExpectQuery("SELECT \\* FROM `bets`").
+		WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, false, false))
+	mock.ExpectQuery("SELECT \\* FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows(guildCols).AddRow(1, "guild1", "Test Guild", 500))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `bets` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(betCols).AddRow(1, "guild1", "chan1", "Who wins?", "Yes", "No", -110, -110, false, false, false))
+	mock.ExpectQuery("SELECT \\* FROM `bet_options`").
+		WillReturnRows(sqlmock.NewRows(betOptionCols).AddRow(1, 1, 1, "Yes", -110).AddRow(2, 1, 2, "No", -110))
+
+	// Snapshot.
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE guild_id = \\? .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 900, 0).AddRow(8, "user8", "guild1", 400, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	mock.ExpectQuery("SELECT `pool` FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows([]string{"pool"}).AddRow(500))
+	mock.ExpectQuery("SELECT \\* FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT \\* FROM `bet_entries`").
+		WillReturnRows(sqlmock.NewRows(entryCols).AddRow(20, 7, 1, 1, 110).AddRow(21, 8, 1, 2, 50))
+
+	mock.ExpectQuery("SELECT \\* FROM `bet_entries`").
+		WillReturnRows(sqlmock.NewRows(entryCols).AddRow(20, 7, 1, 1, 110).AddRow(21, 8, 1, 2, 50))
+
+	// User 7 wins 100 on top of the stake.
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE id = \\? .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 900, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	username("user7", "Alice")
+	noCard(2)
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WillReturnError(gorm.ErrRecordNotFound)
+	}
+	noCard(1)
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(7, 900))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\?").
+		WithArgs(1110.0, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `users`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	username("user7", "Alice")
+
+	// User 8 loses the stake to the pool.
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE id = \\? .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(8, "user8", "guild1", 400, 0))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	noCard(3)
+	mock.ExpectExec("UPDATE `users`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	username("user8", "Bob")
+
+	// Vampire, The Lovers, The Devil and The Emperor find nothing to do.
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WillReturnRows(sqlmock.NewRows(inventoryCols))
+	}
+	mock.ExpectQuery("SELECT \\* FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows(guildCols).AddRow(1, "guild1", "Test Guild", 500))
+
+	mock.ExpectExec("UPDATE `guilds` SET `pool`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `bets`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `bet_options`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `id` FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT \\* FROM `parlay_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Journal.
+	mock.ExpectQuery("SELECT \\* FROM `users`").
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow(7, "user7", "guild1", 1110, 0).AddRow(8, "user8", "guild1", 400, 1))
+	mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+		WillReturnRows(sqlmock.NewRows(inventoryCols))
+	mock.ExpectQuery("SELECT `pool` FROM `guilds`").
+		WillReturnRows(sqlmock.NewRows([]string{"pool"}).AddRow(550))
+	if preview {
+		mock.ExpectRollback()
+		return
+	}
+	mock.ExpectExec("INSERT INTO `settlement_journals`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `settlement_journal_entries`").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+}
+
+func TestPreviewMatchesSettlement(t *testing.T) {
+	loadTestDeck(t)
+
+	settle := func(preview bool) *Settlement {
+		t.Helper()
+		db, mock := newMockDB(t)
+		s, _ := newTestSession(t)
+		expectWinLossSettlement(mock, preview)
+
+		var settlement *Settlement
+		var err error
+		if preview {
+			settlement, err = PreviewSettlement(s, db, 1, ManualResult(1))
+		} else {
+			settlement, err = SettleBet(s, db, 1, ManualResult(1))
+		}
+		if err != nil {
+			t.Fatalf("settling (preview %v): %v", preview, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations (preview %v): %v", preview, err)
+		}
+		return settlement
+	}
+
+	preview := settle(true)
+	settlement := settle(false)
+
+	assertEqual(t, settlement.Entries, preview.Entries, "entries")
+	assertEqual(t, settlement.TotalPayout, preview.TotalPayout, "total payout")
+	assertEqual(t, settlement.Winners, preview.Winners, "winners")
+	assertEqual(t, settlement.Losers, preview.Losers, "losers")
+	assertEqual(t, settlement.Pushes, preview.Pushes, "pushes")
+	assertEqual(t, settlement.BasePayouts, preview.BasePayouts, "base payouts")
+	assertEqual(t, settlement.CardsPlayed, preview.CardsPlayed, "cards played")
+	assertEqual(t, settlement.PoolDelta, preview.PoolDelta, "pool delta")
+	assertEqual(t, "Alice - Bet: Yes - **Won $210.0**", settlement.Winners, "winners")
+	assertEqual(t, "Bob - Bet: No - **Lost $50**", settlement.Losers, "losers")
+	assertEqual(t, 210.0, settlement.TotalPayout, "user 7 is paid stake and winnings")
+	assertEqual(t, 50.0, settlement.PoolDelta, "user 8's stake goes to the pool")
+}
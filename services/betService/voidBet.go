@@ -0,0 +1,149 @@
+package betService
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/messageService"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VoidBet cancels an unresolved bet. Every entry is refunded, parlay legs on the
+// bet are dropped, cards spent on the bet are handed back and the bet's posts are
+// marked as voided. All database changes happen in one transaction, and Discord is
+// only updated after it commits. The returned embed summarises the refunds for the
+// caller to post.
+func VoidBet(s *discordgo.Session, db *gorm.DB, bet models.Bet, reason string) (*discordgo.MessageEmbed, error) {
+	refundList := ""
+	cardList := ""
+	var secondaryMsgs []models.BetMessage
+	var notifications notificationQueue
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Options").First(&bet, bet.ID).Error; err != nil {
+			return err
+		}
+		if bet.Paid || bet.Voided {
+			return ErrBetAlreadySettled
+		}
+
+		var entries []models.BetEntry
+		if err := tx.Where("bet_id = ? AND deleted_at IS NULL", bet.ID).Find(&entries).Error; err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			var user models.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", entry.UserID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+
+			if err := ledgerService.Apply(tx, &user, float64(entry.Amount), ledgerService.Bet(ledgerService.ReasonBetRefund, bet.ID)); err != nil {
+				return err
+			}
+
+			username := common.GetUsernameWithDB(tx, s, user.GuildID, user.DiscordID)
+			optionName := common.GetOptionName(bet, entry.Option)
+			if bet.Total != nil && entry.Total != nil {
+				optionName = common.FormatTotalOption(entry.Option, *entry.Total)
+			}
+			refundList += fmt.Sprintf("%s - Bet: %s - **Refunded $%d**\n", username, optionName, entry.Amount)
+		}
+
+		bet.Active = false
+		bet.Paid = true
+		bet.Voided = true
+		if err := tx.Save(&bet).Error; err != nil {
+			return err
+		}
+
+		if err := voidParlayLegs(s, tx, &notifications, bet.ID); err != nil {
+			return fmt.Errorf("error voiding parlay legs: %v", err)
+		}
+
+		restored, err := cardService.RestoreCardsForVoidedBet(tx, bet.ID)
+		if err != nil {
+			return fmt.Errorf("error restoring cards: %v", err)
+		}
+		for _, inventory := range restored {
+			var user models.User
+			tx.First(&user, "id = ?", inventory.UserID)
+			if user.ID == 0 {
+				continue
+			}
+
+			cardName := "Card"
+			if card := cardService.GetCardByID(inventory.CardID); card != nil {
+				cardName = card.Name
+			}
+			username := common.GetUsernameWithDB(tx, s, user.GuildID, user.DiscordID)
+			cardList += fmt.Sprintf("%s - **%s** returned to inventory\n", username, cardName)
+		}
+
+		if err := tx.Where("bet_id = ?", bet.ID).Find(&secondaryMsgs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BetMessage{}).Where("bet_id = ?", bet.ID).Update("active", false).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications.flush(db)
+
+	if reason == "" {
+		reason = "This bet was voided and all stakes have been refunded."
+	}
+
+	closedEmbed := &discordgo.MessageEmbed{
+		Title:       "🚫 Bet has been VOIDED",
+		Description: fmt.Sprintf("%s\n\n%s", bet.Description, reason),
+		Color:       0x95A5A6,
+	}
+
+	if bet.MessageID != nil {
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *bet.MessageID,
+			Channel:    bet.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{closedEmbed},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			log.Printf("Error editing message for voided bet %d: %v\n", bet.ID, err)
+		}
+	}
+
+	for _, msg := range secondaryMsgs {
+		if msg.MessageID == nil {
+			continue
+		}
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *msg.MessageID,
+			Channel:    msg.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{closedEmbed},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			continue
+		}
+	}
+
+	return messageService.BuildBetVoidEmbed(
+		bet.Description,
+		reason,
+		strings.TrimSpace(refundList),
+		strings.TrimSpace(cardList),
+	), nil
+}
@@ -5,6 +5,7 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"gorm.io/gorm"
@@ -99,8 +100,7 @@ func ApplyAntiAntiBetIfApplicable(db *gorm.DB, bettorUser models.User, isWin boo
 				continue
 			}
 
-			cardHolder.Points += payout
-			if err := db.Model(&cardHolder).UpdateColumn("points", gorm.Expr("points + ?", payout)).Error; err != nil {
+			if err := ledgerService.Apply(db, &cardHolder, payout, ledgerService.Card(cards.AntiAntiBetCardID)); err != nil {
 				return totalPayout, winners, losers, applied, err
 			}
 
@@ -156,13 +156,13 @@ func ApplyVampireIfApplicable(db *gorm.DB, guildID string, totalWinningPayouts f
 			continue
 		}
 
-		vampirePayout := totalOtherWinnings * 0.05
+		vampirePayout := common.RoundPoints(totalOtherWinnings * 0.05)
 
 		if vampirePayout > 500.0 {
 			vampirePayout = 500.0
 		}
 
-		if err := db.Model(&vampireHolder).UpdateColumn("points", gorm.Expr("points + ?", vampirePayout)).Error; err != nil {
+		if err := ledgerService.Apply(db, &vampireHolder, vampirePayout, ledgerService.Card(cards.VampireCardID)); err != nil {
 			return totalVampirePayout, winners, applied, err
 		}
 
@@ -190,13 +190,13 @@ func ApplyTheDevilIfApplicable(db *gorm.DB, guildID string, winnerDiscordIDs map
 		return 0, nil, false, nil
 	}
 
-	devilCardHolders := make(map[string]uint)
+	devilCardHolders := make(map[string]models.User)
 	for _, card := range devilCards {
 		var user models.User
 		if err := db.First(&user, card.UserID).Error; err != nil {
 			continue
 		}
-		devilCardHolders[user.DiscordID] = user.ID
+		devilCardHolders[user.DiscordID] = user
 	}
 
 	if len(devilCardHolders) == 0 {
@@ -212,16 +212,16 @@ func ApplyTheDevilIfApplicable(db *gorm.DB, guildID string, winnerDiscordIDs map
 	}
 
 	for discordID, winnings := range winnerDiscordIDs {
-		userID, hasDevilCard := devilCardHolders[discordID]
+		user, hasDevilCard := devilCardHolders[discordID]
 		if !hasDevilCard || winnings <= 0 {
 			continue
 		}
 
-		divertedAmount := winnings * 0.20
+		divertedAmount := common.RoundPoints(winnings * 0.20)
 		totalDiverted += divertedAmount
 		winnerDiscordIDs[discordID] = winnings - divertedAmount
 
-		if err := db.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("points", gorm.Expr("points - ?", divertedAmount)).Error; err != nil {
+		if err := ledgerService.Apply(db, &user, -divertedAmount, ledgerService.Card(cards.TheDevilCardID)); err != nil {
 			return totalDiverted, diverted, totalDiverted > 0, err
 		}
 
@@ -270,7 +270,7 @@ func ApplyTheEmperorIfApplicable(db *gorm.DB, guildID string, winnerDiscordIDs m
 		if discordID == holderDiscordID || winnings <= 0 {
 			continue
 		}
-		divertedAmount := winnings * 0.10
+		divertedAmount := common.RoundPoints(winnings * 0.10)
 		totalDiverted += divertedAmount
 		winnerDiscordIDs[discordID] = winnings - divertedAmount
 
@@ -278,7 +278,7 @@ func ApplyTheEmperorIfApplicable(db *gorm.DB, guildID string, winnerDiscordIDs m
 		if err := db.Where("discord_id = ? AND guild_id = ?", discordID, guildID).First(&user).Error; err != nil {
 			return totalDiverted, diverted, applied, err
 		}
-		if err := db.Model(&user).UpdateColumn("points", gorm.Expr("points - ?", divertedAmount)).Error; err != nil {
+		if err := ledgerService.Apply(db, &user, -divertedAmount, ledgerService.Card(cards.TheEmperorCardID)); err != nil {
 			return totalDiverted, diverted, applied, err
 		}
 
@@ -339,9 +339,9 @@ func ApplyTheLoversIfApplicable(db *gorm.DB, guildID string, winnerDiscordIDs ma
 			continue
 		}
 
-		loversPayout := targetWinnings * 0.25
+		loversPayout := common.RoundPoints(targetWinnings * 0.25)
 
-		if err := db.Model(&loversHolder).UpdateColumn("points", gorm.Expr("points + ?", loversPayout)).Error; err != nil {
+		if err := ledgerService.Apply(db, &loversHolder, loversPayout, ledgerService.Card(cards.TheLoversCardID)); err != nil {
 			return totalLoversPayout, winners, applied, err
 		}
 
@@ -387,29 +387,23 @@ func ApplyEmotionalHedgeIfApplicable(db *gorm.DB, consumer CardConsumer, user mo
 		return 0, false, nil
 	}
 
-	var guild models.Guild
-	if err := db.Where("guild_id = ?", user.GuildID).First(&guild).Error; err != nil {
+	var subscribedTeams []string
+	err = db.Model(&models.GuildSubscription{}).
+		Where("guild_id = ? AND kind = ?", user.GuildID, models.SubscriptionTeam).
+		Pluck("target", &subscribedTeams).Error
+	if err != nil {
 		return 0, false, err
 	}
-	if guild.SubscribedTeam == nil || *guild.SubscribedTeam == "" {
-		return 0, false, nil
-	}
-	subscribedTeam := *guild.SubscribedTeam
-
-	var userPickedTeamName string
-	if userPick == 1 {
-		userPickedTeamName = bet.Option1
-	} else {
-		userPickedTeamName = bet.Option2
-	}
 
+	userPickedTeamName := common.GetOptionName(bet, userPick)
 	userPickedTeamNameNormalized := common.GetSchoolName(userPickedTeamName)
-	subscribedTeamNormalized := common.GetSchoolName(subscribedTeam)
-
-	isBetOnSubscribedTeam := userPickedTeamNameNormalized == subscribedTeamNormalized
 
-	if !isBetOnSubscribedTeam {
-		isBetOnSubscribedTeam = (userPickedTeamName == subscribedTeam)
+	isBetOnSubscribedTeam := false
+	for _, subscribedTeam := range subscribedTeams {
+		if userPickedTeamNameNormalized == common.GetSchoolName(subscribedTeam) || userPickedTeamName == subscribedTeam {
+			isBetOnSubscribedTeam = true
+			break
+		}
 	}
 
 	if !isBetOnSubscribedTeam {
@@ -434,7 +428,7 @@ func ApplyEmotionalHedgeIfApplicable(db *gorm.DB, consumer CardConsumer, user mo
 		if err := consumer(db, user, cards.EmotionalHedgeCardID); err != nil {
 			return 0, false, err
 		}
-		refund := betAmount * 0.5
+		refund := common.RoundPoints(betAmount * 0.5)
 		return refund, true, nil
 	}
 
@@ -460,7 +454,7 @@ func ApplyBetInsuranceIfApplicable(db *gorm.DB, consumer CardConsumer, user mode
 		}
 
 		if !isWin {
-			refund := betAmount * 0.25
+			refund := common.RoundPoints(betAmount * 0.25)
 			return refund, true, nil
 		} else {
 			return 0, true, nil
@@ -540,7 +534,7 @@ func ApplyRoughingTheKickerIfApplicable(db *gorm.DB, user models.User, currentPa
 	if err != nil {
 		return currentPayout, false, nil
 	}
-	reducedPayout := currentPayout * 0.85
+	reducedPayout := common.RoundPoints(currentPayout * 0.85)
 	if err := db.Delete(&inv).Error; err != nil {
 		return currentPayout, false, err
 	}
@@ -556,7 +550,7 @@ func ApplyHeismanCampaignIfApplicable(db *gorm.DB, user models.User, currentPayo
 	if err != nil {
 		return currentPayout, false, nil
 	}
-	reducedPayout := currentPayout * 0.85
+	reducedPayout := common.RoundPoints(currentPayout * 0.85)
 	if err := db.Delete(&inv).Error; err != nil {
 		return currentPayout, false, err
 	}
@@ -171,10 +171,10 @@ func TestApplyEmotionalHedgeIfApplicable(t *testing.T) {
 			WithArgs(user.ID, user.GuildID, cards.EmotionalHedgeCardID).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		mock.ExpectQuery("SELECT \\* FROM `guilds`").
-			WithArgs(user.GuildID, 1).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "guild_id", "subscribed_team"}).
-				AddRow(1, "guild1", subscribedTeam))
+		mock.ExpectQuery("SELECT `target` FROM `guild_subscriptions`").
+			WithArgs(user.GuildID, models.SubscriptionTeam).
+			WillReturnRows(sqlmock.NewRows([]string{"target"}).
+				AddRow(subscribedTeam))
 
 		userPick := 1
 		betAmount := 100.0
@@ -222,10 +222,10 @@ func TestApplyEmotionalHedgeIfApplicable(t *testing.T) {
 		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		mock.ExpectQuery("SELECT \\* FROM `guilds`").
-			WithArgs(user.GuildID, 1).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "guild_id", "subscribed_team"}).
-				AddRow(1, "guild1", subscribedTeam))
+		mock.ExpectQuery("SELECT `target` FROM `guild_subscriptions`").
+			WithArgs(user.GuildID, models.SubscriptionTeam).
+			WillReturnRows(sqlmock.NewRows([]string{"target"}).
+				AddRow(subscribedTeam))
 
 		userPick := 1
 		betAmount := 100.0
@@ -273,10 +273,10 @@ func TestApplyEmotionalHedgeIfApplicable(t *testing.T) {
 		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		mock.ExpectQuery("SELECT \\* FROM `guilds`").
-			WithArgs(user.GuildID, 1).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "guild_id", "subscribed_team"}).
-				AddRow(1, "guild1", subscribedTeam))
+		mock.ExpectQuery("SELECT `target` FROM `guild_subscriptions`").
+			WithArgs(user.GuildID, models.SubscriptionTeam).
+			WillReturnRows(sqlmock.NewRows([]string{"target"}).
+				AddRow(subscribedTeam))
 
 		userPick := 2
 		betAmount := 100.0
@@ -351,10 +351,10 @@ func TestApplyEmotionalHedgeIfApplicable(t *testing.T) {
 		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		mock.ExpectQuery("SELECT \\* FROM `guilds`").
-			WithArgs(user.GuildID, 1).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "guild_id", "subscribed_team"}).
-				AddRow(1, "guild1", subscribedTeam))
+		mock.ExpectQuery("SELECT `target` FROM `guild_subscriptions`").
+			WithArgs(user.GuildID, models.SubscriptionTeam).
+			WillReturnRows(sqlmock.NewRows([]string{"target"}).
+				AddRow(subscribedTeam))
 
 		userPick := 1
 		betAmount := 100.0
@@ -754,9 +754,14 @@ func TestApplyAntiAntiBetIfApplicable(t *testing.T) {
 				AddRow(cardHolderID, "holder123", "guild1", 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(cardHolderID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(cardHolderID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), cardHolderID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -823,9 +828,14 @@ func TestApplyAntiAntiBetIfApplicable(t *testing.T) {
 				AddRow(cardHolder1ID, "holder123", "guild1", 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(cardHolder1ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(cardHolder1ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), cardHolder1ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -840,9 +850,14 @@ func TestApplyAntiAntiBetIfApplicable(t *testing.T) {
 				AddRow(cardHolder2ID, "holder456", "guild1", 300.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(cardHolder2ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(cardHolder2ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), cardHolder2ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -974,9 +989,14 @@ func TestApplyVampireIfApplicable(t *testing.T) {
 				AddRow(vampireHolderID, vampireHolderDiscordID, guildID, 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(vampireHolderID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(vampireHolderID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), vampireHolderID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		winnerDiscordIDs := make(map[string]float64)
@@ -1039,9 +1059,14 @@ func TestApplyVampireIfApplicable(t *testing.T) {
 				AddRow(vampireHolder1ID, vampireHolder1DiscordID, guildID, 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(vampireHolder1ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(vampireHolder1ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), vampireHolder1ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectQuery("SELECT \\* FROM `users`").
@@ -1050,9 +1075,14 @@ func TestApplyVampireIfApplicable(t *testing.T) {
 				AddRow(vampireHolder2ID, vampireHolder2DiscordID, guildID, 600.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(vampireHolder2ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(vampireHolder2ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), vampireHolder2ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		winnerDiscordIDs := make(map[string]float64)
@@ -1149,12 +1179,6 @@ func TestApplyVampireIfApplicable(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "discord_id", "guild_id", "points"}).
 				AddRow(vampireHolderID, vampireHolderDiscordID, guildID, 500.0))
 
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE `users` SET `points`=").
-			WithArgs(sqlmock.AnyArg(), vampireHolderID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectCommit()
-
 		winnerDiscordIDs := make(map[string]float64)
 		totalVampirePayout, winners, applied, err := ApplyVampireIfApplicable(db, guildID, totalWinningPayouts, winnerDiscordIDs)
 
@@ -1289,9 +1313,14 @@ func TestApplyTheLoversIfApplicable(t *testing.T) {
 				AddRow(loversHolderID, loversHolderDiscordID, guildID, 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(loversHolderID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(loversHolderID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), loversHolderID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		winnerDiscordIDs := make(map[string]float64)
@@ -1358,9 +1387,14 @@ func TestApplyTheLoversIfApplicable(t *testing.T) {
 				AddRow(loversHolder1ID, loversHolder1DiscordID, guildID, 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(loversHolder1ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(loversHolder1ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), loversHolder1ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectQuery("SELECT \\* FROM `users`").
@@ -1369,9 +1403,14 @@ func TestApplyTheLoversIfApplicable(t *testing.T) {
 				AddRow(loversHolder2ID, loversHolder2DiscordID, guildID, 600.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(loversHolder2ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(loversHolder2ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), loversHolder2ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		winnerDiscordIDs := make(map[string]float64)
@@ -1559,9 +1598,14 @@ func TestApplyTheLoversIfApplicable(t *testing.T) {
 				AddRow(loversHolderID, loversHolderDiscordID, guildID, 500.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(loversHolderID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(loversHolderID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), loversHolderID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		winnerDiscordIDs := make(map[string]float64)
@@ -1863,9 +1907,14 @@ func TestApplyTheDevilIfApplicable(t *testing.T) {
 				AddRow(1, guildID, 1000.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(devilHolderID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(devilHolderID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), devilHolderID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -1951,15 +2000,25 @@ func TestApplyTheDevilIfApplicable(t *testing.T) {
 				AddRow(1, guildID, 1000.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(devilHolder1ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(devilHolder2ID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -2283,9 +2342,14 @@ func TestApplyTheEmperorIfApplicable(t *testing.T) {
 				AddRow(winnerUserID, winnerDiscordID, guildID, 600.0))
 
 		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id`,`points` FROM `users` .* FOR UPDATE").
+			WithArgs(winnerUserID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(winnerUserID, 0.0))
 		mock.ExpectExec("UPDATE `users` SET `points`=").
 			WithArgs(sqlmock.AnyArg(), winnerUserID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		mock.ExpectBegin()
@@ -63,7 +63,7 @@ func registerEpicCards(deck *[]models.Card) {
 			ID:                   27,
 			Code:                 "DDN",
 			Name:                 "Double Down",
-			Description:          "The payout of your next winning bet is increased by 2x",
+			Description:          "The payout of your next winning bet is increased by 2x. Pushes don't count.",
 			Handler:              handleDoubleDown,
 			AddToInventory:       true,
 			IsPositive:           true,
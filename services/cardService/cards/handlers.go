@@ -8,6 +8,7 @@ import (
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/historyService"
+	"perfectOddsBot/services/ledgerService"
 	"strings"
 	"time"
 
@@ -64,7 +65,7 @@ func handleGrail(s *discordgo.Session, db *gorm.DB, userID string, guildID strin
 		return nil, err
 	}
 
-	poolWin := guild.Pool * 0.25
+	poolWin := common.RoundPoints(guild.Pool * 0.25)
 
 	return &models.CardResult{
 		Message:     "You discovered the Holy Grail! You won 25% of the pool!",
@@ -79,7 +80,7 @@ func handleJackpot(s *discordgo.Session, db *gorm.DB, userID string, guildID str
 		return nil, err
 	}
 
-	poolWin := guild.Pool * 0.5
+	poolWin := common.RoundPoints(guild.Pool * 0.5)
 
 	return &models.CardResult{
 		Message:     ":rotating_light: You discovered the JACKPOT! You won 50% of the pool! :rotating_light:",
@@ -98,7 +99,7 @@ func handlePettyTheft(s *discordgo.Session, db *gorm.DB, userID string, guildID
 	}, nil
 }
 
-func ExecutePickpocketSteal(db *gorm.DB, userID string, targetUserID string, guildID string, amount float64) (*models.CardResult, error) {
+func ExecutePickpocketSteal(db *gorm.DB, userID string, targetUserID string, guildID string, amount float64, cardID uint) (*models.CardResult, error) {
 	var user models.User
 	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("discord_id = ? AND guild_id = ?", userID, guildID).
@@ -145,12 +146,10 @@ func ExecutePickpocketSteal(db *gorm.DB, userID string, targetUserID string, gui
 			stealAmount = randomUser.Points
 		}
 
-		user.Points += stealAmount
-		randomUser.Points -= stealAmount
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := ledgerService.Apply(db, &randomUser, -stealAmount, ledgerService.Card(cardID)); err != nil {
 			return nil, err
 		}
-		if err := db.Save(&user).Error; err != nil {
+		if err := ledgerService.Apply(db, &user, stealAmount, ledgerService.Card(cardID)); err != nil {
 			return nil, err
 		}
 
@@ -186,13 +185,10 @@ func ExecutePickpocketSteal(db *gorm.DB, userID string, targetUserID string, gui
 		stealAmount = targetUser.Points
 	}
 
-	user.Points += stealAmount
-	targetUser.Points -= stealAmount
-
-	if err := db.Save(&user).Error; err != nil {
+	if err := ledgerService.Apply(db, &user, stealAmount, ledgerService.Card(cardID)); err != nil {
 		return nil, err
 	}
-	if err := db.Save(&targetUser).Error; err != nil {
+	if err := ledgerService.Apply(db, &targetUser, -stealAmount, ledgerService.Card(cardID)); err != nil {
 		return nil, err
 	}
 
@@ -479,7 +475,7 @@ func handleTimeout(s *discordgo.Session, db *gorm.DB, userID string, guildID str
 
 		lockoutUntil := time.Now().Add(2 * time.Hour)
 		randomUser.CardDrawTimeoutUntil = &lockoutUntil
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := db.Omit("points").Save(&randomUser).Error; err != nil {
 			return nil, err
 		}
 
@@ -508,7 +504,7 @@ func handleTimeout(s *discordgo.Session, db *gorm.DB, userID string, guildID str
 	timeoutUntil := time.Now().Add(2 * time.Hour)
 	user.CardDrawTimeoutUntil = &timeoutUntil
 
-	if err := db.Save(&user).Error; err != nil {
+	if err := db.Omit("points").Save(&user).Error; err != nil {
 		return nil, err
 	}
 
@@ -1088,11 +1084,11 @@ func handleTheSun(s *discordgo.Session, db *gorm.DB, userID string, guildID stri
 		if len(allUsers) == 0 {
 			if guild.Pool < gainAmount {
 				totalPoolDrain = guild.Pool
-				gainAmount = guild.Pool / 2.0
+				gainAmount = common.SplitPoints(guild.Pool, 2)
 			}
 			if guild.Pool < totalPoolDrain {
 				totalPoolDrain = guild.Pool
-				gainAmount = totalPoolDrain / 2.0
+				gainAmount = common.SplitPoints(totalPoolDrain, 2)
 			}
 
 			result = &models.CardResult{
@@ -1105,12 +1101,12 @@ func handleTheSun(s *discordgo.Session, db *gorm.DB, userID string, guildID stri
 
 		if guild.Pool < gainAmount {
 			totalPoolDrain = guild.Pool
-			gainAmount = totalPoolDrain / 2.0
+			gainAmount = common.SplitPoints(totalPoolDrain, 2)
 		}
 
 		if guild.Pool < totalPoolDrain {
 			totalPoolDrain = guild.Pool
-			gainAmount = totalPoolDrain / 2.0
+			gainAmount = common.SplitPoints(totalPoolDrain, 2)
 		}
 
 		randomIndex := rand.Intn(len(allUsers))
@@ -1182,7 +1178,7 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				return err
 			}
 
-			pointsLoss := lockedUser.Points * 0.05
+			pointsLoss := common.RoundPoints(lockedUser.Points * 0.05)
 			if pointsLoss <= 0 {
 				continue
 			}
@@ -1208,17 +1204,13 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("discord_id = ? AND guild_id = ?", randomDiscordID, guildID).First(&randomUser).Error; err != nil {
 					return err
 				}
-				deduct := randomUser.Points * 0.05
+				deduct := common.RoundPoints(randomUser.Points * 0.05)
 				if deduct > randomUser.Points {
 					deduct = randomUser.Points
 				}
 				if deduct > 0 {
 					randomUserPointsBefore := randomUser.Points
-					randomUser.Points -= deduct
-					if randomUser.Points < 0 {
-						randomUser.Points = 0
-					}
-					if err := tx.Save(&randomUser).Error; err != nil {
+					if _, err := ledgerService.ApplyFloored(tx, &randomUser, -deduct, ledgerService.Card(JudgementCardID)); err != nil {
 						return err
 					}
 
@@ -1244,11 +1236,7 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			}
 
 			pointsBefore := lockedUser.Points
-			lockedUser.Points -= pointsLoss
-			if lockedUser.Points < 0 {
-				lockedUser.Points = 0
-			}
-			if err := tx.Save(&lockedUser).Error; err != nil {
+			if _, err := ledgerService.ApplyFloored(tx, &lockedUser, -pointsLoss, ledgerService.Card(JudgementCardID)); err != nil {
 				return err
 			}
 
@@ -1262,13 +1250,10 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			top50Details = append(top50Details, fmt.Sprintf("%s: -%.0f points", username, pointsLoss))
 		}
 
-		guild.Pool += totalPointsToPool
+		guild.Pool = common.RoundPoints(guild.Pool + totalPointsToPool)
 
 		poolDistribution := guild.Pool * 0.10
-		gainPerBottomUser := 0.0
-		if bottom50PercentCount > 0 {
-			gainPerBottomUser = poolDistribution / float64(bottom50PercentCount)
-		}
+		gainPerBottomUser := common.SplitPoints(poolDistribution, bottom50PercentCount)
 
 		var bottom50Details []string
 		totalDistributed := 0.0
@@ -1281,8 +1266,7 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			}
 
 			pointsBefore := lockedUser.Points
-			lockedUser.Points += gainPerBottomUser
-			if err := tx.Save(&lockedUser).Error; err != nil {
+			if err := ledgerService.Apply(tx, &lockedUser, gainPerBottomUser, ledgerService.Card(JudgementCardID)); err != nil {
 				return err
 			}
 
@@ -1297,7 +1281,7 @@ func handleJudgement(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			bottom50Details = append(bottom50Details, fmt.Sprintf("%s: +%.0f points", username, gainPerBottomUser))
 		}
 
-		guild.Pool -= totalDistributed
+		guild.Pool = common.RoundPoints(guild.Pool - totalDistributed)
 		if guild.Pool < 0 {
 			guild.Pool = 0
 		}
@@ -1397,8 +1381,7 @@ func handleMajorGlitch(s *discordgo.Session, db *gorm.DB, userID string, guildID
 			}
 
 			pointsBefore := locked.Points
-			locked.Points += gainAmount
-			if err := tx.Save(&locked).Error; err != nil {
+			if err := ledgerService.Apply(tx, &locked, gainAmount, ledgerService.Card(26)); err != nil {
 				return err
 			}
 
@@ -1623,8 +1606,7 @@ func handleGenerousDonation(s *discordgo.Session, db *gorm.DB, userID string, gu
 		}
 
 		standardCost := guild.CardDrawCost
-		randomUser.Points += standardCost
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := ledgerService.Apply(db, &randomUser, standardCost, ledgerService.Card(GenerousDonationCardID)); err != nil {
 			return nil, err
 		}
 
@@ -1727,9 +1709,8 @@ func handleStimulusCheck(s *discordgo.Session, db *gorm.DB, userID string, guild
 			pointsBeforeMap[u.DiscordID] = u.Points
 		}
 
-		if err := db.Model(&models.User{}).
-			Where("guild_id = ? AND discord_id != ?", guildID, userID).
-			Update("points", gorm.Expr("points + ?", gainAmount)).Error; err != nil {
+		gain := func(float64) float64 { return gainAmount }
+		if err := ledgerService.ApplyMany(db, otherUsers, gain, ledgerService.Card(StimulusCheckCardID)); err != nil {
 			return nil, err
 		}
 
@@ -1788,9 +1769,8 @@ func handleTheHierophant(s *discordgo.Session, db *gorm.DB, userID string, guild
 			pointsBeforeMap[u.DiscordID] = u.Points
 		}
 
-		if err := db.Model(&models.User{}).
-			Where("guild_id = ? AND discord_id != ?", guildID, userID).
-			Update("points", gorm.Expr("points + ?", gainAmount)).Error; err != nil {
+		gain := func(float64) float64 { return gainAmount }
+		if err := ledgerService.ApplyMany(db, otherUsers, gain, ledgerService.Card(TheHierophantCardID)); err != nil {
 			return nil, err
 		}
 
@@ -1942,7 +1922,7 @@ func ExecuteBetFreeze(s *discordgo.Session, db *gorm.DB, userID string, targetUs
 
 		timeoutUntil := time.Now().Add(2 * time.Hour)
 		randomUser.BetLockoutUntil = &timeoutUntil
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := db.Omit("points").Save(&randomUser).Error; err != nil {
 			return nil, err
 		}
 
@@ -1990,7 +1970,7 @@ func ExecuteBetFreeze(s *discordgo.Session, db *gorm.DB, userID string, targetUs
 	lockoutUntil := time.Now().Add(2 * time.Hour)
 	targetUser.BetLockoutUntil = &lockoutUntil
 
-	if err := db.Save(&targetUser).Error; err != nil {
+	if err := db.Omit("points").Save(&targetUser).Error; err != nil {
 		return nil, err
 	}
 
@@ -2088,8 +2068,7 @@ func handleGreenShells(s *discordgo.Session, db *gorm.DB, userID string, guildID
 					loss = randomLockedTarget.Points
 				}
 
-				randomLockedTarget.Points -= loss
-				if err := tx.Save(&randomLockedTarget).Error; err != nil {
+				if err := ledgerService.Apply(tx, &randomLockedTarget, -loss, ledgerService.Card(63)); err != nil {
 					return err
 				}
 
@@ -2131,8 +2110,7 @@ func handleGreenShells(s *discordgo.Session, db *gorm.DB, userID string, guildID
 				loss = lockedTarget.Points
 			}
 
-			lockedTarget.Points -= loss
-			if err := tx.Save(&lockedTarget).Error; err != nil {
+			if err := ledgerService.Apply(tx, &lockedTarget, -loss, ledgerService.Card(63)); err != nil {
 				return err
 			}
 
@@ -2221,8 +2199,7 @@ func handleWhackAMole(s *discordgo.Session, db *gorm.DB, userID string, guildID
 				loss = lockedTarget.Points
 			}
 
-			lockedTarget.Points -= loss
-			if err := tx.Save(&lockedTarget).Error; err != nil {
+			if err := ledgerService.Apply(tx, &lockedTarget, -loss, ledgerService.Card(65)); err != nil {
 				return err
 			}
 
@@ -2397,11 +2374,7 @@ func handleSocialism(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				}
 				if actualTake > 0 {
 					pointsBefore := randomUser.Points
-					randomUser.Points -= actualTake
-					if randomUser.Points < 0 {
-						randomUser.Points = 0
-					}
-					if err := tx.Save(&randomUser).Error; err != nil {
+					if _, err := ledgerService.ApplyFloored(tx, &randomUser, -actualTake, ledgerService.Card(41)); err != nil {
 						return err
 					}
 					if randomUser.DiscordID != userID {
@@ -2431,11 +2404,10 @@ func handleSocialism(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 
 			if takeAmount > 0 {
 				pointsBefore := lockedTop.Points
-				lockedTop.Points -= takeAmount
-				totalCollected += takeAmount
-				if err := tx.Save(&lockedTop).Error; err != nil {
+				if err := ledgerService.Apply(tx, &lockedTop, -takeAmount, ledgerService.Card(41)); err != nil {
 					return err
 				}
+				totalCollected += takeAmount
 				if lockedTop.DiscordID != userID {
 					if err := historyService.RecordCardPlayHistory(tx, guildID, lockedTop.DiscordID, lockedTop.ID, 41, "Socialism", userID, pointsBefore, lockedTop.Points, lockedTop.Points-pointsBefore, nil, nil, nil); err != nil {
 						fmt.Printf("Error recording history for Socialism top player: %v\n", err)
@@ -2455,8 +2427,7 @@ func handleSocialism(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				}
 
 				pointsBefore := lockedBottom.Points
-				lockedBottom.Points += amountPerBottomPlayer
-				if err := tx.Save(&lockedBottom).Error; err != nil {
+				if err := ledgerService.Apply(tx, &lockedBottom, amountPerBottomPlayer, ledgerService.Card(41)); err != nil {
 					return err
 				}
 				if lockedBottom.DiscordID != userID {
@@ -2618,8 +2589,7 @@ func handleRobinHood(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				return nil
 			}
 
-			randomUser.Points -= takeAmount
-			if err := tx.Save(&randomUser).Error; err != nil {
+			if err := ledgerService.Apply(tx, &randomUser, -takeAmount, ledgerService.Card(42)); err != nil {
 				return err
 			}
 			if randomUser.DiscordID != userID {
@@ -2675,8 +2645,7 @@ func handleRobinHood(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		}
 
 		topPointsBefore := lockedTop.Points
-		lockedTop.Points -= takeAmount
-		if err := tx.Save(&lockedTop).Error; err != nil {
+		if err := ledgerService.Apply(tx, &lockedTop, -takeAmount, ledgerService.Card(42)); err != nil {
 			return err
 		}
 		if lockedTop.DiscordID != userID {
@@ -2686,8 +2655,7 @@ func handleRobinHood(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		}
 
 		bottomPointsBefore := lockedBottom.Points
-		lockedBottom.Points += 150.0
-		if err := tx.Save(&lockedBottom).Error; err != nil {
+		if err := ledgerService.Apply(tx, &lockedBottom, 150.0, ledgerService.Card(42)); err != nil {
 			return err
 		}
 		if lockedBottom.DiscordID != userID {
@@ -2823,8 +2791,7 @@ func handleRedShells(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				}
 
 				pointsBefore := randomLockedTarget.Points
-				randomLockedTarget.Points -= loss
-				if err := tx.Save(&randomLockedTarget).Error; err != nil {
+				if err := ledgerService.Apply(tx, &randomLockedTarget, -loss, ledgerService.Card(64)); err != nil {
 					return err
 				}
 				if randomLockedTarget.DiscordID != userID {
@@ -2879,8 +2846,7 @@ func handleRedShells(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 
 			if loss > 0 {
 				pointsBefore := lockedTarget.Points
-				lockedTarget.Points -= loss
-				if err := tx.Save(&lockedTarget).Error; err != nil {
+				if err := ledgerService.Apply(tx, &lockedTarget, -loss, ledgerService.Card(64)); err != nil {
 					return err
 				}
 				if lockedTarget.DiscordID != userID {
@@ -3135,11 +3101,10 @@ func ExecuteHostileTakeover(tx *gorm.DB, userID string, targetUserID string, gui
 					return err
 				}
 				randomOrig := randomUser.Points
-				drawer.Points, randomUser.Points = randomUser.Points, drawer.Points
-				if err := tx.Save(&drawer).Error; err != nil {
+				if err := ledgerService.SetBalance(tx, &drawer, randomOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 					return err
 				}
-				if err := tx.Save(&randomUser).Error; err != nil {
+				if err := ledgerService.SetBalance(tx, &randomUser, drawerOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 					return err
 				}
 				randomMention := "<@" + randomUser.DiscordID + ">"
@@ -3202,11 +3167,10 @@ func ExecuteHostileTakeover(tx *gorm.DB, userID string, targetUserID string, gui
 					return err
 				}
 				randomOrig := randomUser.Points
-				drawer.Points, randomUser.Points = randomUser.Points, drawer.Points
-				if err := tx.Save(&drawer).Error; err != nil {
+				if err := ledgerService.SetBalance(tx, &drawer, randomOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 					return err
 				}
-				if err := tx.Save(&randomUser).Error; err != nil {
+				if err := ledgerService.SetBalance(tx, &randomUser, drawerOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 					return err
 				}
 				randomMention := "<@" + randomUser.DiscordID + ">"
@@ -3235,11 +3199,10 @@ func ExecuteHostileTakeover(tx *gorm.DB, userID string, targetUserID string, gui
 			}
 		}
 
-		drawer.Points, target.Points = target.Points, drawer.Points
-		if err := tx.Save(&drawer).Error; err != nil {
+		if err := ledgerService.SetBalance(tx, &drawer, targetOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 			return err
 		}
-		if err := tx.Save(&target).Error; err != nil {
+		if err := ledgerService.SetBalance(tx, &target, drawerOrig, ledgerService.Card(HostileTakeoverCardID)); err != nil {
 			return err
 		}
 		result = &models.CardResult{
@@ -3450,13 +3413,12 @@ func handleBlueShell(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			}
 
 			percentage := 0.1
-			deductAmount := randomUser.Points * percentage
+			deductAmount := common.RoundPoints(randomUser.Points * percentage)
 			if randomUser.Points < deductAmount {
 				deductAmount = randomUser.Points
 			}
 
-			randomUser.Points -= deductAmount
-			if err := tx.Save(&randomUser).Error; err != nil {
+			if err := ledgerService.Apply(tx, &randomUser, -deductAmount, ledgerService.Card(38)); err != nil {
 				return err
 			}
 
@@ -3509,7 +3471,7 @@ func handleBlueShell(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		}
 
 		percentage := 0.1
-		deductAmount := firstPlaceUser.Points * percentage
+		deductAmount := common.RoundPoints(firstPlaceUser.Points * percentage)
 		if firstPlaceUser.Points < deductAmount {
 			deductAmount = firstPlaceUser.Points
 		}
@@ -3556,9 +3518,8 @@ func handleNuke(s *discordgo.Session, db *gorm.DB, userID string, guildID string
 		pointsBeforeMap[u.DiscordID] = u.Points
 	}
 
-	if err := db.Model(&models.User{}).
-		Where("guild_id = ?", guildID).
-		Update("points", gorm.Expr("points * 0.75")).Error; err != nil {
+	loss := func(before float64) float64 { return before*0.75 - before }
+	if err := ledgerService.ApplyMany(db, allUsers, loss, ledgerService.Card(TheNukeCardID)); err != nil {
 		return nil, err
 	}
 
@@ -3585,7 +3546,7 @@ func handleNuke(s *discordgo.Session, db *gorm.DB, userID string, guildID string
 	return &models.CardResult{
 		Message:     "You've drawn The Nuke! Everyone (including you) loses 25% of their points to the Pool.",
 		PointsDelta: 0,
-		PoolDelta:   -(guild.Pool * 0.25),
+		PoolDelta:   -common.RoundPoints(guild.Pool * 0.25),
 	}, nil
 }
 
@@ -3608,9 +3569,8 @@ func handleEMP(s *discordgo.Session, db *gorm.DB, userID string, guildID string)
 		pointsBeforeMap[u.DiscordID] = u.Points
 	}
 
-	if err := db.Model(&models.User{}).
-		Where("guild_id = ?", guildID).
-		Update("points", gorm.Expr("points * 0.95")).Error; err != nil {
+	loss := func(before float64) float64 { return before*0.95 - before }
+	if err := ledgerService.ApplyMany(db, allUsers, loss, ledgerService.Card(EMPCardID)); err != nil {
 		return nil, err
 	}
 
@@ -3637,7 +3597,7 @@ func handleEMP(s *discordgo.Session, db *gorm.DB, userID string, guildID string)
 	return &models.CardResult{
 		Message:     "You've drawn EMP! Everyone (including you and the pool) loses 5% of their points.",
 		PointsDelta: 0,
-		PoolDelta:   -(guild.Pool * 0.05),
+		PoolDelta:   -common.RoundPoints(guild.Pool * 0.05),
 	}, nil
 }
 
@@ -3701,7 +3661,7 @@ func handleGuillotine(s *discordgo.Session, db *gorm.DB, userID string, guildID
 
 	return &models.CardResult{
 		Message:     "You've drawn The Guillotine! You lost 15% of your points.",
-		PointsDelta: -(user.Points * 0.15),
+		PointsDelta: -common.RoundPoints(user.Points * 0.15),
 		PoolDelta:   0,
 	}, nil
 }
@@ -3741,7 +3701,7 @@ func handleRobbingTheHood(s *discordgo.Session, db *gorm.DB, userID string, guil
 			return err
 		}
 
-		stolenAmount := poorestUser.Points * 0.1
+		stolenAmount := common.RoundPoints(poorestUser.Points * 0.1)
 		poorestDisplayName := ""
 		if poorestUser.Username == nil || *poorestUser.Username == "" {
 			poorestDisplayName = fmt.Sprintf("<@%s>", poorestUser.DiscordID)
@@ -3781,19 +3741,14 @@ func handleRobbingTheHood(s *discordgo.Session, db *gorm.DB, userID string, guil
 				First(&randomUser).Error; err != nil {
 				return err
 			}
-			redirectStolen := randomUser.Points * 0.1
+			redirectStolen := common.RoundPoints(randomUser.Points * 0.1)
 			if redirectStolen > randomUser.Points {
 				redirectStolen = randomUser.Points
 			}
-			randomUser.Points -= redirectStolen
-			if randomUser.Points < 0 {
-				randomUser.Points = 0
-			}
-			if err := tx.Save(&randomUser).Error; err != nil {
+			if _, err := ledgerService.ApplyFloored(tx, &randomUser, -redirectStolen, ledgerService.Card(71)); err != nil {
 				return err
 			}
-			drawer.Points += redirectStolen
-			if err := tx.Save(&drawer).Error; err != nil {
+			if err := ledgerService.Apply(tx, &drawer, redirectStolen, ledgerService.Card(71)); err != nil {
 				return err
 			}
 			randomDisplayName := ""
@@ -3825,12 +3780,10 @@ func handleRobbingTheHood(s *discordgo.Session, db *gorm.DB, userID string, guil
 			return nil
 		}
 
-		poorestUser.Points -= stolenAmount
-		if err := tx.Save(&poorestUser).Error; err != nil {
+		if err := ledgerService.Apply(tx, &poorestUser, -stolenAmount, ledgerService.Card(71)); err != nil {
 			return err
 		}
-		drawer.Points += stolenAmount
-		if err := tx.Save(&drawer).Error; err != nil {
+		if err := ledgerService.Apply(tx, &drawer, stolenAmount, ledgerService.Card(71)); err != nil {
 			return err
 		}
 
@@ -3876,7 +3829,7 @@ func handleSnipSnap(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		}
 
 		var entries []models.BetEntry
-		if err := tx.Preload("Bet").
+		if err := tx.Preload("Bet").Preload("Bet.Options").
 			Joins("JOIN bets ON bets.id = bet_entries.bet_id").
 			Where("bet_entries.user_id = ? AND bets.paid = ? AND bet_entries.deleted_at IS NULL", user.ID, false).
 			Find(&entries).Error; err != nil {
@@ -3896,12 +3849,13 @@ func handleSnipSnap(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		entryToFlip := entries[randomIndex]
 
 		oldOption := entryToFlip.Option
-		newOption := 0
-		if oldOption == 1 {
-			newOption = 2
-		} else {
-			newOption = 1
+		var otherOptions []int
+		for _, option := range common.GetBetOptions(entryToFlip.Bet) {
+			if option.OptionNumber != oldOption {
+				otherOptions = append(otherOptions, option.OptionNumber)
+			}
 		}
+		newOption := otherOptions[rand.Intn(len(otherOptions))]
 
 		entryToFlip.Option = newOption
 		if err := tx.Save(&entryToFlip).Error; err != nil {
@@ -3909,12 +3863,7 @@ func handleSnipSnap(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		}
 
 		betName := entryToFlip.Bet.Description
-		newOptionName := ""
-		if newOption == 1 {
-			newOptionName = entryToFlip.Bet.Option1
-		} else {
-			newOptionName = entryToFlip.Bet.Option2
-		}
+		newOptionName := common.GetOptionName(entryToFlip.Bet, newOption)
 
 		result = &models.CardResult{
 			Message:     fmt.Sprintf("Snip Snap Snip Snap! Your bet on **%s** has been flipped! You are now betting on **%s**.", betName, newOptionName),
@@ -3975,7 +3924,7 @@ func handleLehmanBrothersInsider(s *discordgo.Session, db *gorm.DB, userID strin
 	return &models.CardResult{
 		Message:     "You've drawn Lehman Brothers Insider! The pool loses 20% of its total points.",
 		PointsDelta: 0,
-		PoolDelta:   -(guild.Pool * 0.2),
+		PoolDelta:   -common.RoundPoints(guild.Pool * 0.2),
 	}, nil
 }
 
@@ -4063,11 +4012,7 @@ func handleHotPotato(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				if randomUser.Points < deduct {
 					deduct = randomUser.Points
 				}
-				randomUser.Points -= deduct
-				if randomUser.Points < 0 {
-					randomUser.Points = 0
-				}
-				if err := tx.Save(&randomUser).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &randomUser, -deduct, ledgerService.Card(210)); err != nil {
 					return err
 				}
 				randomMention := "<@" + randomUser.DiscordID + ">"
@@ -4083,8 +4028,7 @@ func handleHotPotato(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				messageParts = append(messageParts, fmt.Sprintf("%s's %s blocked your loss!", userMention, protectionName(blockedByRedshirt)))
 			} else {
 				drawerActualLoss = userLoss
-				user.Points -= userLoss
-				if err := tx.Save(&user).Error; err != nil {
+				if err := ledgerService.Apply(tx, &user, -userLoss, ledgerService.Card(210)); err != nil {
 					return err
 				}
 			}
@@ -4116,11 +4060,7 @@ func handleHotPotato(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				if randomUser.Points < deduct {
 					deduct = randomUser.Points
 				}
-				randomUser.Points -= deduct
-				if randomUser.Points < 0 {
-					randomUser.Points = 0
-				}
-				if err := tx.Save(&randomUser).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &randomUser, -deduct, ledgerService.Card(210)); err != nil {
 					return err
 				}
 				randomMention := "<@" + randomUser.DiscordID + ">"
@@ -4136,8 +4076,7 @@ func handleHotPotato(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				messageParts = append(messageParts, fmt.Sprintf("%s's %s blocked the hot potato!", targetMention, protectionName(blockedByRedshirt)))
 			} else {
 				targetActualLoss = targetLoss
-				lockedTarget.Points -= targetLoss
-				if err := tx.Save(&lockedTarget).Error; err != nil {
+				if err := ledgerService.Apply(tx, &lockedTarget, -targetLoss, ledgerService.Card(210)); err != nil {
 					return err
 				}
 			}
@@ -4252,13 +4191,12 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				if randomUser.Points < actualTransfer {
 					actualTransfer = randomUser.Points
 				}
-				randomUser.Points -= actualTransfer
-				if randomUser.Points < 0 {
-					randomUser.Points = 0
+				if _, err := ledgerService.ApplyFloored(tx, &randomUser, -actualTransfer, ledgerService.Card(DuelCardID)); err != nil {
+					return err
+				}
+				if err := ledgerService.Apply(tx, &user, actualTransfer, ledgerService.Card(DuelCardID)); err != nil {
+					return err
 				}
-				tx.Save(&randomUser)
-				user.Points += actualTransfer
-				tx.Save(&user)
 				randomMention := "<@" + randomUser.DiscordID + ">"
 				result = &models.CardResult{
 					Message:           fmt.Sprintf("⚔️ DUEL! %s rolled %d, %s rolled %d. You win! %s's Moon redirected the loss to %s — you gained %.0f points!", userMention, userRoll, targetMention, targetRoll, targetMention, randomMention, actualTransfer),
@@ -4284,13 +4222,10 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				return nil
 			}
 
-			user.Points += transferAmount
-			targetUser.Points -= transferAmount
-
-			if err := tx.Save(&user).Error; err != nil {
+			if err := ledgerService.Apply(tx, &user, transferAmount, ledgerService.Card(DuelCardID)); err != nil {
 				return err
 			}
-			if err := tx.Save(&targetUser).Error; err != nil {
+			if err := ledgerService.Apply(tx, &targetUser, -transferAmount, ledgerService.Card(DuelCardID)); err != nil {
 				return err
 			}
 
@@ -4345,13 +4280,12 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				if randomUser.Points < actualTransfer {
 					actualTransfer = randomUser.Points
 				}
-				randomUser.Points -= actualTransfer
-				if randomUser.Points < 0 {
-					randomUser.Points = 0
+				if _, err := ledgerService.ApplyFloored(tx, &randomUser, -actualTransfer, ledgerService.Card(DuelCardID)); err != nil {
+					return err
+				}
+				if err := ledgerService.Apply(tx, &targetUser, actualTransfer, ledgerService.Card(DuelCardID)); err != nil {
+					return err
 				}
-				tx.Save(&randomUser)
-				targetUser.Points += actualTransfer
-				tx.Save(&targetUser)
 				randomMention := "<@" + randomUser.DiscordID + ">"
 				result = &models.CardResult{
 					Message:           fmt.Sprintf("⚔️ DUEL! %s rolled %d, %s rolled %d. You lose! Your Moon redirected the loss to %s — %s gained %.0f points!", userMention, userRoll, targetMention, targetRoll, randomMention, targetMention, actualTransfer),
@@ -4377,13 +4311,10 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				return nil
 			}
 
-			user.Points -= transferAmount
-			targetUser.Points += transferAmount
-
-			if err := tx.Save(&user).Error; err != nil {
+			if err := ledgerService.Apply(tx, &user, -transferAmount, ledgerService.Card(DuelCardID)); err != nil {
 				return err
 			}
-			if err := tx.Save(&targetUser).Error; err != nil {
+			if err := ledgerService.Apply(tx, &targetUser, transferAmount, ledgerService.Card(DuelCardID)); err != nil {
 				return err
 			}
 
@@ -4426,11 +4357,9 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 					if ru.Points < deduct {
 						deduct = ru.Points
 					}
-					ru.Points -= deduct
-					if ru.Points < 0 {
-						ru.Points = 0
+					if _, err := ledgerService.ApplyFloored(tx, &ru, -deduct, ledgerService.Card(DuelCardID)); err != nil {
+						return err
 					}
-					tx.Save(&ru)
 				}
 			} else {
 				blocked, _, err := CheckAndConsumeShieldOrRedshirt(tx, user.ID, guildID)
@@ -4439,8 +4368,9 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				}
 				if !blocked {
 					userActualLoss = userLoss
-					user.Points -= userLoss
-					tx.Save(&user)
+					if err := ledgerService.Apply(tx, &user, -userLoss, ledgerService.Card(DuelCardID)); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -4462,11 +4392,9 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 					if ru.Points < deduct {
 						deduct = ru.Points
 					}
-					ru.Points -= deduct
-					if ru.Points < 0 {
-						ru.Points = 0
+					if _, err := ledgerService.ApplyFloored(tx, &ru, -deduct, ledgerService.Card(DuelCardID)); err != nil {
+						return err
 					}
-					tx.Save(&ru)
 				}
 			} else {
 				blocked, _, err := CheckAndConsumeShieldOrRedshirt(tx, targetUser.ID, guildID)
@@ -4475,8 +4403,9 @@ func ExecuteDuel(db *gorm.DB, userID string, targetUserID string, guildID string
 				}
 				if !blocked {
 					targetActualLoss = targetLoss
-					targetUser.Points -= targetLoss
-					tx.Save(&targetUser)
+					if err := ledgerService.Apply(tx, &targetUser, -targetLoss, ledgerService.Card(DuelCardID)); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -4596,13 +4525,10 @@ func handleReversePickpocket(s *discordgo.Session, db *gorm.DB, userID string, g
 			transferAmount = user.Points
 		}
 
-		user.Points -= transferAmount
-		lockedTarget.Points += transferAmount
-
-		if err := tx.Save(&user).Error; err != nil {
+		if err := ledgerService.Apply(tx, &user, -transferAmount, ledgerService.Card(202)); err != nil {
 			return err
 		}
-		if err := tx.Save(&lockedTarget).Error; err != nil {
+		if err := ledgerService.Apply(tx, &lockedTarget, transferAmount, ledgerService.Card(202)); err != nil {
 			return err
 		}
 
@@ -4802,7 +4728,7 @@ func ExecuteSocialDistancing(db *gorm.DB, userID string, targetUserID string, gu
 
 		lockoutUntil := time.Now().Add(2 * time.Hour)
 		randomUser.CardDrawTimeoutUntil = &lockoutUntil
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := db.Omit("points").Save(&randomUser).Error; err != nil {
 			return nil, err
 		}
 
@@ -4868,7 +4794,7 @@ func ExecuteSocialDistancing(db *gorm.DB, userID string, targetUserID string, gu
 	lockoutUntil := time.Now().Add(2 * time.Hour)
 	targetUser.CardDrawTimeoutUntil = &lockoutUntil
 
-	if err := db.Save(&targetUser).Error; err != nil {
+	if err := db.Omit("points").Save(&targetUser).Error; err != nil {
 		return nil, err
 	}
 
@@ -4929,7 +4855,7 @@ func handleTheHermit(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 				lockoutUntil := time.Now().Add(12 * time.Hour)
 				user.CardDrawTimeoutUntil = &lockoutUntil
 
-				if err := db.Save(&user).Error; err != nil {
+				if err := db.Omit("points").Save(&user).Error; err != nil {
 					return nil, err
 				}
 
@@ -4952,7 +4878,7 @@ func handleTheHermit(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 
 		lockoutUntil := time.Now().Add(12 * time.Hour)
 		randomUser.CardDrawTimeoutUntil = &lockoutUntil
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := db.Omit("points").Save(&randomUser).Error; err != nil {
 			return nil, err
 		}
 
@@ -5005,7 +4931,7 @@ func handleTheHermit(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		lockoutUntil := time.Now().Add(12 * time.Hour)
 		user.CardDrawTimeoutUntil = &lockoutUntil
 
-		if err := db.Save(&user).Error; err != nil {
+		if err := db.Omit("points").Save(&user).Error; err != nil {
 			return nil, err
 		}
 
@@ -5174,13 +5100,10 @@ func ExecuteJustice(s *discordgo.Session, db *gorm.DB, userID string, targetUser
 		drawerOriginalPoints := drawer.Points
 		randomOriginalPoints := randomUser.Points
 		averagePoints := (drawer.Points + randomUser.Points) / 2.0
-		drawer.Points = averagePoints
-		randomUser.Points = averagePoints
-
-		if err := db.Save(&drawer).Error; err != nil {
+		if err := ledgerService.SetBalance(db, &drawer, averagePoints, ledgerService.Card(JusticeCardID)); err != nil {
 			return nil, err
 		}
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := ledgerService.SetBalance(db, &randomUser, averagePoints, ledgerService.Card(JusticeCardID)); err != nil {
 			return nil, err
 		}
 
@@ -5213,13 +5136,10 @@ func ExecuteJustice(s *discordgo.Session, db *gorm.DB, userID string, targetUser
 	drawerOriginalPoints := drawer.Points
 	targetOriginalPoints := target.Points
 	averagePoints := (drawer.Points + target.Points) / 2.0
-	drawer.Points = averagePoints
-	target.Points = averagePoints
-
-	if err := db.Save(&drawer).Error; err != nil {
+	if err := ledgerService.SetBalance(db, &drawer, averagePoints, ledgerService.Card(JusticeCardID)); err != nil {
 		return nil, err
 	}
-	if err := db.Save(&target).Error; err != nil {
+	if err := ledgerService.SetBalance(db, &target, averagePoints, ledgerService.Card(JusticeCardID)); err != nil {
 		return nil, err
 	}
 
@@ -5248,12 +5168,7 @@ func handleTheHangedMan(s *discordgo.Session, db *gorm.DB, userID string, guildI
 			lossAmount = user.Points
 		}
 
-		user.Points -= lossAmount
-		if user.Points < 0 {
-			user.Points = 0
-		}
-
-		if err := tx.Save(&user).Error; err != nil {
+		if _, err := ledgerService.ApplyFloored(tx, &user, -lossAmount, ledgerService.Card(TheHangedManCardID)); err != nil {
 			return err
 		}
 
@@ -5598,7 +5513,7 @@ func handleBlackHole(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			return nil
 		}
 
-		poolAmount := guild.Pool * 0.25
+		poolAmount := common.RoundPoints(guild.Pool * 0.25)
 		if poolAmount <= 0 {
 			result = &models.CardResult{
 				Message:     "The pool is empty. The Black Hole has nothing to distribute.",
@@ -5614,7 +5529,7 @@ func handleBlackHole(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		}
 
 		bottomPlayers := allUsers[:numBottomPlayers]
-		amountPerPlayer := poolAmount / float64(numBottomPlayers)
+		amountPerPlayer := common.SplitPoints(poolAmount, numBottomPlayers)
 
 		var message string
 		var affectedUsers []string
@@ -5626,8 +5541,7 @@ func handleBlackHole(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 			}
 
 			pointsBefore := lockedPlayer.Points
-			lockedPlayer.Points += amountPerPlayer
-			if err := tx.Save(&lockedPlayer).Error; err != nil {
+			if err := ledgerService.Apply(tx, &lockedPlayer, amountPerPlayer, ledgerService.Card(BlackHoleCardID)); err != nil {
 				return err
 			}
 
@@ -5656,7 +5570,7 @@ func handleBlackHole(s *discordgo.Session, db *gorm.DB, userID string, guildID s
 		result = &models.CardResult{
 			Message:     message,
 			PointsDelta: 0,
-			PoolDelta:   -poolAmount,
+			PoolDelta:   -common.RoundPoints(amountPerPlayer * float64(numBottomPlayers)),
 		}
 		return nil
 	}); err != nil {
@@ -5778,7 +5692,7 @@ func handleDeath(s *discordgo.Session, db *gorm.DB, userID string, guildID strin
 
 		origPool := guild.Pool
 		actualDrain := math.Min(poolDrain, origPool)
-		guild.Pool -= actualDrain
+		guild.Pool = common.RoundPoints(guild.Pool - actualDrain)
 		if guild.Pool < 0 {
 			guild.Pool = 0
 		}
@@ -5818,9 +5732,8 @@ func handleTheTower(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		pointsBeforeMap[u.DiscordID] = u.Points
 	}
 
-	if err := db.Model(&models.User{}).
-		Where("guild_id = ?", guildID).
-		Update("points", gorm.Expr("GREATEST(0, points - ?)", 50)).Error; err != nil {
+	loss := func(before float64) float64 { return -math.Min(before, 50) }
+	if err := ledgerService.ApplyMany(db, allUsers, loss, ledgerService.Card(TheTowerCardID)); err != nil {
 		return nil, err
 	}
 
@@ -5840,7 +5753,7 @@ func handleTheTower(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		}
 	}
 
-	poolReduction := guild.Pool * 0.75
+	poolReduction := common.RoundPoints(guild.Pool * 0.75)
 	return &models.CardResult{
 		Message:     "The Tower has fallen! The pool was reduced by 75% and every player lost 50 points as the debris settled.",
 		PointsDelta: 0,
@@ -5863,10 +5776,10 @@ func handleTheWorld(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 			return err
 		}
 
-		poolWin := guild.Pool * 0.10
+		poolWin := common.RoundPoints(guild.Pool * 0.10)
 
 		var entries []models.BetEntry
-		if err := tx.Preload("Bet").
+		if err := tx.Preload("Bet").Preload("Bet.Options").
 			Joins("JOIN bets ON bets.id = bet_entries.bet_id").
 			Where("bet_entries.user_id = ? AND bets.paid = ? AND bet_entries.deleted_at IS NULL", user.ID, false).
 			Find(&entries).Error; err != nil {
@@ -5886,7 +5799,7 @@ func handleTheWorld(s *discordgo.Session, db *gorm.DB, userID string, guildID st
 		entry := entries[randomIndex]
 		bet := entry.Bet
 
-		basePayout := common.CalculatePayout(entry.Amount, entry.Option, bet)
+		basePayout := common.CalculateEntryPayout(entry, entry.Option, bet)
 		totalWin := basePayout + poolWin
 
 		if err := tx.Model(&user).UpdateColumn("total_bets_won", gorm.Expr("total_bets_won + 1")).Error; err != nil {
@@ -5962,7 +5875,7 @@ func handleTheGoldenWhistle(s *discordgo.Session, db *gorm.DB, userID string, gu
 				}
 			}
 			bet := entry.Bet
-			payout := common.CalculatePayout(entry.Amount, entry.Option, bet)
+			payout := common.CalculateEntryPayout(entry, entry.Option, bet)
 			userResolutions[entry.UserID].TotalPayout += payout
 			res := userResolutions[entry.UserID]
 			res.BetIDs = append(res.BetIDs, bet.ID)
@@ -5977,8 +5890,10 @@ func handleTheGoldenWhistle(s *discordgo.Session, db *gorm.DB, userID string, gu
 			}
 			pointsBefore := u.Points
 
+			if err := ledgerService.Apply(tx, &u, res.TotalPayout, ledgerService.Card(TheGoldenWhistleCardID)); err != nil {
+				return err
+			}
 			if err := tx.Model(&u).Updates(map[string]interface{}{
-				"points":           gorm.Expr("points + ?", res.TotalPayout),
 				"total_bets_won":   gorm.Expr("total_bets_won + ?", len(res.BetIDs)),
 				"total_points_won": gorm.Expr("total_points_won + ?", res.TotalPayout),
 			}).Error; err != nil {
@@ -6223,8 +6138,7 @@ func ExecuteBlindsideBlock(db *gorm.DB, userID string, targetUserID string, guil
 			deductAmount = randomUser.Points
 		}
 
-		randomUser.Points -= deductAmount
-		if err := db.Save(&randomUser).Error; err != nil {
+		if err := ledgerService.Apply(db, &randomUser, -deductAmount, ledgerService.Card(BlindsideBlockCardID)); err != nil {
 			return nil, err
 		}
 
@@ -6234,7 +6148,7 @@ func ExecuteBlindsideBlock(db *gorm.DB, userID string, targetUserID string, guil
 			First(&guild).Error; err != nil {
 			return nil, err
 		}
-		guild.Pool += deductAmount
+		guild.Pool = common.RoundPoints(guild.Pool + deductAmount)
 		if err := db.Save(&guild).Error; err != nil {
 			return nil, err
 		}
@@ -6272,8 +6186,7 @@ func ExecuteBlindsideBlock(db *gorm.DB, userID string, targetUserID string, guil
 		deductAmount = targetUser.Points
 	}
 
-	targetUser.Points -= deductAmount
-	if err := db.Save(&targetUser).Error; err != nil {
+	if err := ledgerService.Apply(db, &targetUser, -deductAmount, ledgerService.Card(BlindsideBlockCardID)); err != nil {
 		return nil, err
 	}
 
@@ -6283,7 +6196,7 @@ func ExecuteBlindsideBlock(db *gorm.DB, userID string, targetUserID string, guil
 		First(&guild).Error; err != nil {
 		return nil, err
 	}
-	guild.Pool += deductAmount
+	guild.Pool = common.RoundPoints(guild.Pool + deductAmount)
 	if err := db.Save(&guild).Error; err != nil {
 		return nil, err
 	}
@@ -6318,13 +6231,10 @@ func ExecuteAlleyOop(db *gorm.DB, userID string, targetUserID string, guildID st
 		giveAmount = user.Points
 	}
 
-	user.Points -= giveAmount
-	targetUser.Points += giveAmount
-
-	if err := db.Save(&user).Error; err != nil {
+	if err := ledgerService.Apply(db, &user, -giveAmount, ledgerService.Card(AlleyOopCardID)); err != nil {
 		return nil, err
 	}
-	if err := db.Save(&targetUser).Error; err != nil {
+	if err := ledgerService.Apply(db, &targetUser, giveAmount, ledgerService.Card(AlleyOopCardID)); err != nil {
 		return nil, err
 	}
 
@@ -6459,8 +6369,7 @@ func handleStormTheField(s *discordgo.Session, db *gorm.DB, userID string, guild
 
 	for _, user := range allUsers {
 		pointsBefore := user.Points
-		user.Points += 150
-		if err := db.Save(&user).Error; err != nil {
+		if err := ledgerService.Apply(db, &user, 150, ledgerService.Card(StormTheFieldCardID)); err != nil {
 			return nil, err
 		}
 
@@ -6484,7 +6393,7 @@ func handleRecruitingViolation(s *discordgo.Session, db *gorm.DB, userID string,
 		return nil, err
 	}
 
-	deductAmount := user.Points * 0.05
+	deductAmount := common.RoundPoints(user.Points * 0.05)
 	if user.Points < deductAmount {
 		deductAmount = user.Points
 	}
@@ -6507,7 +6416,7 @@ func handleNcaaSanctions(s *discordgo.Session, db *gorm.DB, userID string, guild
 	return &models.CardResult{
 		Message:     "NCAA Sanctions! The Pool is reduced by 15%.",
 		PointsDelta: 0,
-		PoolDelta:   -guild.Pool * 0.15,
+		PoolDelta:   -common.RoundPoints(guild.Pool * 0.15),
 	}, nil
 }
 
@@ -6533,14 +6442,8 @@ func handleMarchMadness(s *discordgo.Session, db *gorm.DB, userID string, guildI
 		pointsBefore := allUsers[i].Points
 		// Random multiplier in [-0.05, +0.05] (i.e. -5% to +5%)
 		multiplier := rand.Float64()*0.1 - 0.05
-		pointsChange := allUsers[i].Points * multiplier
-
-		allUsers[i].Points += pointsChange
-		if allUsers[i].Points < 0 {
-			allUsers[i].Points = 0
-		}
-
-		if err := db.Save(&allUsers[i]).Error; err != nil {
+		pointsChange, err := ledgerService.ApplyFloored(db, &allUsers[i], allUsers[i].Points*multiplier, ledgerService.Card(MarchMadnessCardID))
+		if err != nil {
 			return nil, err
 		}
 
@@ -6654,7 +6557,7 @@ func handleHeismanTrophy(s *discordgo.Session, db *gorm.DB, userID string, guild
 		return nil, err
 	}
 
-	poolWin := guild.Pool * 0.05
+	poolWin := common.RoundPoints(guild.Pool * 0.05)
 
 	inv := models.UserInventory{
 		UserID:    user.ID,
@@ -6686,17 +6589,16 @@ func handleNationalChampionship(s *discordgo.Session, db *gorm.DB, userID string
 	if err := db.Where("guild_id = ? AND discord_id != ? AND deleted_at IS NULL", guildID, userID).Find(&allUsers).Error; err != nil {
 		return nil, err
 	}
-	poolWin := guild.Pool * 0.2
+	poolWin := common.RoundPoints(guild.Pool * 0.2)
 	var otherUserPointsDelta float64
 	if len(allUsers) == 0 {
 		otherUserPointsDelta = 0
 	} else {
-		otherUserPointsDelta = math.Min(200.0, (guild.Pool-poolWin)/float64(len(allUsers)))
+		otherUserPointsDelta = math.Min(200.0, common.SplitPoints(guild.Pool-poolWin, len(allUsers)))
 	}
 	for _, user := range allUsers {
 		pointsBefore := user.Points
-		user.Points += otherUserPointsDelta
-		if err := db.Save(&user).Error; err != nil {
+		if err := ledgerService.Apply(db, &user, otherUserPointsDelta, ledgerService.Card(NationalChampionshipCardID)); err != nil {
 			return nil, err
 		}
 
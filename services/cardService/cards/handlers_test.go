@@ -36,8 +36,13 @@ func TestHandleNuke_AtomicUpdate(t *testing.T) {
 			AddRow(1, "user1", "guild1", 100.0))
 
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `users` SET .* WHERE guild_id = \\? AND `users`.`deleted_at` IS NULL").
-		WithArgs(sqlmock.AnyArg(), "guild1").
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE id IN \\(\\?\\) .* FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(1, 100.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(75.0, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	mock.ExpectQuery("SELECT \\* FROM `guilds` WHERE guild_id = \\? AND `guilds`.`deleted_at` IS NULL ORDER BY `guilds`.`id` LIMIT \\? FOR UPDATE").
@@ -70,8 +75,13 @@ func TestHandleMajorGlitch_AtomicUpdate(t *testing.T) {
 		WithArgs(2, 1).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "discord_id", "guild_id", "points"}).
 			AddRow(2, "target1", "guild1", 120.0))
-	mock.ExpectExec("UPDATE `users` SET .* WHERE `users`.`deleted_at` IS NULL AND `id` = \\?").
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE `users`.`id` = \\? .* FOR UPDATE").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(2, 120.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(220.0, 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec("INSERT INTO `card_play_histories` .*").
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -103,9 +113,19 @@ func TestHandleStimulusCheck_AtomicUpdate(t *testing.T) {
 			AddRow(2, "u2", "guild1", 100.0).
 			AddRow(3, "u3", "guild1", 200.0))
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `users` SET .* WHERE \\(guild_id = \\? AND discord_id != \\?\\) AND `users`.`deleted_at` IS NULL").
-		WithArgs(50.0, sqlmock.AnyArg(), "guild1", "drawer1").
-		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE id IN \\(\\?,\\?\\) .* FOR UPDATE").
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(2, 100.0).AddRow(3, 200.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(150.0, 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(250.0, 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO `card_play_histories` .*").
@@ -200,20 +220,30 @@ func TestExecutePickpocketSteal_LocksAndUpdates(t *testing.T) {
 		WithArgs(2, "guild1", ShieldCardID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `users` SET .* WHERE `users`.`deleted_at` IS NULL AND `id` = \\?").
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE `users`.`id` = \\? .* FOR UPDATE").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(1, 100.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(150.0, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `users` SET .* WHERE `users`.`deleted_at` IS NULL AND `id` = \\?").
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE `users`.`id` = \\? .* FOR UPDATE").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(2, 80.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs(30.0, 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries` .*").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	mock.ExpectQuery("SELECT \\* FROM `user_inventories` WHERE \\(user_id = \\? AND guild_id = \\? AND card_id = \\? AND deleted_at IS NULL\\) AND `user_inventories`.`deleted_at` IS NULL").
 		WithArgs(2, "guild1", BountyHunterCardID).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-	if _, err := ExecutePickpocketSteal(db, "drawer1", "target1", "guild1", 50.0); err != nil {
+	if _, err := ExecutePickpocketSteal(db, "drawer1", "target1", "guild1", 50.0, PettyTheftCardID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
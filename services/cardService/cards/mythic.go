@@ -15,7 +15,7 @@ func registerMythicCards(deck *[]models.Card) {
 			ID:             43,
 			Code:           "GOJ",
 			Name:           "Get Out of Jail Free",
-			Description:    "Nullifies the next lost bet completely.",
+			Description:    "Nullifies the next lost bet completely. Pushes don't count.",
 			Handler:        handleGetOutOfJail,
 			AddToInventory: true,
 		},
@@ -71,7 +71,7 @@ func registerUncommonCards(deck *[]models.Card) {
 			ID:             35,
 			Code:           "BIS",
 			Name:           "Bet Insurance",
-			Description:    "If you lose your next bet, get 25% of your wager back.",
+			Description:    "If you lose your next bet, get 25% of your wager back. Pushes don't count.",
 			Handler:        handleBetInsurance,
 			AddToInventory: true,
 			IsPositive:     true,
@@ -8,6 +8,7 @@ import (
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/historyService"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -46,8 +47,10 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 		return
 	}
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
-		db.Save(&user)
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
 	}
 
 	username := common.GetUsernameFromUser(i.Member.User)
@@ -354,8 +357,12 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 
 	*guild = lockedGuild
 
-	user.Points -= drawCardCost
-	guild.Pool += drawCardCost
+	if err := ledgerService.Apply(tx, &user, -drawCardCost, ledgerService.Entry{Reason: ledgerService.ReasonCardDraw}); err != nil {
+		tx.Rollback()
+		common.SendError(s, i, err, db)
+		return
+	}
+	guild.Pool = common.RoundPoints(guild.Pool + drawCardCost)
 
 	if guild.PoolDrainUntil != nil {
 		if now.After(*guild.PoolDrainUntil) {
@@ -372,7 +379,7 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 
 	user.CardDrawCount++
 
-	if err := tx.Save(&user).Error; err != nil {
+	if err := tx.Omit("points").Save(&user).Error; err != nil {
 		tx.Rollback()
 		common.SendError(s, i, err, db)
 		return
@@ -397,7 +404,13 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 	}
 	rarityMultiplier := calculateRarityMultiplier(distanceFromTop5)
 
-	hasSubscription := guild.SubscribedTeam != nil && *guild.SubscribedTeam != ""
+	var teamSubscriptions int64
+	if err := tx.Model(&models.GuildSubscription{}).Where("guild_id = ? AND kind = ?", guildID, models.SubscriptionTeam).Count(&teamSubscriptions).Error; err != nil {
+		tx.Rollback()
+		common.SendError(s, i, err, db)
+		return
+	}
+	hasSubscription := teamSubscriptions > 0
 	var allowedCardIDs []uint
 	if guild.RestrictedDrawEnabled {
 		restrictedUserIDs, restrictedUserIDsErr := guild.RestrictedDrawUserIDsSlice()
@@ -514,16 +527,17 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 				return
 			}
 
-			user.Points += cardResult.PointsDelta
-			if user.Points < 0 {
-				user.Points = 0
+			if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+				tx.Rollback()
+				common.SendError(s, i, err, db)
+				return
 			}
-			guild.Pool += cardResult.PoolDelta
+			guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 			if guild.Pool < 0 {
 				guild.Pool = 0
 			}
 
-			if err := tx.Save(&user).Error; err != nil {
+			if err := tx.Omit("points").Save(&user).Error; err != nil {
 				tx.Rollback()
 				common.SendError(s, i, err, db)
 				return
@@ -543,16 +557,17 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 		} else if cardResult.SelectionType == "bet" {
 			ShowBetSelectMenu(s, i, card.ID, card.Name, card.Description, userID, guildID, db)
 
-			user.Points += cardResult.PointsDelta
-			if user.Points < 0 {
-				user.Points = 0
+			if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+				tx.Rollback()
+				common.SendError(s, i, err, db)
+				return
 			}
-			guild.Pool += cardResult.PoolDelta
+			guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 			if guild.Pool < 0 {
 				guild.Pool = 0
 			}
 
-			if err := tx.Save(&user).Error; err != nil {
+			if err := tx.Omit("points").Save(&user).Error; err != nil {
 				tx.Rollback()
 				common.SendError(s, i, err, db)
 				return
@@ -575,16 +590,17 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 	if len(card.Options) > 0 {
 		ShowCardOptionsMenu(s, i, card.ID, card.Name, card.Description, userID, guildID, db, card.Options)
 
-		user.Points += cardResult.PointsDelta
-		if user.Points < 0 {
-			user.Points = 0
+		if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+			tx.Rollback()
+			common.SendError(s, i, err, db)
+			return
 		}
-		guild.Pool += cardResult.PoolDelta
+		guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 		if guild.Pool < 0 {
 			guild.Pool = 0
 		}
 
-		if err := tx.Save(&user).Error; err != nil {
+		if err := tx.Omit("points").Save(&user).Error; err != nil {
 			tx.Rollback()
 			common.SendError(s, i, err, db)
 			return
@@ -724,11 +740,12 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 		}
 	}
 
-	user.Points += cardResult.PointsDelta
-	if user.Points < 0 {
-		user.Points = 0
+	if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+		tx.Rollback()
+		common.SendError(s, i, err, db)
+		return
 	}
-	guild.Pool += cardResult.PoolDelta
+	guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 	if guild.Pool < 0 {
 		guild.Pool = 0
 	}
@@ -865,11 +882,7 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 
 				targetPointsBefore := userToUpdate.Points
 
-				userToUpdate.Points += cardResult.TargetPointsDelta
-				if userToUpdate.Points < 0 {
-					userToUpdate.Points = 0
-				}
-				if err := tx.Save(&userToUpdate).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &userToUpdate, cardResult.TargetPointsDelta, ledgerService.Card(card.ID)); err != nil {
 					tx.Rollback()
 					common.SendError(s, i, err, db)
 					return
@@ -887,7 +900,7 @@ func DrawCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 		}
 	}
 
-	if err := tx.Save(&user).Error; err != nil {
+	if err := tx.Omit("points").Save(&user).Error; err != nil {
 		tx.Rollback()
 		common.SendError(s, i, err, db)
 		return
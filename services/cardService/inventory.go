@@ -3,6 +3,7 @@ package cardService
 import (
 	"fmt"
 	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/guildService"
 
 	"github.com/bwmarrin/discordgo"
@@ -24,6 +25,73 @@ func PlayCardFromInventoryInTransaction(tx *gorm.DB, user models.User, cardID ui
 	return tx.Delete(&inventory).Error
 }
 
+// PlayCardOnBetInTransaction consumes a card and records the bet it was played
+// on, so the card can be restored if that bet is later voided.
+func PlayCardOnBetInTransaction(tx *gorm.DB, user models.User, cardID uint, betID uint) error {
+	var inventory models.UserInventory
+	result := tx.Where("user_id = ? AND guild_id = ? AND card_id = ? AND target_bet_id IS NULL", user.ID, user.GuildID, cardID).First(&inventory)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	inventory.TargetBetID = &betID
+	if err := tx.Save(&inventory).Error; err != nil {
+		return err
+	}
+
+	return tx.Delete(&inventory).Error
+}
+
+// RestoreCardsForVoidedBet hands back cards that were spent on a bet that has
+// been voided. Uno Reverse cards lose their target and can be played again
+// with /play-card, and Stop the Steal cards played on the bet are returned.
+// Cards that only trigger at resolution (such as Emotional Hedge) were never
+// consumed and need no restoring.
+func RestoreCardsForVoidedBet(db *gorm.DB, betID uint) ([]models.UserInventory, error) {
+	var restored []models.UserInventory
+
+	var unoCards []models.UserInventory
+	if err := db.Where("card_id = ? AND target_bet_id = ?", cards.UnoReverseCardID, betID).Find(&unoCards).Error; err != nil {
+		return nil, err
+	}
+	for _, card := range unoCards {
+		if err := db.Model(&card).Update("target_bet_id", nil).Error; err != nil {
+			return nil, err
+		}
+		restored = append(restored, card)
+	}
+
+	var stolenCards []models.UserInventory
+	if err := db.Unscoped().Where("card_id = ? AND target_bet_id = ? AND deleted_at IS NOT NULL", cards.StopTheStealCardID, betID).Find(&stolenCards).Error; err != nil {
+		return nil, err
+	}
+	for _, card := range stolenCards {
+		if err := db.Unscoped().Model(&card).Updates(map[string]interface{}{"deleted_at": nil, "target_bet_id": nil}).Error; err != nil {
+			return nil, err
+		}
+		restored = append(restored, card)
+	}
+
+	return restored, nil
+}
+
+// ReleaseCardsOnBet takes a user's unspent cards off a bet they no longer have
+// a stake in, such as after cashing out. Uno Reverse cards lose their target
+// and can be played again with /play-card.
+func ReleaseCardsOnBet(db *gorm.DB, userID uint, betID uint) ([]models.UserInventory, error) {
+	var released []models.UserInventory
+	if err := db.Where("user_id = ? AND target_bet_id = ?", userID, betID).Find(&released).Error; err != nil {
+		return nil, err
+	}
+	for _, card := range released {
+		if err := db.Model(&card).Update("target_bet_id", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+	return released, nil
+}
+
 func PlayCardFromInventoryWithMessage(s *discordgo.Session, db *gorm.DB, user models.User, cardID uint, customMessage string) error {
 	card := GetCardByID(cardID)
 	if card == nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"gorm.io/gorm"
@@ -36,7 +37,7 @@ func processRoyaltyPayment(tx *gorm.DB, card *models.Card, royaltyGuildID string
 		return fmt.Errorf("error fetching or creating royalty user: %v", result.Error)
 	}
 
-	if err := tx.Model(&royaltyUser).UpdateColumn("points", gorm.Expr("points + ?", royaltyAmount)).Error; err != nil {
+	if err := ledgerService.Apply(tx, &royaltyUser, royaltyAmount, ledgerService.Entry{Reason: ledgerService.ReasonCardRoyalty, CardID: card.ID}); err != nil {
 		return fmt.Errorf("error saving royalty user: %v", err)
 	}
 
@@ -107,11 +108,15 @@ func processTagCards(tx *gorm.DB, guildID string) error {
 	}
 
 	if len(userIDsToUpdate) > 0 {
-		if err := tx.Model(&models.User{}).
-			Where("id IN ? AND guild_id = ?", userIDsToUpdate, guildID).
-			UpdateColumn("points", gorm.Expr("points + 1.0")).Error; err != nil {
+		var users []models.User
+		if err := tx.Where("id IN ? AND guild_id = ?", userIDsToUpdate, guildID).Find(&users).Error; err != nil {
 			return err
 		}
+		for idx := range users {
+			if err := ledgerService.Apply(tx, &users[idx], 1.0, ledgerService.Card(cards.TagCardID)); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, expiredCard := range expiredCards {
@@ -0,0 +1,89 @@
+package cardService
+
+import (
+	"perfectOddsBot/services/cardService/cards"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRestoreCardsForVoidedBet(t *testing.T) {
+	t.Run("No cards played on bet", func(t *testing.T) {
+		db, mock, err := newMockDB()
+		if err != nil {
+			t.Fatalf("Failed to create mock DB: %v", err)
+		}
+		defer func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}()
+
+		betID := uint(123)
+
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(cards.UnoReverseCardID, betID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "guild_id", "card_id", "target_bet_id"}))
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(cards.StopTheStealCardID, betID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "guild_id", "card_id", "target_bet_id"}))
+
+		restored, err := RestoreCardsForVoidedBet(db, betID)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if len(restored) != 0 {
+			t.Errorf("Expected no restored cards, got %d", len(restored))
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("Uno Reverse and Stop the Steal returned", func(t *testing.T) {
+		db, mock, err := newMockDB()
+		if err != nil {
+			t.Fatalf("Failed to create mock DB: %v", err)
+		}
+		defer func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}()
+
+		betID := uint(123)
+
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(cards.UnoReverseCardID, betID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "guild_id", "card_id", "target_bet_id"}).
+				AddRow(1, 10, "guild1", cards.UnoReverseCardID, betID))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `user_inventories` SET `target_bet_id`=").
+			WithArgs(nil, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(cards.StopTheStealCardID, betID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "guild_id", "card_id", "target_bet_id"}).
+				AddRow(2, 11, "guild1", cards.StopTheStealCardID, betID))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `user_inventories` SET `deleted_at`=").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		restored, err := RestoreCardsForVoidedBet(db, betID)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if len(restored) != 2 {
+			t.Fatalf("Expected 2 restored cards, got %d", len(restored))
+		}
+		if restored[0].CardID != cards.UnoReverseCardID || restored[1].CardID != cards.StopTheStealCardID {
+			t.Errorf("Unexpected restored cards: %+v", restored)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
 	"strings"
 	"time"
 
@@ -24,11 +25,17 @@ func MyInventory(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.
 	}
 
 	var user models.User
-	result := db.Where(models.User{DiscordID: userID, GuildID: guildID}).Attrs(models.User{Points: guild.StartingPoints}).FirstOrCreate(&user)
+	result := db.FirstOrCreate(&user, models.User{DiscordID: userID, GuildID: guildID})
 	if result.Error != nil {
 		common.SendError(s, i, fmt.Errorf("error fetching user: %v", result.Error), db)
 		return
 	}
+	if result.RowsAffected == 1 {
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
+	}
 
 	inventory, err := getUserInventory(db, user.ID, guildID)
 	if err != nil {
@@ -244,11 +244,13 @@ func ShowBetSelectMenu(s *discordgo.Session, i *discordgo.InteractionCreate, car
 		Option      int
 		Option1     string
 		Option2     string
+		OptionName  *string
 	}
 
 	err := db.Table("bet_entries").
-		Select("bets.id as bet_id, bets.description, bet_entries.option, bets.option1, bets.option2").
+		Select("bets.id as bet_id, bets.description, bet_entries.option, bets.option1, bets.option2, bet_options.name as option_name").
 		Joins("JOIN bets ON bets.id = bet_entries.bet_id").
+		Joins("LEFT JOIN bet_options ON bet_options.bet_id = bets.id AND bet_options.option_number = bet_entries.option AND bet_options.deleted_at IS NULL").
 		Where("bet_entries.user_id = (SELECT id FROM users WHERE discord_id = ? AND guild_id = ?) AND bets.active = ? AND bets.paid = ? AND bet_entries.deleted_at IS NULL AND bets.deleted_at IS NULL", userID, guildID, true, false).
 		Limit(25).
 		Scan(&results).Error
@@ -275,7 +277,9 @@ func ShowBetSelectMenu(s *discordgo.Session, i *discordgo.InteractionCreate, car
 	options := []discordgo.SelectMenuOption{}
 	for _, res := range results {
 		pickedTeam := res.Option1
-		if res.Option == 2 {
+		if res.OptionName != nil {
+			pickedTeam = *res.OptionName
+		} else if res.Option == 2 {
 			pickedTeam = res.Option2
 		}
 
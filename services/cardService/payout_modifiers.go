@@ -0,0 +1,286 @@
+package cardService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService/cards"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// PayoutPhase is the outcome of a bet entry that a payout modifier runs on.
+type PayoutPhase int
+
+const (
+	PhaseWin PayoutPhase = iota
+	PhaseLoss
+	PhasePush
+)
+
+// PayoutContext describes the bet entry being settled.
+type PayoutContext struct {
+	DB        *gorm.DB
+	Consumer  CardConsumer
+	User      models.User
+	Bet       models.Bet
+	Entry     models.BetEntry
+	Phase     PayoutPhase
+	ScoreDiff int
+	// Scored is true when the bet was graded from a final score rather than
+	// resolved by hand.
+	Scored bool
+	// DryRun is set when a settlement is only being previewed. Modifiers with a
+	// random outcome describe the odds instead of rolling.
+	DryRun bool
+}
+
+// PayoutState is threaded through the modifiers for one entry. On a win
+// Amount is the payout; on a loss it is the amount lost, starting at the stake.
+type PayoutState struct {
+	Amount float64
+	// Refund is paid back to the bettor on top of Amount.
+	Refund float64
+	// PoolRefund is paid back to the bettor out of the losing stake that would
+	// otherwise go to the pool.
+	PoolRefund float64
+	// Stopped is set by a modifier that settles the entry on its own, such as
+	// Get Out of Jail Free. No later modifiers run.
+	Stopped     bool
+	Annotations []string
+	// Triggered lists the cards whose modifiers applied, in order.
+	Triggered []uint
+}
+
+// Annotation joins the modifier notes for the resolution embed,
+// e.g. " (Double Down: 2x payout!) (Bet Insurance: consumed)".
+func (p *PayoutState) Annotation() string {
+	if len(p.Annotations) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(p.Annotations, ") (") + ")"
+}
+
+// PayoutModifier is a card that changes what a bet entry pays. Modifiers run in
+// ascending Priority order within a phase. Apply returns a note describing what
+// it did, or "" when the card did not apply.
+type PayoutModifier interface {
+	CardID() uint
+	Priority() int
+	Phases() []PayoutPhase
+	Apply(ctx *PayoutContext, state *PayoutState) (annotation string, err error)
+}
+
+var (
+	payoutModifiers   []PayoutModifier
+	payoutModifiersMu sync.RWMutex
+)
+
+// RegisterPayoutModifier adds a modifier to the settlement pipeline.
+func RegisterPayoutModifier(modifier PayoutModifier) {
+	payoutModifiersMu.Lock()
+	defer payoutModifiersMu.Unlock()
+	payoutModifiers = append(payoutModifiers, modifier)
+	sort.SliceStable(payoutModifiers, func(a, b int) bool {
+		return payoutModifiers[a].Priority() < payoutModifiers[b].Priority()
+	})
+}
+
+// PayoutModifiersForPhase returns the registered modifiers for a phase in the
+// order they run.
+func PayoutModifiersForPhase(phase PayoutPhase) []PayoutModifier {
+	payoutModifiersMu.RLock()
+	defer payoutModifiersMu.RUnlock()
+
+	var modifiers []PayoutModifier
+	for _, modifier := range payoutModifiers {
+		for _, p := range modifier.Phases() {
+			if p == phase {
+				modifiers = append(modifiers, modifier)
+				break
+			}
+		}
+	}
+	return modifiers
+}
+
+// ApplyPayoutModifiers runs every modifier registered for ctx.Phase over state.
+func ApplyPayoutModifiers(ctx *PayoutContext, state *PayoutState) error {
+	for _, modifier := range PayoutModifiersForPhase(ctx.Phase) {
+		annotation, err := modifier.Apply(ctx, state)
+		if err != nil {
+			card := GetCardByID(modifier.CardID())
+			if card != nil {
+				return fmt.Errorf("error checking %s: %v", card.Name, err)
+			}
+			return fmt.Errorf("error checking card %d: %v", modifier.CardID(), err)
+		}
+		if annotation != "" {
+			state.Annotations = append(state.Annotations, annotation)
+			state.Triggered = append(state.Triggered, modifier.CardID())
+		}
+		if state.Stopped {
+			break
+		}
+	}
+	return nil
+}
+
+// cardPayoutModifier adapts one of the Apply*IfApplicable card effects to the
+// PayoutModifier interface.
+type cardPayoutModifier struct {
+	cardID   uint
+	priority int
+	phases   []PayoutPhase
+	apply    func(ctx *PayoutContext, state *PayoutState) (string, error)
+}
+
+func (m cardPayoutModifier) CardID() uint          { return m.cardID }
+func (m cardPayoutModifier) Priority() int         { return m.priority }
+func (m cardPayoutModifier) Phases() []PayoutPhase { return m.phases }
+func (m cardPayoutModifier) Apply(ctx *PayoutContext, state *PayoutState) (string, error) {
+	return m.apply(ctx, state)
+}
+
+func init() {
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.GetOutOfJailCardID,
+		priority: 0,
+		phases:   []PayoutPhase{PhaseLoss},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			refund, applied, err := ApplyGetOutOfJailIfApplicable(ctx.DB, ctx.Consumer, ctx.User, float64(ctx.Entry.Amount))
+			if err != nil || !applied || refund <= 0 {
+				return "", err
+			}
+			state.Refund += refund
+			state.Stopped = true
+			return "Get Out of Jail Free: Full refund!", nil
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.DoubleDownCardID,
+		priority: 10,
+		phases:   []PayoutPhase{PhaseWin},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			payout, applied, err := ApplyDoubleDownIfAvailable(ctx.DB, ctx.Consumer, ctx.User, state.Amount)
+			if err != nil || !applied {
+				return "", err
+			}
+			state.Amount = payout
+			return "Double Down: 2x payout!", nil
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.GamblerCardID,
+		priority: 20,
+		phases:   []PayoutPhase{PhaseWin, PhaseLoss},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			amount, applied, err := ApplyGamblerIfAvailable(ctx.DB, ctx.Consumer, ctx.User, state.Amount, ctx.Phase == PhaseWin)
+			if err != nil || !applied {
+				return "", err
+			}
+			if ctx.DryRun {
+				return "The Gambler: 50/50 to double", nil
+			}
+			doubled := amount > state.Amount
+			state.Amount = amount
+			switch {
+			case !doubled:
+				return "The Gambler: consumed, no double", nil
+			case ctx.Phase == PhaseWin:
+				return "The Gambler: 2x payout!", nil
+			default:
+				return "The Gambler: 2x loss!", nil
+			}
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.HomeFieldAdvantageCardID,
+		priority: 30,
+		phases:   []PayoutPhase{PhaseWin},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			payout, applied, err := ApplyHomeFieldAdvantageIfApplicable(ctx.DB, ctx.User, state.Amount)
+			if err != nil || !applied {
+				return "", err
+			}
+			state.Amount = payout
+			return "Home Field Advantage: +15!", nil
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.RoughingTheKickerCardID,
+		priority: 40,
+		phases:   []PayoutPhase{PhaseWin},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			payout, applied, err := ApplyRoughingTheKickerIfApplicable(ctx.DB, ctx.User, state.Amount)
+			if err != nil || !applied {
+				return "", err
+			}
+			state.Amount = payout
+			return "Roughing the Kicker: -15% payout", nil
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.HeismanCampaignCardID,
+		priority: 50,
+		phases:   []PayoutPhase{PhaseWin},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			payout, applied, err := ApplyHeismanCampaignIfApplicable(ctx.DB, ctx.User, state.Amount)
+			if err != nil || !applied {
+				return "", err
+			}
+			state.Amount = payout
+			return "Heisman Campaign: -15% payout", nil
+		},
+	})
+
+	// Emotional Hedge pays out on whether the subscribed team won straight up,
+	// so it only runs for bets graded from a final score.
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.EmotionalHedgeCardID,
+		priority: 60,
+		phases:   []PayoutPhase{PhaseWin, PhaseLoss},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			if !ctx.Scored {
+				return "", nil
+			}
+			refund, applied, err := ApplyEmotionalHedgeIfApplicable(ctx.DB, ctx.Consumer, ctx.User, ctx.Bet, ctx.Entry.Option, float64(ctx.Entry.Amount), ctx.ScoreDiff)
+			if err != nil || !applied {
+				return "", err
+			}
+			if refund > 0 {
+				state.Refund += refund
+				return fmt.Sprintf("Emotional Hedge: Refunding $%.1f", refund), nil
+			}
+			return "Emotional Hedge: consumed", nil
+		},
+	})
+
+	RegisterPayoutModifier(cardPayoutModifier{
+		cardID:   cards.BetInsuranceCardID,
+		priority: 70,
+		phases:   []PayoutPhase{PhaseWin, PhaseLoss},
+		apply: func(ctx *PayoutContext, state *PayoutState) (string, error) {
+			betAmount := float64(ctx.Entry.Amount)
+			if ctx.Phase == PhaseWin {
+				betAmount = 0
+			}
+			refund, applied, err := ApplyBetInsuranceIfApplicable(ctx.DB, ctx.Consumer, ctx.User, betAmount, ctx.Phase == PhaseWin)
+			if err != nil || !applied {
+				return "", err
+			}
+			if refund > 0 {
+				state.PoolRefund += refund
+				return fmt.Sprintf("Bet Insurance: Refunding $%.1f", refund), nil
+			}
+			return "Bet Insurance: consumed", nil
+		},
+	})
+}
@@ -0,0 +1,206 @@
+package cardService
+
+import (
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService/cards"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+func TestPayoutModifiersForPhase(t *testing.T) {
+	cardIDs := func(phase PayoutPhase) []uint {
+		var ids []uint
+		for _, modifier := range PayoutModifiersForPhase(phase) {
+			ids = append(ids, modifier.CardID())
+		}
+		return ids
+	}
+
+	expectOrder := func(phase PayoutPhase, expected []uint) {
+		t.Helper()
+		got := cardIDs(phase)
+		if len(got) != len(expected) {
+			t.Fatalf("Expected %d modifiers, got %d (%v)", len(expected), len(got), got)
+		}
+		for idx := range expected {
+			if got[idx] != expected[idx] {
+				t.Errorf("Modifier %d: expected card %d, got %d", idx, expected[idx], got[idx])
+			}
+		}
+	}
+
+	expectOrder(PhaseWin, []uint{
+		cards.DoubleDownCardID,
+		cards.GamblerCardID,
+		cards.HomeFieldAdvantageCardID,
+		cards.RoughingTheKickerCardID,
+		cards.HeismanCampaignCardID,
+		cards.EmotionalHedgeCardID,
+		cards.BetInsuranceCardID,
+	})
+	expectOrder(PhaseLoss, []uint{
+		cards.GetOutOfJailCardID,
+		cards.GamblerCardID,
+		cards.EmotionalHedgeCardID,
+		cards.BetInsuranceCardID,
+	})
+	expectOrder(PhasePush, nil)
+}
+
+func TestApplyPayoutModifiers(t *testing.T) {
+	invCols := []string{"id", "created_at", "updated_at", "deleted_at", "user_id", "guild_id", "card_id", "target_bet_id", "target_user_id", "bet_amount", "times_applied", "expires_at"}
+
+	t.Run("Double Down stacks before Roughing the Kicker", func(t *testing.T) {
+		db, mock, err := newMockDB()
+		if err != nil {
+			t.Fatalf("Failed to create mock DB: %v", err)
+		}
+		defer func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}()
+
+		user := models.User{ID: 1, GuildID: "guild1"}
+		createdAt := time.Now()
+
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.DoubleDownCardID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.GamblerCardID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.HomeFieldAdvantageCardID, 1).
+			WillReturnRows(sqlmock.NewRows(invCols))
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.RoughingTheKickerCardID, 1).
+			WillReturnRows(sqlmock.NewRows(invCols).
+				AddRow(1, createdAt, createdAt, nil, user.ID, user.GuildID, cards.RoughingTheKickerCardID, nil, nil, 0.0, 0, nil))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `user_inventories` SET `deleted_at`=").
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT \\* FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.HeismanCampaignCardID, 1).
+			WillReturnRows(sqlmock.NewRows(invCols))
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.BetInsuranceCardID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		var consumed []uint
+		ctx := &PayoutContext{
+			DB: db,
+			Consumer: func(db *gorm.DB, u models.User, cardID uint) error {
+				consumed = append(consumed, cardID)
+				return nil
+			},
+			User:  user,
+			Entry: models.BetEntry{Option: 1, Amount: 100},
+			Phase: PhaseWin,
+		}
+		state := &PayoutState{Amount: 100}
+
+		if err := ApplyPayoutModifiers(ctx, state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state.Amount != 170 {
+			t.Errorf("Expected payout 170.00 (2x then -15%%), got %.2f", state.Amount)
+		}
+		if len(consumed) != 1 || consumed[0] != cards.DoubleDownCardID {
+			t.Errorf("Expected only Double Down to be consumed, got %v", consumed)
+		}
+		expected := " (Double Down: 2x payout!) (Roughing the Kicker: -15% payout)"
+		if state.Annotation() != expected {
+			t.Errorf("Expected annotation %q, got %q", expected, state.Annotation())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("Get Out of Jail Free stops the loss pipeline", func(t *testing.T) {
+		db, mock, err := newMockDB()
+		if err != nil {
+			t.Fatalf("Failed to create mock DB: %v", err)
+		}
+		defer func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}()
+
+		user := models.User{ID: 1, GuildID: "guild1"}
+
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.GetOutOfJailCardID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		ctx := &PayoutContext{
+			DB:       db,
+			Consumer: func(db *gorm.DB, u models.User, cardID uint) error { return nil },
+			User:     user,
+			Entry:    models.BetEntry{Option: 2, Amount: 50},
+			Phase:    PhaseLoss,
+			Scored:   true,
+		}
+		state := &PayoutState{Amount: 50}
+
+		if err := ApplyPayoutModifiers(ctx, state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !state.Stopped {
+			t.Error("Expected Get Out of Jail Free to stop the pipeline")
+		}
+		if state.Refund != 50 {
+			t.Errorf("Expected full refund of 50.00, got %.2f", state.Refund)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("The Gambler does not roll during a dry run", func(t *testing.T) {
+		db, mock, err := newMockDB()
+		if err != nil {
+			t.Fatalf("Failed to create mock DB: %v", err)
+		}
+		defer func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}()
+
+		user := models.User{ID: 1, GuildID: "guild1"}
+
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user_inventories`").
+			WithArgs(user.ID, user.GuildID, cards.GamblerCardID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		gambler := PayoutModifiersForPhase(PhaseWin)[1]
+		ctx := &PayoutContext{
+			DB:       db,
+			Consumer: func(db *gorm.DB, u models.User, cardID uint) error { return nil },
+			User:     user,
+			Entry:    models.BetEntry{Option: 1, Amount: 100},
+			Phase:    PhaseWin,
+			DryRun:   true,
+		}
+		state := &PayoutState{Amount: 190}
+
+		annotation, err := gambler.Apply(ctx, state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state.Amount != 190 {
+			t.Errorf("Expected payout to stay at 190.00 during a dry run, got %.2f", state.Amount)
+		}
+		if annotation != "The Gambler: 50/50 to double" {
+			t.Errorf("Unexpected annotation %q", annotation)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+}
@@ -3,8 +3,10 @@ package cardService
 import (
 	"fmt"
 	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -36,11 +38,17 @@ func PlayCard(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 	}
 
 	var user models.User
-	result := db.Where(models.User{DiscordID: userID, GuildID: guildID}).Attrs(models.User{Points: guild.StartingPoints}).FirstOrCreate(&user)
+	result := db.FirstOrCreate(&user, models.User{DiscordID: userID, GuildID: guildID})
 	if result.Error != nil {
 		common.SendError(s, i, fmt.Errorf("error fetching user: %v", result.Error), db)
 		return
 	}
+	if result.RowsAffected == 1 {
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
+	}
 
 	showPlayableCardSelectMenu(s, i, db, userID, guildID, user.ID)
 }
@@ -58,6 +66,9 @@ func showPlayableCardSelectMenu(s *discordgo.Session, i *discordgo.InteractionCr
 		if item.ExpiresAt != nil && item.ExpiresAt.Before(now) {
 			continue
 		}
+		if item.TargetBetID != nil {
+			continue
+		}
 		inventoryMap[item.CardID]++
 	}
 
@@ -68,7 +79,8 @@ func showPlayableCardSelectMenu(s *discordgo.Session, i *discordgo.InteractionCr
 
 	for cardID, count := range inventoryMap {
 		card := GetCardByID(uint(cardID))
-		if card != nil && card.UserPlayable {
+		// An Uno Reverse held without a target was handed back by a voided bet.
+		if card != nil && (card.UserPlayable || card.ID == cards.UnoReverseCardID) {
 			playableCards = append(playableCards, struct {
 				Card  *models.Card
 				Count int
@@ -8,6 +8,7 @@ import (
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/historyService"
+	"perfectOddsBot/services/ledgerService"
 	"strings"
 	"time"
 
@@ -47,8 +48,10 @@ func ShowStore(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return
 	}
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
-		db.Save(&user)
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
 	}
 
 	username := common.GetUsernameFromUser(i.Member.User)
@@ -240,8 +243,10 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 		return result.Error
 	}
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
-		db.Save(&user)
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return err
+		}
 	}
 
 	username := common.GetUsernameFromUser(i.Member.User)
@@ -359,8 +364,12 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 
 	*guild = lockedGuild
 
-	user.Points -= storeCost
-	guild.Pool += storeCost
+	if err := ledgerService.Apply(tx, &user, -storeCost, ledgerService.Entry{Reason: ledgerService.ReasonCardPurchase, CardID: cardID}); err != nil {
+		tx.Rollback()
+		common.SendError(s, i, err, db)
+		return err
+	}
+	guild.Pool = common.RoundPoints(guild.Pool + storeCost)
 
 	if guild.PoolDrainUntil != nil {
 		if now.After(*guild.PoolDrainUntil) {
@@ -375,7 +384,7 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 		}
 	}
 
-	if err := tx.Save(&user).Error; err != nil {
+	if err := tx.Omit("points").Save(&user).Error; err != nil {
 		tx.Rollback()
 		common.SendError(s, i, err, db)
 		return err
@@ -437,16 +446,17 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 				return err
 			}
 
-			user.Points += cardResult.PointsDelta
-			if user.Points < 0 {
-				user.Points = 0
+			if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+				tx.Rollback()
+				common.SendError(s, i, err, db)
+				return err
 			}
-			guild.Pool += cardResult.PoolDelta
+			guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 			if guild.Pool < 0 {
 				guild.Pool = 0
 			}
 
-			if err := tx.Save(&user).Error; err != nil {
+			if err := tx.Omit("points").Save(&user).Error; err != nil {
 				tx.Rollback()
 				common.SendError(s, i, err, db)
 				return err
@@ -462,16 +472,17 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 		} else if cardResult.SelectionType == "bet" {
 			ShowBetSelectMenu(s, i, card.ID, card.Name, card.Description, userID, guildID, db)
 
-			user.Points += cardResult.PointsDelta
-			if user.Points < 0 {
-				user.Points = 0
+			if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+				tx.Rollback()
+				common.SendError(s, i, err, db)
+				return err
 			}
-			guild.Pool += cardResult.PoolDelta
+			guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 			if guild.Pool < 0 {
 				guild.Pool = 0
 			}
 
-			if err := tx.Save(&user).Error; err != nil {
+			if err := tx.Omit("points").Save(&user).Error; err != nil {
 				tx.Rollback()
 				common.SendError(s, i, err, db)
 				return err
@@ -490,16 +501,17 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 	if len(card.Options) > 0 {
 		ShowCardOptionsMenu(s, i, card.ID, card.Name, card.Description, userID, guildID, db, card.Options)
 
-		user.Points += cardResult.PointsDelta
-		if user.Points < 0 {
-			user.Points = 0
+		if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+			tx.Rollback()
+			common.SendError(s, i, err, db)
+			return err
 		}
-		guild.Pool += cardResult.PoolDelta
+		guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 		if guild.Pool < 0 {
 			guild.Pool = 0
 		}
 
-		if err := tx.Save(&user).Error; err != nil {
+		if err := tx.Omit("points").Save(&user).Error; err != nil {
 			tx.Rollback()
 			common.SendError(s, i, err, db)
 			return err
@@ -635,11 +647,12 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 		}
 	}
 
-	user.Points += cardResult.PointsDelta
-	if user.Points < 0 {
-		user.Points = 0
+	if _, err := ledgerService.ApplyFloored(tx, &user, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+		tx.Rollback()
+		common.SendError(s, i, err, db)
+		return err
 	}
-	guild.Pool += cardResult.PoolDelta
+	guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 	if guild.Pool < 0 {
 		guild.Pool = 0
 	}
@@ -776,11 +789,7 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 
 				targetPointsBefore := userToUpdate.Points
 
-				userToUpdate.Points += cardResult.TargetPointsDelta
-				if userToUpdate.Points < 0 {
-					userToUpdate.Points = 0
-				}
-				if err := tx.Save(&userToUpdate).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &userToUpdate, cardResult.TargetPointsDelta, ledgerService.Card(card.ID)); err != nil {
 					tx.Rollback()
 					common.SendError(s, i, err, db)
 					return err
@@ -798,7 +807,7 @@ func ProcessStorePurchase(s *discordgo.Session, i *discordgo.InteractionCreate,
 		}
 	}
 
-	if err := tx.Save(&user).Error; err != nil {
+	if err := tx.Omit("points").Save(&user).Error; err != nil {
 		tx.Rollback()
 		common.SendError(s, i, err, db)
 		return err
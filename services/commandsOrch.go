@@ -2,11 +2,14 @@ package services
 
 import (
 	"fmt"
+	"perfectOddsBot/models"
 	"perfectOddsBot/services/betService"
 	cardService "perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/extService"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/interactionService"
+	"perfectOddsBot/services/limitService"
+	"perfectOddsBot/services/teamService"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -27,6 +30,8 @@ func HandleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		ShowPoints(s, i, db)
 	case "my-stats":
 		ShowStats(s, i, db)
+	case "points-history":
+		ShowPointsHistory(s, i, db)
 	case "leaderboard":
 		ShowLeaderboard(s, i, db)
 	case "create-bet":
@@ -48,11 +53,19 @@ func HandleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate, db
 	case "set-starting-points":
 		guildService.SetStartingPoints(s, i, db)
 	case "list-cbb-games":
-		extService.ListCBBGames(s, i, db)
+		extService.ListESPNGames(s, i, db, extService.SportCBB)
 	case "create-cbb-bet":
-		betService.CreateCBBBetSelector(s, i, db)
-	case "subscribe-to-team":
-		interactionService.TeamSubscriptionMessage(s, i, db)
+		betService.CreateESPNBetSelector(s, i, db, extService.SportCBB)
+	case "list-nfl-games":
+		extService.ListESPNGames(s, i, db, extService.SportNFL)
+	case "create-nfl-bet":
+		betService.CreateESPNBetSelector(s, i, db, extService.SportNFL)
+	case "list-nba-games":
+		extService.ListESPNGames(s, i, db, extService.SportNBA)
+	case "create-nba-bet":
+		betService.CreateESPNBetSelector(s, i, db, extService.SportNBA)
+	case "manage-subscriptions":
+		interactionService.ManageSubscriptions(s, i, db)
 	case "create-parlay":
 		betService.CreateParlaySelector(s, i, db)
 	case "my-parlays":
@@ -69,6 +82,20 @@ func HandleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		cardService.ShowRecap(s, i, db)
 	case "toggle-card-drawing":
 		guildService.ToggleCardDrawing(s, i, db)
+	case "reverse-bet":
+		betService.ReverseBet(s, i, db)
+	case "set-line-alerts":
+		guildService.SetLineAlerts(s, i, db)
+	case "season-status":
+		ShowSeasonStatus(s, i, db)
+	case "set-timezone":
+		guildService.SetTimezone(s, i, db)
+	case "add-team-alias":
+		teamService.AddTeamAlias(s, i, db)
+	case "link-bet":
+		betService.LinkCustomBet(s, i, db)
+	case "bet-limits":
+		limitService.BetLimits(s, i, db)
 	}
 }
 
@@ -77,27 +104,39 @@ func ShowHelp(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB)
 		{"help", "Show this help message with all available commands", false, false},
 		{"my-points", "Show your current points", false, false},
 		{"my-stats", "Show your betting statistics", false, false},
+		{"points-history", "Show your most recent point changes and why they happened", false, false},
 		{"leaderboard", "Show the top users by points", false, false},
-		{"my-bets", "Show your current open, active bets", false, false},
-		{"my-parlays", "Show your active parlays", false, false},
-		{"create-parlay", "Create a parlay by combining multiple open bets", false, false},
+		{"my-bets", "Show your current open, active bets and cash out offers", false, false},
+		{"my-parlays", "Show your active parlays and cash out offers", false, false},
+		{"create-parlay", "Create a parlay, round robin or teaser by combining multiple open bets", false, false},
 		{"draw-card", "Draw a random card from the deck (Costs X points, adds to pool)", false, false},
 		{"store", "Purchase specific cards directly from the store", false, false},
 		{"my-inventory", "View the cards currently in your hand", false, false},
 		{"play-card", "Play a card from your inventory", false, false},
 		{"recap", "View your card play history (last X days)", false, false},
-		{"create-bet", "Create a new bet", true, false},
+		{"create-bet", "Create a new bet, optionally locking itself at a set time", true, false},
+		{"link-bet", "Link a custom bet to a game so it locks at kickoff and can resolve itself", true, false},
+		{"reverse-bet", "Undo a bet's payout and optionally re-resolve it", true, false},
 		{"give-points", "Give points to a user", true, false},
 		{"reset-points", "Reset all users' points to a default value", true, false},
 		{"set-betting-channel", "Set the current channel as the main channel for payouts", true, false},
 		{"set-points-per-message", "Set the amount of points users get per message", true, false},
 		{"set-starting-points", "Set the amount of points new users start with", true, false},
+		{"bet-limits", "View or change the most users can stake per bet, per side and across open bets", true, false},
 		{"list-cfb-games", "List this week's CFB games and their current lines", false, true},
 		{"list-cbb-games", "List the currently open CBB games", false, true},
 		{"create-cfb-bet", "Create a new College Football bet", false, true},
 		{"create-cbb-bet", "Create a new College Basketball bet", false, true},
-		{"subscribe-to-team", "Choose a College team to subscribe to all CFB & CBB events for", true, true},
+		{"list-nfl-games", "List the currently open NFL games", false, true},
+		{"create-nfl-bet", "Create a new NFL bet", false, true},
+		{"list-nba-games", "List the currently open NBA games", false, true},
+		{"create-nba-bet", "Create a new NBA bet", false, true},
+		{"manage-subscriptions", "Add or remove the team, conference and ranked-matchup subscriptions that auto-create CFB & CBB bets", true, true},
 		{"toggle-card-drawing", "Toggle card drawing on/off for this server", true, false},
+		{"set-line-alerts", "Set how far a game line must move before the bet channel is alerted", true, false},
+		{"season-status", "Show where each sport is in its season and which scheduled jobs are running", true, false},
+		{"set-timezone", "Set the server's timezone for game times, today's games and game-day posts", true, false},
+		{"add-team-alias", "Teach the bot another name for a team, used to match subscriptions and name teams in bets", true, false},
 	}
 
 	var fields []*discordgo.MessageEmbedField
@@ -162,6 +201,22 @@ func RegisterCommands(s *discordgo.Session) error {
 			Name:        "create-cbb-bet",
 			Description: "★ Create a new College Basketball bet (PREMIUM)",
 		},
+		{
+			Name:        "list-nfl-games",
+			Description: "★ List the currently open NFL games (PREMIUM)",
+		},
+		{
+			Name:        "create-nfl-bet",
+			Description: "★ Create a new NFL bet (PREMIUM)",
+		},
+		{
+			Name:        "list-nba-games",
+			Description: "★ List the currently open NBA games (PREMIUM)",
+		},
+		{
+			Name:        "create-nba-bet",
+			Description: "★ Create a new NBA bet (PREMIUM)",
+		},
 		{
 			Name:        "leaderboard",
 			Description: "Show the top users by points",
@@ -200,6 +255,161 @@ func RegisterCommands(s *discordgo.Session) error {
 					Type:        discordgo.ApplicationCommandOptionInteger,
 					Required:    false,
 				},
+				{
+					Name:        "option3",
+					Description: "Third betting option // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "odds3",
+					Description: "Odds for option 3 (e.g., +150 or -200) // *Optional: Default -110",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "option4",
+					Description: "Fourth betting option // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "odds4",
+					Description: "Odds for option 4 (e.g., +150 or -200) // *Optional: Default -110",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "option5",
+					Description: "Fifth betting option // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "odds5",
+					Description: "Odds for option 5 (e.g., +150 or -200) // *Optional: Default -110",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "option6",
+					Description: "Sixth betting option // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "odds6",
+					Description: "Odds for option 6 (e.g., +150 or -200) // *Optional: Default -110",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "lock",
+					Description: "When betting closes, like 7:30pm, 10/18 7pm or in 2h, in the server's timezone // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "bet-limits",
+			Description: "🛡 View or change the server's bet limits - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "max-stake",
+					Description: "Most points one bet or parlay can stake (0 = off) // *Optional",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "max-percent",
+					Description: "Most of a user's balance one bet or parlay can stake, as a percent (0 = off) // *Optional",
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Required:    false,
+				},
+				{
+					Name:        "max-exposure",
+					Description: "Most points a user can have riding on open bets and parlays (0 = off) // *Optional",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+				{
+					Name:        "max-side-stake",
+					Description: "Most points a user can have on one side of a bet (0 = off) // *Optional",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "link-bet",
+			Description: "🛡 Link a custom bet to a game so it locks at kickoff - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "bet-id",
+					Description: "ID of the custom bet to link",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    true,
+				},
+				{
+					Name:        "sport",
+					Description: "The game's sport",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "College Football", Value: models.SportCFB},
+						{Name: extService.SportCBB.FullName, Value: extService.SportCBB.Key},
+						{Name: extService.SportNFL.FullName, Value: extService.SportNFL.Key},
+						{Name: extService.SportNBA.FullName, Value: extService.SportNBA.Key},
+					},
+				},
+				{
+					Name:        "game-id",
+					Description: "The game's ID, as shown by the list games commands",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    true,
+				},
+				{
+					Name:        "outcome",
+					Description: "How the bet is resolved; graded outcomes need option 1 to be the outcome happening",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Manual - lock at kickoff, an admin resolves it", Value: models.LinkManual},
+						{Name: "Team wins - option 1 wins if the team wins", Value: models.LinkTeamWins},
+						{Name: "Total over - option 1 wins if the total goes over", Value: models.LinkTotalOver},
+					},
+				},
+				{
+					Name:        "team",
+					Description: "The team that has to win, for the team wins outcome // *Optional",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+				{
+					Name:        "total",
+					Description: "The points the total has to go over, for the total over outcome // *Optional",
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "reverse-bet",
+			Description: "🛡 Undo a resolved bet's payout and optionally re-resolve it - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "bet-id",
+					Description: "ID of the bet to reverse",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    true,
+				},
+				{
+					Name:        "re-resolve",
+					Description: "Option to resolve on after reversing, or 0 to push; game bets regrade from the score // *Optional",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -256,21 +466,44 @@ func RegisterCommands(s *discordgo.Session) error {
 			Name:        "my-stats",
 			Description: "Show your betting statistics",
 		},
+		{
+			Name:        "points-history",
+			Description: "Show your most recent point changes and why they happened",
+		},
 		{
 			Name:        "my-bets",
-			Description: "Show your current open, active bets",
+			Description: "Show your current open, active bets and cash out offers",
 		},
 		{
-			Name:        "subscribe-to-team",
-			Description: "★ Choose a College team to subscribe to all CFB & CBB events for (PREMIUM)",
+			Name:        "manage-subscriptions",
+			Description: "★ Manage team, conference and ranked-matchup bet subscriptions (PREMIUM)",
 		},
 		{
 			Name:        "create-parlay",
-			Description: "Create a parlay by combining multiple open bets",
+			Description: "Create a parlay, round robin or teaser by combining multiple open bets",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "type",
+					Description: "Kind of parlay // *Optional: Default Standard",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Standard - every leg must hit", Value: models.ParlayStandard},
+						{Name: "Round Robin - a parlay for every combination of your legs", Value: models.ParlayRoundRobin},
+						{Name: fmt.Sprintf("Teaser - spreads move %.0f points your way at reduced odds", betService.TeaserPoints), Value: models.ParlayTeaser},
+					},
+				},
+				{
+					Name:        "size",
+					Description: "Legs in each round robin parlay // *Optional: Default 2",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+			},
 		},
 		{
 			Name:        "my-parlays",
-			Description: "Show your active parlays",
+			Description: "Show your active parlays and cash out offers",
 		},
 		{
 			Name:        "draw-card",
@@ -304,6 +537,58 @@ func RegisterCommands(s *discordgo.Session) error {
 			Name:        "toggle-card-drawing",
 			Description: "🛡 Toggle card drawing on/off for this server - ADMIN ONLY",
 		},
+		{
+			Name:        "set-line-alerts",
+			Description: "🛡 Set how far a game line must move before the bet channel is alerted - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "points",
+					Description: "Spread or total movement in points that triggers an alert, 0 to turn off (default 1.5)",
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Required:    true,
+				},
+				{
+					Name:        "odds",
+					Description: "Moneyline movement in cents that triggers an alert, 0 to turn off (default 25)",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "season-status",
+			Description: "🛡 Show where each sport is in its season and which scheduled jobs are running - ADMIN ONLY",
+		},
+		{
+			Name:        "set-timezone",
+			Description: "🛡 Set the server's timezone for game times and game-day posts - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "timezone",
+					Description: "IANA timezone name, such as America/Chicago or Europe/London (default America/New_York)",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "add-team-alias",
+			Description: "🛡 Teach the bot another name for a team - ADMIN ONLY",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "team",
+					Description: "The team, by any name the bot already knows, such as Ohio State",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "alias",
+					Description: "Another name for the team, such as tOSU",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			},
+		},
 	}
 
 	// map of commands to keep
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"math/big"
 	"net/http"
 	"os"
 	"perfectOddsBot/models"
 	"perfectOddsBot/models/external"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -100,53 +104,175 @@ func FormatOdds(odds float64) string {
 	return response
 }
 
+// PointsScale is the number of minor units in a point. Balances, the pool and
+// payouts are stored as DECIMAL(20,2) and all points math rounds to this scale.
+const PointsScale = 100
+
+// ToMinorUnits converts points to integer minor units, rounding half away from
+// zero.
+func ToMinorUnits(points float64) int64 {
+	return int64(math.Round(points * PointsScale))
+}
+
+// FromMinorUnits converts integer minor units back to points.
+func FromMinorUnits(units int64) float64 {
+	return float64(units) / PointsScale
+}
+
+// RoundPoints rounds points to the nearest minor unit.
+func RoundPoints(points float64) float64 {
+	return FromMinorUnits(ToMinorUnits(points))
+}
+
+// SplitPoints splits points n ways. Each share is rounded down to a minor unit,
+// so the shares never add up to more than points.
+func SplitPoints(points float64, n int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return FromMinorUnits(ToMinorUnits(points) / int64(n))
+}
+
+// divRound divides two non-negative integers, rounding half up.
+func divRound(n int64, d int64) int64 {
+	return (2*n + d) / (2 * d)
+}
+
+// CalculatePayout returns the stake plus winnings for an amount on an option.
+// Winnings are worked out in minor units, so -110 on 10 points pays 19.09
+// rather than the 19 integer division used to give.
 func CalculatePayout(amount int, option int, bet models.Bet) float64 {
-	var odds int
-	if option == 1 {
-		odds = bet.Odds1
-	} else {
-		odds = bet.Odds2
+	return CalculatePayoutAtOdds(amount, GetOddsFromBet(bet, option))
+}
+
+// CalculateEntryPayout returns the payout for an entry winning on option. An
+// entry pays at the odds it was placed at, since game bet prices move after
+// entries are taken; entries from before odds were recorded, and payouts on a
+// different option, use the bet's current odds.
+func CalculateEntryPayout(entry models.BetEntry, option int, bet models.Bet) float64 {
+	if entry.Odds != nil && *entry.Odds != 0 && option == entry.Option {
+		return CalculatePayoutAtOdds(entry.Amount, *entry.Odds)
 	}
+	return CalculatePayout(entry.Amount, option, bet)
+}
+
+// CalculatePayoutAtOdds returns the stake plus winnings for an amount at
+// American odds.
+func CalculatePayoutAtOdds(amount int, americanOdds int) float64 {
+	odds := int64(americanOdds)
+	stake := int64(amount) * PointsScale
 
 	if odds > 0 {
-		return float64(amount + (amount*odds)/100)
+		return FromMinorUnits(stake + divRound(stake*odds, 100))
 	}
 
-	return float64(amount + (amount*100)/-odds)
+	return FromMinorUnits(stake + divRound(stake*100, -odds))
 }
 
 func CalculateSimplePayout(amount float64) float64 {
-	return amount * 2.0
+	return RoundPoints(amount * 2.0)
 }
 
+// CalculateParlayOddsMultiplier returns the decimal odds of a parlay. The legs
+// are multiplied as exact fractions and rounded once, so the order of the legs
+// can't change the payout.
 func CalculateParlayOddsMultiplier(oddsList []int) float64 {
-	if len(oddsList) == 0 {
-		return 1.0
-	}
-
-	multiplier := 1.0
+	multiplier := big.NewRat(1, 1)
 	for _, odds := range oddsList {
 		if odds > 0 {
-			multiplier *= (float64(odds) / 100.0) + 1.0
-		} else {
-			multiplier *= (100.0 / float64(-odds)) + 1.0
+			multiplier.Mul(multiplier, big.NewRat(int64(odds)+100, 100))
+		} else if odds < 0 {
+			multiplier.Mul(multiplier, big.NewRat(100-int64(odds), -int64(odds)))
 		}
 	}
 
-	return multiplier
+	decimal, _ := multiplier.Float64()
+	return decimal
 }
 
+// CalculateParlayPayout returns the stake times the parlay multiplier, rounded to
+// minor units the same way as a straight bet.
 func CalculateParlayPayout(amount int, oddsMultiplier float64) float64 {
-	return float64(amount) * oddsMultiplier
+	return RoundPoints(float64(amount) * oddsMultiplier)
+}
+
+// GetParlayEntryOdds returns the odds a parlay leg was placed at. Legs from
+// before odds were recorded use the bet's current odds.
+func GetParlayEntryOdds(entry models.ParlayEntry) int {
+	if entry.Odds != nil && *entry.Odds != 0 {
+		return *entry.Odds
+	}
+	return GetOddsFromBet(entry.Bet, entry.SelectedOption)
 }
 
 func GetOddsFromBet(bet models.Bet, option int) int {
+	for _, betOption := range bet.Options {
+		if betOption.OptionNumber == option {
+			return betOption.Odds
+		}
+	}
 	if option == 1 {
 		return bet.Odds1
 	}
 	return bet.Odds2
 }
 
+// GetBetOptions returns the options of a bet ordered by option number. Game bets and
+// bets created before multi-option support only have Option1/Option2, so those are
+// returned as options 1 and 2. bet.Options must be preloaded for custom bets.
+func GetBetOptions(bet models.Bet) []models.BetOption {
+	if len(bet.Options) > 0 {
+		options := make([]models.BetOption, len(bet.Options))
+		copy(options, bet.Options)
+		sort.Slice(options, func(a, b int) bool {
+			return options[a].OptionNumber < options[b].OptionNumber
+		})
+		return options
+	}
+
+	return []models.BetOption{
+		{BetID: bet.ID, OptionNumber: 1, Name: bet.Option1, Odds: bet.Odds1, BetCount: bet.BetsOption1},
+		{BetID: bet.ID, OptionNumber: 2, Name: bet.Option2, Odds: bet.Odds2, BetCount: bet.BetsOption2},
+	}
+}
+
+func GetOptionName(bet models.Bet, option int) string {
+	for _, betOption := range bet.Options {
+		if betOption.OptionNumber == option {
+			return betOption.Name
+		}
+	}
+	if option == 1 {
+		return bet.Option1
+	}
+	return bet.Option2
+}
+
+func IsValidBetOption(bet models.Bet, option int) bool {
+	for _, betOption := range GetBetOptions(bet) {
+		if betOption.OptionNumber == option {
+			return true
+		}
+	}
+	return false
+}
+
+// BetOpen reports whether a bet still takes entries. A bet past its lock time
+// is closed even if the lock job hasn't run yet.
+func BetOpen(bet models.Bet, now time.Time) bool {
+	return bet.Active && !bet.Paid && (bet.LockAt == nil || now.Before(*bet.LockAt))
+}
+
+func GetOptionEmoji(option int) string {
+	if option >= 1 && option <= 9 {
+		return fmt.Sprintf("%d\uFE0F\u20E3", option)
+	}
+	if option == 10 {
+		return "🔟"
+	}
+	return fmt.Sprintf("#%d", option)
+}
+
 func GetUsernameFromUser(user *discordgo.User) string {
 	if user == nil {
 		return "Unknown User"
@@ -164,7 +290,7 @@ func GetUsernameFromUser(user *discordgo.User) string {
 func UpdateUserUsername(db *gorm.DB, user *models.User, username string) {
 	if user.Username == nil || *user.Username != username {
 		user.Username = &username
-		db.Save(user)
+		db.Model(user).Update("username", username)
 	}
 }
 
@@ -199,6 +325,12 @@ func GetUsernameWithDB(db *gorm.DB, s *discordgo.Session, guildId string, userId
 }
 
 func CFBDWrapper(requestUrl string) (*http.Response, error) {
+	return CFBDWrapperWithin(requestUrl, AnyAge)
+}
+
+// CFBDWrapperWithin is CFBDWrapper, but skips cached responses older than
+// maxAge.
+func CFBDWrapperWithin(requestUrl string, maxAge time.Duration) (*http.Response, error) {
 	var cfbdKey string
 	getEnv, ok := os.LookupEnv("ENV")
 	if ok == false {
@@ -217,42 +349,19 @@ func CFBDWrapper(requestUrl string) (*http.Response, error) {
 		}
 	}
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", cfbdKey))
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		resp.Body.Close()
-		return nil, err
-	}
-	return resp, nil
+	header := http.Header{}
+	header.Add("Authorization", fmt.Sprintf("Bearer %s", cfbdKey))
+	return cfbdClient.getWithin(requestUrl, header, maxAge)
 }
 
 func ESPNWrapper(requestUrl string) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
+	return ESPNWrapperWithin(requestUrl, AnyAge)
+}
 
-	if resp.StatusCode != 200 {
-		resp.Body.Close()
-		return nil, err
-	}
-	return resp, nil
+// ESPNWrapperWithin is ESPNWrapper, but skips cached responses older than
+// maxAge.
+func ESPNWrapperWithin(requestUrl string, maxAge time.Duration) (*http.Response, error) {
+	return espnClient.getWithin(requestUrl, nil, maxAge)
 }
 
 func PFWrapper(requestUrl string) (*http.Response, error) {
@@ -274,24 +383,9 @@ func PFWrapper(requestUrl string) (*http.Response, error) {
 		}
 	}
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("X-Api-Key", fmt.Sprintf("%s", pfKey))
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		fmt.Println(resp.StatusCode)
-		resp.Body.Close()
-		return nil, err
-	}
-	return resp, nil
+	header := http.Header{}
+	header.Add("X-Api-Key", pfKey)
+	return pfClient.get(requestUrl, header)
 }
 
 func Contains[T comparable](s []T, e T) bool {
@@ -377,3 +471,35 @@ func CalculateBetEntryWin(option int, scoreDiff int, spread float64) bool {
 		return float64(-scoreDiff) > spread
 	}
 }
+
+// CalculateTotalEntryWin determines if an over/under bet entry wins based on the combined score.
+// Parameters:
+//   - option: 1 for the over, 2 for the under
+//   - totalScore: homeScore + awayScore
+//   - total: the over/under line the entry was placed at
+//
+// Returns true if the bet entry wins, false otherwise.
+func CalculateTotalEntryWin(option int, totalScore int, total float64) bool {
+	if option == 1 {
+		return float64(totalScore) > total
+	}
+	return float64(totalScore) < total
+}
+
+// CalculateBetEntryPush reports whether a spread bet lands exactly on the number.
+// The push is the same for both sides, so no option is needed.
+func CalculateBetEntryPush(scoreDiff int, spread float64) bool {
+	return float64(scoreDiff)+spread == 0
+}
+
+// CalculateTotalEntryPush reports whether the combined score lands exactly on the total.
+func CalculateTotalEntryPush(totalScore int, total float64) bool {
+	return float64(totalScore) == total
+}
+
+func FormatTotalOption(option int, total float64) string {
+	if option == 1 {
+		return fmt.Sprintf("Over %s", strings.TrimPrefix(FormatOdds(total), "+"))
+	}
+	return fmt.Sprintf("Under %s", strings.TrimPrefix(FormatOdds(total), "+"))
+}
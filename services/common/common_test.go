@@ -0,0 +1,76 @@
+package common
+
+import (
+	"perfectOddsBot/models"
+	"testing"
+)
+
+func TestCalculateEntryPayout(t *testing.T) {
+	locked := 150
+	bet := models.Bet{Option1: "Alabama", Option2: "Auburn", Odds1: -200, Odds2: 170}
+
+	tests := []struct {
+		name   string
+		entry  models.BetEntry
+		option int
+		payout float64
+	}{
+		{"pays at the locked odds after the line moves", models.BetEntry{Option: 2, Amount: 100, Odds: &locked}, 2, 250},
+		{"entry placed before odds were recorded", models.BetEntry{Option: 2, Amount: 100}, 2, 270},
+		{"payout on another option uses that option's odds", models.BetEntry{Option: 2, Amount: 100, Odds: &locked}, 1, 150},
+		{"locked favourite odds in minor units", models.BetEntry{Option: 1, Amount: 10, Odds: intPtr(-110)}, 1, 19.09},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if payout := CalculateEntryPayout(tt.entry, tt.option, bet); payout != tt.payout {
+				t.Errorf("payout = %.2f, want %.2f", payout, tt.payout)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestCalculateParlayPayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		odds   []int
+		amount int
+		payout float64
+	}{
+		{"two -110 legs", []int{-110, -110}, 100, 364.46},
+		{"mixed legs", []int{150, -200, 120}, 10, 82.5},
+		{"mixed legs in another order", []int{120, 150, -200}, 10, 82.5},
+		{"no legs", nil, 25, 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multiplier := CalculateParlayOddsMultiplier(tt.odds)
+			if payout := CalculateParlayPayout(tt.amount, multiplier); payout != tt.payout {
+				t.Errorf("payout = %.2f, want %.2f", payout, tt.payout)
+			}
+		})
+	}
+}
+
+func TestSplitPoints(t *testing.T) {
+	tests := []struct {
+		points float64
+		n      int
+		share  float64
+	}{
+		{100.01, 2, 50},
+		{10, 3, 3.33},
+		{10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if share := SplitPoints(tt.points, tt.n); share != tt.share {
+			t.Errorf("SplitPoints(%.2f, %d) = %.2f, want %.2f", tt.points, tt.n, share, tt.share)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HTTPStatusError is returned by the API wrappers when a provider answers with
+// anything other than 200 once retries are used up.
+type HTTPStatusError struct {
+	Provider   string
+	URL        string
+	StatusCode int
+	Body       string
+	// RetryAfter is how long a 429 asked us to wait, if it said.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s request to %s failed with status %d: %s", e.Provider, e.URL, e.StatusCode, e.Body)
+}
+
+// apiClient is the HTTP client shared by every request to one provider. It
+// spaces requests out to stay under the provider's rate limit, retries network
+// errors, 429s and 5xx responses with backoff, and caches 200 responses for a
+// while so scheduler ticks and paginated commands don't spend quota on data
+// that hasn't changed.
+type apiClient struct {
+	name        string
+	client      *http.Client
+	minInterval time.Duration
+	cacheTTL    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+
+	limitMu sync.Mutex
+	nextAt  time.Time
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	body      []byte
+	header    http.Header
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// AnyAge accepts any cached response that hasn't expired. Pass a shorter max
+// age to the API wrappers when a caller needs fresher data than the cache
+// holds, such as live scores.
+const AnyAge = time.Duration(math.MaxInt64)
+
+const (
+	apiTimeout          = 15 * time.Second
+	apiMaxAttempts      = 3
+	apiBackoff          = 500 * time.Millisecond
+	apiMaxBodyInError   = 200
+	apiMaxRetryAfterSec = 30
+	apiCacheSweepSize   = 256
+)
+
+func newAPIClient(name string, requestsPerSecond float64, cacheTTL time.Duration) *apiClient {
+	return &apiClient{
+		name:        name,
+		client:      &http.Client{Timeout: apiTimeout},
+		minInterval: time.Duration(float64(time.Second) / requestsPerSecond),
+		cacheTTL:    cacheTTL,
+		maxAttempts: apiMaxAttempts,
+		backoff:     apiBackoff,
+		cache:       make(map[string]cachedResponse),
+	}
+}
+
+var (
+	cfbdClient = newAPIClient("CFBD", 2, 5*time.Minute)
+	espnClient = newAPIClient("ESPN", 5, time.Minute)
+	pfClient   = newAPIClient("Perfect Fall", 5, 10*time.Minute)
+)
+
+// get returns the response for a GET of requestUrl, from the cache when it is
+// fresh. The response body is already read, so closing it is optional.
+func (c *apiClient) get(requestUrl string, header http.Header) (*http.Response, error) {
+	return c.getWithin(requestUrl, header, AnyAge)
+}
+
+// getWithin is get, but only uses a cached response fetched within maxAge. A
+// maxAge of zero always goes to the provider. The fresh response is cached for
+// everyone else either way.
+func (c *apiClient) getWithin(requestUrl string, header http.Header, maxAge time.Duration) (*http.Response, error) {
+	if cached, ok := c.cached(requestUrl, maxAge); ok {
+		return cached.response(), nil
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(c.retryDelay(attempt, lastErr))
+		}
+		c.wait()
+
+		entry, err := c.do(requestUrl, header)
+		if err == nil {
+			c.store(requestUrl, entry)
+			return entry.response(), nil
+		}
+		lastErr = err
+		if !retryable(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *apiClient) do(requestUrl string, header http.Header) (cachedResponse, error) {
+	req, err := http.NewRequest("GET", requestUrl, nil)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		snippet := string(body)
+		if len(snippet) > apiMaxBodyInError {
+			snippet = snippet[:apiMaxBodyInError]
+		}
+		return cachedResponse{}, &HTTPStatusError{
+			Provider:   c.name,
+			URL:        requestUrl,
+			StatusCode: resp.StatusCode,
+			Body:       snippet,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return cachedResponse{body: body, header: resp.Header}, nil
+}
+
+func retryable(err error) bool {
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+}
+
+func (c *apiClient) retryDelay(attempt int, lastErr error) time.Duration {
+	var statusErr *HTTPStatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter
+	}
+	return c.backoff * time.Duration(1<<(attempt-2))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if seconds > apiMaxRetryAfterSec {
+		seconds = apiMaxRetryAfterSec
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// wait blocks until the next request is allowed under the rate limit.
+func (c *apiClient) wait() {
+	c.limitMu.Lock()
+	now := time.Now()
+	startAt := c.nextAt
+	if startAt.Before(now) {
+		startAt = now
+	}
+	c.nextAt = startAt.Add(c.minInterval)
+	c.limitMu.Unlock()
+
+	time.Sleep(time.Until(startAt))
+}
+
+func (c *apiClient) cached(requestUrl string, maxAge time.Duration) (cachedResponse, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	entry, ok := c.cache[requestUrl]
+	if !ok {
+		return cachedResponse{}, false
+	}
+	now := time.Now()
+	if now.After(entry.expiresAt) {
+		delete(c.cache, requestUrl)
+		return cachedResponse{}, false
+	}
+	if now.Sub(entry.fetchedAt) >= maxAge {
+		return cachedResponse{}, false
+	}
+	return entry, true
+}
+
+func (c *apiClient) store(requestUrl string, entry cachedResponse) {
+	if c.cacheTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	entry.fetchedAt = now
+	entry.expiresAt = now.Add(c.cacheTTL)
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if len(c.cache) >= apiCacheSweepSize {
+		for key, existing := range c.cache {
+			if now.After(existing.expiresAt) {
+				delete(c.cache, key)
+			}
+		}
+	}
+	c.cache[requestUrl] = entry
+}
+
+func (entry cachedResponse) response() *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     entry.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(entry.body)),
+	}
+}
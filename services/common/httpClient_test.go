@@ -0,0 +1,147 @@
+package common
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestAPIClient(cacheTTL time.Duration) *apiClient {
+	c := newAPIClient("Test", 1000, cacheTTL)
+	c.backoff = time.Millisecond
+	return c
+}
+
+func TestAPIClientGet(t *testing.T) {
+	t.Run("retries server errors then succeeds", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{"ok":true}`))
+		}))
+		defer server.Close()
+
+		resp, err := newTestAPIClient(0).get(server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != `{"ok":true}` {
+			t.Errorf("unexpected body: %s", body)
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 requests, got %d", calls)
+		}
+	})
+
+	t.Run("returns a typed error without retrying client errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("bad token"))
+		}))
+		defer server.Close()
+
+		resp, err := newTestAPIClient(0).get(server.URL, nil)
+		if resp != nil {
+			t.Errorf("expected no response")
+		}
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("expected an HTTPStatusError, got %v", err)
+		}
+		if statusErr.StatusCode != http.StatusUnauthorized || statusErr.Body != "bad token" {
+			t.Errorf("unexpected error: %+v", statusErr)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 request, got %d", calls)
+		}
+	})
+
+	t.Run("serves repeat requests from the cache", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("expected the auth header to be sent")
+			}
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		c := newTestAPIClient(time.Minute)
+		header := http.Header{}
+		header.Add("Authorization", "Bearer token")
+		for call := 0; call < 3; call++ {
+			resp, err := c.get(server.URL, header)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != `[]` {
+				t.Errorf("unexpected body: %s", body)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 request, got %d", calls)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("expected 2s, got %v", got)
+	}
+	if got := parseRetryAfter("600"); got != apiMaxRetryAfterSec*time.Second {
+		t.Errorf("expected the cap, got %v", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+}
+
+func TestAPIClientGetWithin(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(time.Minute)
+	if _, err := c.get(server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.getWithin(server.URL, nil, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a fresh cached response to be used, got %d requests", calls)
+	}
+
+	if _, err := c.getWithin(server.URL, nil, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected a max age of zero to skip the cache, got %d requests", calls)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := c.getWithin(server.URL, nil, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.get(server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected a stale response to be fetched again and then cached, got %d requests", calls)
+	}
+}
@@ -0,0 +1,154 @@
+package common
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the timezone of guilds that haven't set one.
+const DefaultTimezone = "America/New_York"
+
+// GuildLocation returns the guild's timezone, or DefaultTimezone when it has
+// none or it can't be loaded.
+func GuildLocation(guild models.Guild) *time.Location {
+	if guild.Timezone != "" {
+		if loc, err := time.LoadLocation(guild.Timezone); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FormatGameTime formats a game time in loc, for text Discord won't render
+// timestamps in, such as bet descriptions and embed titles.
+func FormatGameTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon 03:04 pm MST")
+}
+
+// DiscordTimestamp renders t with Discord's timestamp markup, which each member
+// sees in their own timezone. Style is one of Discord's format letters, such as
+// "F" for the full date and time or "R" for relative.
+func DiscordTimestamp(t time.Time, style string) string {
+	return fmt.Sprintf("<t:%d:%s>", t.Unix(), style)
+}
+
+// GameBetDescription is a game bet's description followed by its start time as
+// a Discord timestamp.
+func GameBetDescription(bet models.Bet) string {
+	if bet.GameStartDate == nil {
+		return bet.Description
+	}
+	return fmt.Sprintf("%s\n🕒 %s", bet.Description, DiscordTimestamp(*bet.GameStartDate, "F"))
+}
+
+// CustomBetDescription is a custom bet's description followed by when it locks,
+// if it has a lock time.
+func CustomBetDescription(bet models.Bet) string {
+	if bet.LockAt == nil {
+		return bet.Description
+	}
+	return fmt.Sprintf("%s\n🔒 Locks %s", bet.Description, DiscordTimestamp(*bet.LockAt, "F"))
+}
+
+// StartOfDay returns midnight at the start of t's day in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// lockTimeLayouts are the absolute lock times ParseLockTime accepts, read after
+// lowercasing and joining "pm" to the time. Layouts without a year or date fall
+// in the current year or day.
+var lockTimeLayouts = []struct {
+	layout  string
+	hasYear bool
+	hasDate bool
+}{
+	{"2006-01-02 15:04", true, true},
+	{"2006-01-02 3:04pm", true, true},
+	{"2006-01-02 3pm", true, true},
+	{"1/2 15:04", false, true},
+	{"1/2 3:04pm", false, true},
+	{"1/2 3pm", false, true},
+	{"15:04", false, false},
+	{"3:04pm", false, false},
+	{"3pm", false, false},
+}
+
+// ParseLockTime reads when a bet should lock: either relative to now, like
+// "in 2h", "in 90m" or "in 1d", or an absolute time in loc, like "7:30pm",
+// "10/18 7pm" or "2026-10-18 19:30". The time must be in the future.
+func ParseLockTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	if value == "" {
+		return time.Time{}, fmt.Errorf("a lock time is required")
+	}
+
+	var lockAt time.Time
+	if relative, found := strings.CutPrefix(value, "in "); found {
+		duration, err := parseLockDuration(strings.ReplaceAll(relative, " ", ""))
+		if err != nil {
+			return time.Time{}, err
+		}
+		lockAt = now.Add(duration)
+	} else {
+		value = strings.NewReplacer(" am", "am", " pm", "pm").Replace(value)
+		local := now.In(loc)
+		parsed := false
+		for _, layout := range lockTimeLayouts {
+			t, err := time.ParseInLocation(layout.layout, value, loc)
+			if err != nil {
+				continue
+			}
+			year, month, day := t.Date()
+			if !layout.hasYear {
+				year = local.Year()
+			}
+			if !layout.hasDate {
+				month, day = local.Month(), local.Day()
+			}
+			lockAt = time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, loc)
+			parsed = true
+			break
+		}
+		if !parsed {
+			return time.Time{}, fmt.Errorf("couldn't read the lock time %q; use a time like 7:30pm, 10/18 7pm or in 2h", input)
+		}
+	}
+
+	if !lockAt.After(now) {
+		return time.Time{}, fmt.Errorf("the lock time %q has already passed", input)
+	}
+	return lockAt, nil
+}
+
+// parseLockDuration reads a duration like "2h", "1h30m" or "1d". Days aren't
+// understood by time.ParseDuration, so they are read first.
+func parseLockDuration(value string) (time.Duration, error) {
+	var days time.Duration
+	if idx := strings.Index(value, "d"); idx > 0 {
+		n, err := strconv.Atoi(value[:idx])
+		if err != nil {
+			return 0, fmt.Errorf("couldn't read the lock time %q", value)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		value = value[idx+1:]
+	}
+	if value == "" {
+		return days, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't read the lock time %q; use a duration like 2h or 90m", value)
+	}
+	return days + duration, nil
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLockTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	now := time.Date(2026, 10, 18, 14, 0, 0, 0, loc)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"in 2h", now.Add(2 * time.Hour)},
+		{"in 90m", now.Add(90 * time.Minute)},
+		{"In 1h 30m", now.Add(90 * time.Minute)},
+		{"in 1d", now.Add(24 * time.Hour)},
+		{"in 1d2h", now.Add(26 * time.Hour)},
+		{"7:30pm", time.Date(2026, 10, 18, 19, 30, 0, 0, loc)},
+		{"7 PM", time.Date(2026, 10, 18, 19, 0, 0, 0, loc)},
+		{"19:30", time.Date(2026, 10, 18, 19, 30, 0, 0, loc)},
+		{"10/25 7pm", time.Date(2026, 10, 25, 19, 0, 0, 0, loc)},
+		{"2026-11-01 12:00", time.Date(2026, 11, 1, 12, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLockTime(tt.input, now, loc)
+			if err != nil {
+				t.Fatalf("ParseLockTime(%q) returned error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseLockTime(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "tomorrow", "in soon", "9am", "10/1 7pm", "in -2h"} {
+		if _, err := ParseLockTime(input, now, loc); err == nil {
+			t.Errorf("expected ParseLockTime(%q) to fail", input)
+		}
+	}
+}
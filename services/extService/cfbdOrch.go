@@ -1,7 +1,6 @@
 package extService
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -9,6 +8,7 @@ import (
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"runtime/debug"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -23,8 +23,6 @@ func ListCFBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 		}
 	}()
 
-	cfbUrl := "https://api.collegefootballdata.com/lines?"
-	pfWeekUrl := "https://api.perfectfall.com/week-season"
 	conferenceList := []string{"Big Ten", "ACC", "SEC"}
 
 	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
@@ -37,43 +35,13 @@ func ListCFBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 		return
 	}
 
-	weekResp, err := common.PFWrapper(pfWeekUrl)
+	calendar, err := GetCalendar()
 	if err != nil {
 		common.SendError(s, i, err, db)
 		return
 	}
-	defer weekResp.Body.Close()
 
-	var calendar external.CalendarData
-	err = json.NewDecoder(weekResp.Body).Decode(&calendar)
-	if err != nil {
-		common.SendError(s, i, err, db)
-		return
-	}
-
-	if calendar.Week == nil {
-		common.SendError(s, i, fmt.Errorf("API did not return week data"), db)
-		return
-	}
-	if calendar.Season == nil {
-		common.SendError(s, i, fmt.Errorf("API did not return season data"), db)
-		return
-	}
-
-	var weekNum = calendar.Week.WeekNum
-	if weekNum > calendar.MaxRegWeek {
-		weekNum = 1
-	}
-	linesUrl := fmt.Sprintf("%syear=%d&seasonType=%s&week=%d", cfbUrl, calendar.Season.Year, calendar.Week.WeekType, weekNum)
-	linesResp, err := common.CFBDWrapper(linesUrl)
-	if err != nil {
-		common.SendError(s, i, err, db)
-		return
-	}
-	defer linesResp.Body.Close()
-
-	var bettingLines []external.CFBD_BettingLines
-	err = json.NewDecoder(linesResp.Body).Decode(&bettingLines)
+	bettingLines, err := providers.Football.Lines(calendar, common.AnyAge)
 	if err != nil {
 		common.SendError(s, i, err, db)
 		return
@@ -94,7 +62,7 @@ func ListCFBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 					if lineErr != nil {
 						continue
 					} else {
-						lineText += fmt.Sprintf(" (%d):  %s \n", bet.ID, line.FormattedSpread)
+						lineText += fmt.Sprintf(" (%d) %s:  %s \n", bet.ID, common.DiscordTimestamp(bet.StartDate, "f"), line.FormattedSpread)
 					}
 				}
 
@@ -116,69 +84,104 @@ func ListCFBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 	}
 }
 
-func GetCFBGames() (_ []external.CFBD_BettingLines, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Println("Recovered in GetCFBGames", r)
-			debug.PrintStack()
-			err = fmt.Errorf("panic recovered in GetCFBGames: %v", r)
-		}
-	}()
-
-	cfbUrl := "https://api.collegefootballdata.com/lines?"
-	pfWeekUrl := "https://api.perfectfall.com/week-season"
+// CFBDProvider reads college football lines and scores from the CFBD API.
+type CFBDProvider struct{}
 
-	weekResp, err := common.PFWrapper(pfWeekUrl)
-	if err != nil {
-		return []external.CFBD_BettingLines{}, err
-	}
-	if weekResp == nil {
-		return []external.CFBD_BettingLines{}, fmt.Errorf("GetCFBGames: weekResp is empty")
+func (CFBDProvider) Lines(calendar external.CalendarData, maxAge time.Duration) ([]external.CFBD_BettingLines, error) {
+	weekNum := calendar.Week.WeekNum
+	if weekNum > calendar.MaxRegWeek {
+		weekNum = 1
 	}
-	if weekResp.Body == nil {
-		return []external.CFBD_BettingLines{}, fmt.Errorf("GetCFBGames: weekResp.Body is empty")
+	linesUrl := fmt.Sprintf("https://api.collegefootballdata.com/lines?year=%d&seasonType=%s&week=%d", calendar.Season.Year, calendar.Week.WeekType, weekNum)
+
+	var bettingLines []external.CFBD_BettingLines
+	resp, err := common.CFBDWrapperWithin(linesUrl, maxAge)
+	if err := decodeResponse(resp, err, "CFB Lines", &bettingLines); err != nil {
+		return nil, err
 	}
-	defer weekResp.Body.Close()
+	return bettingLines, nil
+}
 
-	var calendar external.CalendarData
-	err = json.NewDecoder(weekResp.Body).Decode(&calendar)
-	if err != nil {
-		return []external.CFBD_BettingLines{}, err
+func (CFBDProvider) Scoreboard(maxAge time.Duration) (external.CFBD_Scoreboard, error) {
+	var scoreboard external.CFBD_Scoreboard
+	resp, err := common.CFBDWrapperWithin("https://api.collegefootballdata.com/scoreboard?classification=fbs", maxAge)
+	if err := decodeResponse(resp, err, "CFB Scoreboard", &scoreboard); err != nil {
+		return external.CFBD_Scoreboard{}, err
 	}
+	return scoreboard, nil
+}
 
-	if calendar.Week == nil {
-		return []external.CFBD_BettingLines{}, fmt.Errorf("GetCFBGames: calendar.Week is nil - API did not return week data")
+func (CFBDProvider) Rankings(calendar external.CalendarData) (external.CFBD_Rankings, error) {
+	weekNum := calendar.Week.WeekNum
+	if weekNum > calendar.MaxRegWeek {
+		weekNum = calendar.MaxRegWeek
 	}
-	if calendar.Season == nil {
-		return []external.CFBD_BettingLines{}, fmt.Errorf("GetCFBGames: calendar.Season is nil - API did not return season data")
+	rankingsUrl := fmt.Sprintf("https://api.collegefootballdata.com/rankings?year=%d&seasonType=regular&week=%d", calendar.Season.Year, weekNum)
+
+	var rankings external.CFBD_Rankings
+	resp, err := common.CFBDWrapper(rankingsUrl)
+	if err := decodeResponse(resp, err, "CFB Rankings", &rankings); err != nil {
+		return nil, err
 	}
+	return rankings, nil
+}
 
-	var weekNum = calendar.Week.WeekNum
-	if weekNum > calendar.MaxRegWeek {
-		weekNum = 1
+// cfbRankingPolls are the polls GetCFBRankedTeams reads, most preferred first.
+var cfbRankingPolls = []string{"Playoff Committee Rankings", "AP Top 25", "Coaches Poll"}
+
+// GetCFBRankedTeams returns the schools ranked this week, from the playoff
+// committee's rankings once they're out and the AP poll before that.
+func GetCFBRankedTeams() (map[string]bool, error) {
+	calendar, err := GetCalendar()
+	if err != nil {
+		return nil, fmt.Errorf("GetCFBRankedTeams: %v", err)
 	}
-	linesUrl := fmt.Sprintf("%syear=%d&seasonType=%s&week=%d", cfbUrl, calendar.Season.Year, calendar.Week.WeekType, weekNum)
-	linesResp, err := common.CFBDWrapper(linesUrl)
+
+	rankings, err := providers.Football.Rankings(calendar)
 	if err != nil {
-		return []external.CFBD_BettingLines{}, err
+		return nil, err
+	}
+
+	ranked := make(map[string]bool)
+	if len(rankings) == 0 {
+		return ranked, nil
 	}
-	if linesResp != nil {
-		if linesResp.Body != nil {
-			defer linesResp.Body.Close()
-		} else {
-			return nil, errors.New("CFB Lines Empty")
+	latest := rankings[len(rankings)-1]
+	for _, pollName := range cfbRankingPolls {
+		for _, poll := range latest.Polls {
+			if poll.Poll != pollName {
+				continue
+			}
+			for _, rank := range poll.Ranks {
+				ranked[rank.School] = true
+			}
+			return ranked, nil
 		}
-	} else {
-		return nil, errors.New("CFB Lines Empty")
 	}
+	return ranked, nil
+}
 
-	var bettingLines []external.CFBD_BettingLines
-	err = json.NewDecoder(linesResp.Body).Decode(&bettingLines)
+func GetCFBGames() ([]external.CFBD_BettingLines, error) {
+	return GetCFBGamesWithin(common.AnyAge)
+}
+
+// GetCFBGamesWithin is GetCFBGames, but fetches the lines again if the cached
+// copy is older than maxAge.
+func GetCFBGamesWithin(maxAge time.Duration) (_ []external.CFBD_BettingLines, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered in GetCFBGames", r)
+			debug.PrintStack()
+			err = fmt.Errorf("panic recovered in GetCFBGames: %v", r)
+		}
+	}()
+
+	calendar, err := GetCalendar()
 	if err != nil {
-		return []external.CFBD_BettingLines{}, err
+		return []external.CFBD_BettingLines{}, fmt.Errorf("GetCFBGames: %v", err)
 	}
 
-	return bettingLines, nil
+	return providers.Football.Lines(calendar, maxAge)
 }
 
 func GetCfbdBet(betid int) (_ external.CFBD_BettingLines, err error) {
@@ -190,46 +193,10 @@ func GetCfbdBet(betid int) (_ external.CFBD_BettingLines, err error) {
 		}
 	}()
 
-	cfbUrl := "https://api.collegefootballdata.com/lines?"
-	pfWeekUrl := "https://api.perfectfall.com/week-season"
-
-	weekResp, err := common.PFWrapper(pfWeekUrl)
-	if err != nil {
-		return external.CFBD_BettingLines{}, err
-	}
-	defer weekResp.Body.Close()
-
-	var calendar external.CalendarData
-	err = json.NewDecoder(weekResp.Body).Decode(&calendar)
-	if err != nil {
-		fmt.Printf("error parsing json err: %v", err)
-		return external.CFBD_BettingLines{}, err
-	}
-
-	if calendar.Week == nil {
-		return external.CFBD_BettingLines{}, fmt.Errorf("GetCfbdBet: calendar.Week is nil - API did not return week data")
-	}
-	if calendar.Season == nil {
-		return external.CFBD_BettingLines{}, fmt.Errorf("GetCfbdBet: calendar.Season is nil - API did not return season data")
-	}
-
-	var weekNum = calendar.Week.WeekNum
-	if weekNum > calendar.MaxRegWeek {
-		weekNum = 1
-	}
-	linesUrl := fmt.Sprintf("%syear=%d&seasonType=%s&week=%d", cfbUrl, calendar.Season.Year, calendar.Week.WeekType, weekNum)
-	linesResp, err := common.CFBDWrapper(linesUrl)
+	bettingLines, err := GetCFBGames()
 	if err != nil {
 		return external.CFBD_BettingLines{}, err
 	}
-	defer linesResp.Body.Close()
-
-	var bettingLines []external.CFBD_BettingLines
-	err = json.NewDecoder(linesResp.Body).Decode(&bettingLines)
-	if err != nil {
-		fmt.Printf("error parsing json err: %v", err)
-		return external.CFBD_BettingLines{}, err
-	}
 
 	for _, bet := range bettingLines {
 		if bet.ID == betid {
@@ -239,3 +206,21 @@ func GetCfbdBet(betid int) (_ external.CFBD_BettingLines, err error) {
 
 	return external.CFBD_BettingLines{}, errors.New("bet not found")
 }
+
+func GetCFBScoreboard() (external.CFBD_Scoreboard, error) {
+	return GetCFBScoreboardWithin(common.AnyAge)
+}
+
+// GetCFBScoreboardWithin is GetCFBScoreboard, but fetches the scoreboard again
+// if the cached copy is older than maxAge.
+func GetCFBScoreboardWithin(maxAge time.Duration) (_ external.CFBD_Scoreboard, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered in GetCFBScoreboard", r)
+			debug.PrintStack()
+			err = fmt.Errorf("panic recovered in GetCFBScoreboard: %v", r)
+		}
+	}()
+
+	return providers.Football.Scoreboard(maxAge)
+}
@@ -1,22 +1,21 @@
 package extService
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"perfectOddsBot/models/external"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"strconv"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
 )
 
-func ListCBBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
-	scoreboardUrl := "http://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball/scoreboard"
-	linesUrl := "https://sports.core.api.espn.com/v2/sports/basketball/leagues/mens-college-basketball/events/%s/competitions/%s/odds"
-
+// ListESPNGames lists a league's games on the current day in the guild's
+// timezone, and their current lines.
+func ListESPNGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport Sport) {
 	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
 	if err != nil {
 		common.SendError(s, i, err, db)
@@ -27,37 +26,24 @@ func ListCBBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 		return
 	}
 
-	scoreboardResp, err := common.ESPNWrapper(scoreboardUrl)
-	if err != nil {
-		common.SendError(s, i, err, db)
-		return
-	}
-	defer scoreboardResp.Body.Close()
-
-	var scoreboard external.ESPN_Scoreboard
-	err = json.NewDecoder(scoreboardResp.Body).Decode(&scoreboard)
+	loc := common.GuildLocation(*guild)
+	now := time.Now()
+	events, err := GetESPNGamesOn(sport, now, loc)
 	if err != nil {
 		common.SendError(s, i, err, db)
 		return
 	}
 
 	var response string
-	if len(scoreboard.Events) == 0 {
+	if len(events) == 0 {
 		response = "There are no games to list"
 	} else {
-		response = fmt.Sprintf("Lines for %s - \n", scoreboard.Day)
-		for _, event := range scoreboard.Events {
+		response = fmt.Sprintf("Lines for %s - \n", now.In(loc).Format("Monday, January 2"))
+		for _, event := range events {
 			for _, game := range event.Competitions {
 				if game.Status.Type.Name != "STATUS_FINAL" {
-					linesResp, err := common.ESPNWrapper(fmt.Sprintf(linesUrl, event.ID, event.ID))
-					if err != nil {
-						common.SendError(s, i, err, db)
-						return
-					}
-					defer linesResp.Body.Close()
-
-					var lines external.ESPN_Lines
-					err = json.NewDecoder(linesResp.Body).Decode(&lines)
+					eventID, _ := strconv.Atoi(event.ID)
+					lines, err := providers.Leagues.Odds(sport, eventID)
 					if err != nil {
 						common.SendError(s, i, err, db)
 						return
@@ -69,7 +55,7 @@ func ListCBBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 						lineText = line.Details
 					}
 
-					response += fmt.Sprintf("* `%s` (%s): %s\n", event.Name, event.ID, lineText)
+					response += fmt.Sprintf("* `%s` (%s) %s: %s\n", event.Name, event.ID, common.DiscordTimestamp(parseESPNDate(event.Date), "t"), lineText)
 				} else {
 					response += fmt.Sprintf("* `%s` - FINAL\n", event.Name)
 				}
@@ -90,17 +76,44 @@ func ListCBBGames(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm
 	}
 }
 
-func GetCbbGames() ([]external.ESPN_Event, error) {
-	scoreboardUrl := "http://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball/scoreboard"
+// ESPNProvider reads games and odds for every league from ESPN.
+type ESPNProvider struct{}
 
-	scoreboardResp, err := common.ESPNWrapper(scoreboardUrl)
-	if err != nil {
-		return []external.ESPN_Event{}, err
+func (ESPNProvider) Scoreboard(sport Sport, maxAge time.Duration) (external.ESPN_Scoreboard, error) {
+	var scoreboard external.ESPN_Scoreboard
+	resp, err := common.ESPNWrapperWithin(sport.scoreboardURL(), maxAge)
+	if err := decodeResponse(resp, err, sport.Name+" Scoreboard", &scoreboard); err != nil {
+		return external.ESPN_Scoreboard{}, err
 	}
-	defer scoreboardResp.Body.Close()
+	return scoreboard, nil
+}
 
+func (ESPNProvider) ScoreboardDates(sport Sport, from time.Time, to time.Time) (external.ESPN_Scoreboard, error) {
 	var scoreboard external.ESPN_Scoreboard
-	err = json.NewDecoder(scoreboardResp.Body).Decode(&scoreboard)
+	resp, err := common.ESPNWrapper(sport.scoreboardDatesURL(from, to))
+	if err := decodeResponse(resp, err, sport.Name+" Scoreboard", &scoreboard); err != nil {
+		return external.ESPN_Scoreboard{}, err
+	}
+	return scoreboard, nil
+}
+
+func (ESPNProvider) Odds(sport Sport, eventID int) (external.ESPN_Lines, error) {
+	var bettingLines external.ESPN_Lines
+	resp, err := common.ESPNWrapper(sport.oddsURL(eventID))
+	if err := decodeResponse(resp, err, sport.Name+" Lines", &bettingLines); err != nil {
+		return external.ESPN_Lines{}, err
+	}
+	return bettingLines, nil
+}
+
+func GetESPNGames(sport Sport) ([]external.ESPN_Event, error) {
+	return GetESPNGamesWithin(sport, common.AnyAge)
+}
+
+// GetESPNGamesWithin is GetESPNGames, but fetches the scoreboard again if the
+// cached copy is older than maxAge.
+func GetESPNGamesWithin(sport Sport, maxAge time.Duration) ([]external.ESPN_Event, error) {
+	scoreboard, err := providers.Leagues.Scoreboard(sport, maxAge)
 	if err != nil {
 		return []external.ESPN_Event{}, err
 	}
@@ -109,22 +122,39 @@ func GetCbbGames() ([]external.ESPN_Event, error) {
 		return scoreboard.Events, nil
 	}
 
-	return []external.ESPN_Event{}, errors.New("Unable to fetch list of CBB games")
+	return []external.ESPN_Event{}, fmt.Errorf("Unable to fetch list of %s games", sport.Name)
 }
 
-func GetCbbLines(betid int) (external.ESPN_Lines, error) {
-	linesUrl := fmt.Sprintf("https://sports.core.api.espn.com/v2/sports/basketball/leagues/mens-college-basketball/events/%s/competitions/%s/odds", strconv.Itoa(betid), strconv.Itoa(betid))
+// GetESPNGamesOn returns a league's games that start on day in loc. ESPN's own
+// scoreboard follows US Eastern days, so the days either side are fetched too.
+func GetESPNGamesOn(sport Sport, day time.Time, loc *time.Location) ([]external.ESPN_Event, error) {
+	start := common.StartOfDay(day, loc)
+	end := start.AddDate(0, 0, 1)
 
-	linesResp, err := common.CFBDWrapper(linesUrl)
+	scoreboard, err := providers.Leagues.ScoreboardDates(sport, start.AddDate(0, 0, -1), end)
 	if err != nil {
-		return external.ESPN_Lines{}, err
+		return []external.ESPN_Event{}, err
 	}
-	defer linesResp.Body.Close()
+	return eventsBetween(scoreboard.Events, start, end), nil
+}
 
-	var bettingLines external.ESPN_Lines
-	err = json.NewDecoder(linesResp.Body).Decode(&bettingLines)
+// eventsBetween returns the events that start in [start, end).
+func eventsBetween(events []external.ESPN_Event, start time.Time, end time.Time) []external.ESPN_Event {
+	var between []external.ESPN_Event
+	for _, event := range events {
+		startTime := parseESPNDate(event.Date)
+		if startTime.IsZero() || startTime.Before(start) || !startTime.Before(end) {
+			continue
+		}
+		between = append(between, event)
+	}
+	return between
+}
+
+func GetESPNLines(sport Sport, betid int) (external.ESPN_Lines, error) {
+	bettingLines, err := providers.Leagues.Odds(sport, betid)
 	if err != nil {
-		return external.ESPN_Lines{}, errors.New(fmt.Sprintf("error parsing json err: %v", err))
+		return external.ESPN_Lines{}, err
 	}
 
 	if len(bettingLines.Items) > 0 {
@@ -134,17 +164,8 @@ func GetCbbLines(betid int) (external.ESPN_Lines, error) {
 	return external.ESPN_Lines{}, errors.New("bet not found")
 }
 
-func GetCbbGame(eventId string) (external.ESPN_Event, error) {
-	scoreboardUrl := "https://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball/scoreboard"
-
-	scoreboardResp, err := common.ESPNWrapper(scoreboardUrl)
-	if err != nil {
-		return external.ESPN_Event{}, err
-	}
-	defer scoreboardResp.Body.Close()
-
-	var scoreboard external.ESPN_Scoreboard
-	err = json.NewDecoder(scoreboardResp.Body).Decode(&scoreboard)
+func GetESPNGame(sport Sport, eventId string) (external.ESPN_Event, error) {
+	scoreboard, err := providers.Leagues.Scoreboard(sport, common.AnyAge)
 	if err != nil {
 		return external.ESPN_Event{}, err
 	}
@@ -0,0 +1,151 @@
+package extService
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"perfectOddsBot/models/external"
+)
+
+// fixtureReplay reads recorded API responses from a directory. Each lookup has
+// a file named after it, such as cfb_lines.json, holding the raw JSON the real
+// API returned. To replay a day of games, record numbered files instead
+// (cfb_lines.1.json, cfb_lines.2.json, ...): each lookup returns the next file
+// and stays on the last one once the recording runs out.
+type fixtureReplay struct {
+	dir   string
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// FixtureProviders replays recorded responses from dir in place of the live
+// APIs, so the bot can run with no network. The files are:
+//
+//	calendar.json            Perfect Fall week-season
+//	teams.json               Perfect Fall school-list
+//	cfb_lines.json           CFBD lines for the calendar's week
+//	cfb_scoreboard.json      CFBD scoreboard
+//	cfb_rankings.json        CFBD rankings for the calendar's week
+//	<sport>_scoreboard.json      ESPN scoreboard, e.g. nfl_scoreboard.json
+//	<sport>_scoreboard_<from>-<to>.json
+//	                             ESPN scoreboard for a date range, e.g.
+//	                             nba_scoreboard_20270101-20270103.json; falls
+//	                             back to <sport>_scoreboard.json
+//	<sport>_odds_<eventID>.json  ESPN odds for one event, e.g. cbb_odds_401.json
+func FixtureProviders(dir string) Providers {
+	replay := &fixtureReplay{dir: dir, calls: make(map[string]int)}
+	return Providers{
+		Schedule: fixtureSchedule{replay},
+		Football: fixtureFootball{replay},
+		Leagues:  fixtureLeagues{replay},
+	}
+}
+
+func (f *fixtureReplay) load(name string, v interface{}) error {
+	path, err := f.next(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error parsing fixture %s: %v", path, err)
+	}
+	return nil
+}
+
+// has reports whether there is a recording for name.
+func (f *fixtureReplay) has(name string) bool {
+	for _, file := range []string{name + ".json", name + ".1.json"} {
+		if _, err := os.Stat(filepath.Join(f.dir, file)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the file for the next lookup of name.
+func (f *fixtureReplay) next(name string) (string, error) {
+	single := filepath.Join(f.dir, name+".json")
+	if _, err := os.Stat(single); err == nil {
+		return single, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	call := f.calls[name] + 1
+	path := filepath.Join(f.dir, fmt.Sprintf("%s.%d.json", name, call))
+	if _, err := os.Stat(path); err == nil {
+		f.calls[name] = call
+		return path, nil
+	}
+	if call > 1 {
+		return filepath.Join(f.dir, fmt.Sprintf("%s.%d.json", name, call-1)), nil
+	}
+	return "", fmt.Errorf("no fixture for %s in %s", name, f.dir)
+}
+
+type fixtureSchedule struct{ replay *fixtureReplay }
+
+func (f fixtureSchedule) Calendar() (external.CalendarData, error) {
+	var calendar external.CalendarData
+	err := f.replay.load("calendar", &calendar)
+	return calendar, err
+}
+
+func (f fixtureSchedule) Teams() (external.TeamList, error) {
+	var teamList external.TeamList
+	err := f.replay.load("teams", &teamList)
+	return teamList, err
+}
+
+type fixtureFootball struct{ replay *fixtureReplay }
+
+func (f fixtureFootball) Lines(calendar external.CalendarData, maxAge time.Duration) ([]external.CFBD_BettingLines, error) {
+	var bettingLines []external.CFBD_BettingLines
+	err := f.replay.load("cfb_lines", &bettingLines)
+	return bettingLines, err
+}
+
+func (f fixtureFootball) Scoreboard(maxAge time.Duration) (external.CFBD_Scoreboard, error) {
+	var scoreboard external.CFBD_Scoreboard
+	err := f.replay.load("cfb_scoreboard", &scoreboard)
+	return scoreboard, err
+}
+
+func (f fixtureFootball) Rankings(calendar external.CalendarData) (external.CFBD_Rankings, error) {
+	var rankings external.CFBD_Rankings
+	err := f.replay.load("cfb_rankings", &rankings)
+	return rankings, err
+}
+
+type fixtureLeagues struct{ replay *fixtureReplay }
+
+func (f fixtureLeagues) Scoreboard(sport Sport, maxAge time.Duration) (external.ESPN_Scoreboard, error) {
+	var scoreboard external.ESPN_Scoreboard
+	err := f.replay.load(sport.Key+"_scoreboard", &scoreboard)
+	return scoreboard, err
+}
+
+func (f fixtureLeagues) ScoreboardDates(sport Sport, from time.Time, to time.Time) (external.ESPN_Scoreboard, error) {
+	var scoreboard external.ESPN_Scoreboard
+	name := fmt.Sprintf("%s_scoreboard_%s-%s", sport.Key, from.Format("20060102"), to.Format("20060102"))
+	if !f.replay.has(name) {
+		name = sport.Key + "_scoreboard"
+	}
+	err := f.replay.load(name, &scoreboard)
+	return scoreboard, err
+}
+
+func (f fixtureLeagues) Odds(sport Sport, eventID int) (external.ESPN_Lines, error) {
+	var lines external.ESPN_Lines
+	err := f.replay.load(fmt.Sprintf("%s_odds_%d", sport.Key, eventID), &lines)
+	return lines, err
+}
@@ -0,0 +1,104 @@
+package extService
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+)
+
+func writeFixture(t *testing.T, dir string, name string, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+}
+
+func TestFixtureProviders(t *testing.T) {
+	t.Run("reads a single recording on every call", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, dir, "cbb_odds_401.json", `{"count":1,"items":[{"details":"DUKE -3.5"}]}`)
+		p := FixtureProviders(dir)
+
+		for call := 0; call < 2; call++ {
+			lines, err := p.Leagues.Odds(SportCBB, 401)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(lines.Items) != 1 || lines.Items[0].Details != "DUKE -3.5" {
+				t.Errorf("unexpected lines: %+v", lines.Items)
+			}
+		}
+	})
+
+	t.Run("replays numbered recordings in order and holds the last", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, dir, "cfb_lines.1.json", `[{"id":1,"homeTeam":"Ohio State","awayTeam":"Michigan"}]`)
+		writeFixture(t, dir, "cfb_lines.2.json", `[{"id":1,"homeTeam":"Ohio State","awayTeam":"Michigan","homeScore":24,"awayScore":17}]`)
+		p := FixtureProviders(dir)
+
+		var finals []bool
+		for call := 0; call < 3; call++ {
+			games, err := p.Football.Lines(external.CalendarData{}, common.AnyAge)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			finals = append(finals, games[0].HomeScore != nil)
+		}
+		if finals[0] || !finals[1] || !finals[2] {
+			t.Errorf("expected the scheduled game then the final twice, got %v", finals)
+		}
+	})
+
+	t.Run("missing recording is an error", func(t *testing.T) {
+		p := FixtureProviders(t.TempDir())
+		if _, err := p.Schedule.Teams(); err == nil {
+			t.Error("expected an error for a missing fixture")
+		}
+	})
+}
+
+func TestGetESPNGamesOn(t *testing.T) {
+	dir := t.TempDir()
+	// Tip-offs at 11pm Eastern on the 1st, 6:30pm Eastern on the 2nd and 11pm
+	// Eastern on the 2nd.
+	writeFixture(t, dir, "nba_scoreboard_20270101-20270103.json", `{"events":[
+		{"id":"1","date":"2027-01-02T04:00Z"},
+		{"id":"2","date":"2027-01-02T23:30Z"},
+		{"id":"3","date":"2027-01-03T04:00Z"}]}`)
+	previous := CurrentProviders()
+	UseProviders(FixtureProviders(dir))
+	defer UseProviders(previous)
+
+	eastern, _ := time.LoadLocation("America/New_York")
+	london, _ := time.LoadLocation("Europe/London")
+	day := time.Date(2027, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want []string
+	}{
+		{"Eastern day", eastern, []string{"2", "3"}},
+		{"London day", london, []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := GetESPNGamesOn(SportNBA, day, tt.loc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("games = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
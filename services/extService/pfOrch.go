@@ -0,0 +1,50 @@
+package extService
+
+import (
+	"errors"
+
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+)
+
+// PerfectFallProvider reads the football calendar and school list from the
+// Perfect Fall API.
+type PerfectFallProvider struct{}
+
+func (PerfectFallProvider) Calendar() (external.CalendarData, error) {
+	var calendar external.CalendarData
+	resp, err := common.PFWrapper("https://api.perfectfall.com/week-season")
+	if err := decodeResponse(resp, err, "Week Season", &calendar); err != nil {
+		return external.CalendarData{}, err
+	}
+	return calendar, nil
+}
+
+func (PerfectFallProvider) Teams() (external.TeamList, error) {
+	var teamList external.TeamList
+	resp, err := common.PFWrapper("https://api.perfectfall.com/school-list")
+	if err := decodeResponse(resp, err, "School List", &teamList); err != nil {
+		return nil, err
+	}
+	return teamList, nil
+}
+
+// GetCalendar returns the current football week and season.
+func GetCalendar() (external.CalendarData, error) {
+	calendar, err := providers.Schedule.Calendar()
+	if err != nil {
+		return external.CalendarData{}, err
+	}
+	if calendar.Week == nil {
+		return external.CalendarData{}, errors.New("calendar.Week is nil - API did not return week data")
+	}
+	if calendar.Season == nil {
+		return external.CalendarData{}, errors.New("calendar.Season is nil - API did not return season data")
+	}
+	return calendar, nil
+}
+
+// GetTeamList returns the schools a guild can subscribe to.
+func GetTeamList() (external.TeamList, error) {
+	return providers.Schedule.Teams()
+}
@@ -0,0 +1,84 @@
+package extService
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"perfectOddsBot/models/external"
+)
+
+// ScheduleProvider supplies the football calendar and the list of schools teams
+// can be subscribed to.
+type ScheduleProvider interface {
+	Calendar() (external.CalendarData, error)
+	Teams() (external.TeamList, error)
+}
+
+// FootballProvider supplies college football lines and scores. maxAge is how
+// old a cached response can be; pass common.AnyAge unless the caller needs live
+// data.
+type FootballProvider interface {
+	// Lines returns every game with lines for a week of the season, including
+	// final scores once they are in.
+	Lines(calendar external.CalendarData, maxAge time.Duration) ([]external.CFBD_BettingLines, error)
+	Scoreboard(maxAge time.Duration) (external.CFBD_Scoreboard, error)
+	// Rankings returns the polls for a week of the season.
+	Rankings(calendar external.CalendarData) (external.CFBD_Rankings, error)
+}
+
+// LeagueProvider supplies games and odds for the ESPN leagues. maxAge is as for
+// FootballProvider.
+type LeagueProvider interface {
+	Scoreboard(sport Sport, maxAge time.Duration) (external.ESPN_Scoreboard, error)
+	// ScoreboardDates returns the games from one date to another, inclusive.
+	// ESPN's dates are US Eastern days.
+	ScoreboardDates(sport Sport, from time.Time, to time.Time) (external.ESPN_Scoreboard, error)
+	Odds(sport Sport, eventID int) (external.ESPN_Lines, error)
+}
+
+// Providers are the data sources the bot reads games, odds and teams from.
+type Providers struct {
+	Schedule ScheduleProvider
+	Football FootballProvider
+	Leagues  LeagueProvider
+}
+
+// LiveProviders reads from the Perfect Fall, CFBD and ESPN APIs.
+func LiveProviders() Providers {
+	return Providers{
+		Schedule: PerfectFallProvider{},
+		Football: CFBDProvider{},
+		Leagues:  ESPNProvider{},
+	}
+}
+
+var providers = LiveProviders()
+
+// UseProviders replaces the data sources. Call it at startup, before the
+// scheduler runs.
+func UseProviders(p Providers) {
+	providers = p
+}
+
+// CurrentProviders returns the data sources in use.
+func CurrentProviders() Providers {
+	return providers
+}
+
+// decodeResponse decodes a JSON response from one of the API wrappers.
+func decodeResponse(resp *http.Response, err error, name string, v interface{}) error {
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("%s Empty", name)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error parsing %s json: %v", name, err)
+	}
+	return nil
+}
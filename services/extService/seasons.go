@@ -0,0 +1,149 @@
+package extService
+
+import (
+	"fmt"
+	"time"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+)
+
+// SeasonPhase is the part of a season a sport is in.
+type SeasonPhase string
+
+const (
+	PhasePreseason  SeasonPhase = "preseason"
+	PhaseRegular    SeasonPhase = "regular"
+	PhasePostseason SeasonPhase = "postseason"
+	PhaseOffseason  SeasonPhase = "offseason"
+)
+
+// cfbPreseasonWindow is how long before week one CFB counts as preseason, so
+// lines are picked up as books post them.
+const cfbPreseasonWindow = 14 * 24 * time.Hour
+
+// Label returns the phase as shown in embeds.
+func (phase SeasonPhase) Label() string {
+	switch phase {
+	case PhasePreseason:
+		return "Preseason"
+	case PhaseRegular:
+		return "Regular Season"
+	case PhasePostseason:
+		return "Postseason"
+	}
+	return "Off-Season"
+}
+
+// SeasonSports are the sport keys with a season calendar.
+var SeasonSports = []string{models.SportCFB, models.SportCBB, models.SportNFL, models.SportNBA}
+
+// Season is where a sport is in its season.
+type Season struct {
+	Sport string
+	Name  string
+	Phase SeasonPhase
+	// Start and End bound the season the phase belongs to. Either may be zero
+	// when the calendar doesn't say.
+	Start time.Time
+	End   time.Time
+	// Reason explains how the phase was decided.
+	Reason string
+}
+
+// GetSeason looks up where a sport is in its season: the Perfect Fall calendar
+// for CFB and the ESPN scoreboard for every other league.
+func GetSeason(sportKey string, now time.Time) (Season, error) {
+	if sportKey == models.SportCFB {
+		calendar, err := GetCalendar()
+		if err != nil {
+			return Season{}, err
+		}
+		return cfbSeason(calendar, now), nil
+	}
+
+	sport, err := ESPNSport(sportKey)
+	if err != nil {
+		return Season{}, err
+	}
+	scoreboard, err := providers.Leagues.Scoreboard(sport, common.AnyAge)
+	if err != nil {
+		return Season{}, err
+	}
+	return espnSeason(sport, scoreboard, now)
+}
+
+// cfbSeason reads the phase from the Perfect Fall calendar: the season's dates
+// bound it and the current week's type marks the postseason.
+func cfbSeason(calendar external.CalendarData, now time.Time) Season {
+	season := Season{Sport: models.SportCFB, Name: "CFB", Start: calendar.Season.StartDate, End: calendar.Season.EndDate}
+
+	switch {
+	case now.Before(season.Start.Add(-cfbPreseasonWindow)):
+		season.Phase = PhaseOffseason
+		season.Reason = fmt.Sprintf("The %d season starts %s", calendar.Season.Year, season.Start.Format("Jan 2"))
+	case now.Before(season.Start):
+		season.Phase = PhasePreseason
+		season.Reason = fmt.Sprintf("The %d season starts %s", calendar.Season.Year, season.Start.Format("Jan 2"))
+	case !season.End.IsZero() && now.After(season.End):
+		season.Phase = PhaseOffseason
+		season.Reason = fmt.Sprintf("The %d season ended %s", calendar.Season.Year, season.End.Format("Jan 2"))
+		if calendar.NextSeason != nil {
+			season.Reason += fmt.Sprintf("; the %d season starts %s", calendar.NextSeason.Year, calendar.NextSeason.StartDate.Format("Jan 2"))
+		}
+	case calendar.Week.WeekType == "postseason":
+		season.Phase = PhasePostseason
+		season.Reason = "Perfect Fall's current week is a postseason week"
+	default:
+		season.Phase = PhaseRegular
+		season.Reason = fmt.Sprintf("Perfect Fall's current week is week %d", calendar.Week.WeekNum)
+	}
+	return season
+}
+
+// espnSeason reads the phase from the season type on an ESPN scoreboard.
+func espnSeason(sport Sport, scoreboard external.ESPN_Scoreboard, now time.Time) (Season, error) {
+	if len(scoreboard.Leagues) == 0 {
+		return Season{}, fmt.Errorf("%s scoreboard has no league season", sport.Name)
+	}
+	league := scoreboard.Leagues[0].Season
+
+	season := Season{
+		Sport: sport.Key,
+		Name:  sport.Name,
+		Start: parseESPNDate(league.StartDate),
+		End:   parseESPNDate(league.EndDate),
+	}
+
+	// ESPN numbers its season types 1 preseason, 2 regular season,
+	// 3 postseason, 4 off-season and 5 play-in.
+	switch league.Type.Type {
+	case 1:
+		season.Phase = PhasePreseason
+	case 2:
+		season.Phase = PhaseRegular
+	case 3, 5:
+		season.Phase = PhasePostseason
+	default:
+		season.Phase = PhaseOffseason
+	}
+	season.Reason = fmt.Sprintf("ESPN lists the %s as %s", league.DisplayName, league.Type.Name)
+
+	if season.Phase != PhaseOffseason && !season.End.IsZero() && now.After(season.End) {
+		season.Phase = PhaseOffseason
+		season.Reason = fmt.Sprintf("The %s ended %s", league.DisplayName, season.End.Format("Jan 2"))
+	}
+	return season, nil
+}
+
+// parseESPNDate parses the dates ESPN puts on its scoreboards. A date it can't
+// parse is zero.
+func parseESPNDate(value string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04Z", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
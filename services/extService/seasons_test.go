@@ -0,0 +1,85 @@
+package extService
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"perfectOddsBot/models/external"
+)
+
+func TestCFBSeason(t *testing.T) {
+	calendar := external.CalendarData{
+		Week:       &external.Week{WeekNum: 5, WeekType: "regular"},
+		Season:     &external.Season{Year: 2026, StartDate: time.Date(2026, 8, 29, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2027, 1, 20, 0, 0, 0, 0, time.UTC)},
+		NextSeason: &external.Season{Year: 2027, StartDate: time.Date(2027, 8, 28, 0, 0, 0, 0, time.UTC)},
+	}
+	postseason := calendar
+	postseason.Week = &external.Week{WeekNum: 1, WeekType: "postseason"}
+
+	tests := []struct {
+		name     string
+		calendar external.CalendarData
+		now      time.Time
+		want     SeasonPhase
+	}{
+		{"summer", calendar, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), PhaseOffseason},
+		{"week before kickoff", calendar, time.Date(2026, 8, 22, 0, 0, 0, 0, time.UTC), PhasePreseason},
+		{"regular season", calendar, time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), PhaseRegular},
+		{"bowl season in January", postseason, time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC), PhasePostseason},
+		{"after the title game", postseason, time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC), PhaseOffseason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			season := cfbSeason(tt.calendar, tt.now)
+			if season.Phase != tt.want {
+				t.Errorf("phase = %s, want %s (%s)", season.Phase, tt.want, season.Reason)
+			}
+			if season.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
+func TestESPNSeason(t *testing.T) {
+	scoreboard := func(t *testing.T, body string) external.ESPN_Scoreboard {
+		t.Helper()
+		var sb external.ESPN_Scoreboard
+		if err := json.Unmarshal([]byte(body), &sb); err != nil {
+			t.Fatalf("failed to parse scoreboard: %v", err)
+		}
+		return sb
+	}
+	now := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		body string
+		want SeasonPhase
+	}{
+		{"regular season", `{"leagues":[{"season":{"startDate":"2026-09-01T07:00Z","endDate":"2027-02-15T07:59Z","displayName":"2026","type":{"type":2,"name":"Regular Season"}}}]}`, PhaseRegular},
+		{"postseason", `{"leagues":[{"season":{"startDate":"2026-09-01T07:00Z","endDate":"2027-02-15T07:59Z","displayName":"2026","type":{"type":3,"name":"Postseason"}}}]}`, PhasePostseason},
+		{"preseason", `{"leagues":[{"season":{"startDate":"2026-08-01T07:00Z","endDate":"2027-02-15T07:59Z","displayName":"2026","type":{"type":1,"name":"Preseason"}}}]}`, PhasePreseason},
+		{"off-season", `{"leagues":[{"season":{"startDate":"2026-06-01T07:00Z","endDate":"2026-08-01T06:59Z","displayName":"2026","type":{"type":4,"name":"Off Season"}}}]}`, PhaseOffseason},
+		{"season already ended", `{"leagues":[{"season":{"startDate":"2026-01-01T07:00Z","endDate":"2026-06-01T06:59Z","displayName":"2026","type":{"type":3,"name":"Postseason"}}}]}`, PhaseOffseason},
+		{"football week calendar", `{"leagues":[{"season":{"startDate":"2026-07-30T07:00Z","endDate":"2027-02-12T07:59Z","displayName":"2026","type":{"type":2,"name":"Regular Season"}},"calendar":[{"label":"Regular Season","value":"2"}]}]}`, PhaseRegular},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			season, err := espnSeason(SportNFL, scoreboard(t, tt.body), now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if season.Phase != tt.want {
+				t.Errorf("phase = %s, want %s (%s)", season.Phase, tt.want, season.Reason)
+			}
+		})
+	}
+
+	if _, err := espnSeason(SportNFL, external.ESPN_Scoreboard{}, now); err == nil {
+		t.Error("expected an error for a scoreboard with no league")
+	}
+}
@@ -0,0 +1,58 @@
+package extService
+
+import (
+	"fmt"
+	"time"
+
+	"perfectOddsBot/models"
+)
+
+// Sport is an ESPN league that bets can be created for. ESPN serves every
+// league in the same shape, so one flow handles them all.
+type Sport struct {
+	// Key is stored on models.Bet and used in command names and custom IDs.
+	Key string
+	// Name is the short name shown in embeds, such as "NFL".
+	Name string
+	// FullName is the name shown in command descriptions.
+	FullName string
+	// Category and League form ESPN's path for the league, such as
+	// football/nfl.
+	Category string
+	League   string
+}
+
+var (
+	SportCBB = Sport{Key: models.SportCBB, Name: "CBB", FullName: "College Basketball", Category: "basketball", League: "mens-college-basketball"}
+	SportNFL = Sport{Key: models.SportNFL, Name: "NFL", FullName: "NFL", Category: "football", League: "nfl"}
+	SportNBA = Sport{Key: models.SportNBA, Name: "NBA", FullName: "NBA", Category: "basketball", League: "nba"}
+)
+
+// ESPNSports are the leagues bets can be created for through ESPN.
+var ESPNSports = []Sport{SportCBB, SportNFL, SportNBA}
+
+// ESPNSport returns the ESPN league for a bet's sport. Bets created before
+// sports were recorded were all college basketball.
+func ESPNSport(key string) (Sport, error) {
+	if key == "" {
+		return SportCBB, nil
+	}
+	for _, sport := range ESPNSports {
+		if sport.Key == key {
+			return sport, nil
+		}
+	}
+	return Sport{}, fmt.Errorf("unknown ESPN sport %q", key)
+}
+
+func (sport Sport) scoreboardURL() string {
+	return fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/%s/%s/scoreboard", sport.Category, sport.League)
+}
+
+func (sport Sport) scoreboardDatesURL(from time.Time, to time.Time) string {
+	return fmt.Sprintf("%s?dates=%s-%s&limit=500", sport.scoreboardURL(), from.Format("20060102"), to.Format("20060102"))
+}
+
+func (sport Sport) oddsURL(eventID int) string {
+	return fmt.Sprintf("https://sports.core.api.espn.com/v2/sports/%s/leagues/%s/events/%d/competitions/%d/odds", sport.Category, sport.League, eventID, eventID)
+}
@@ -0,0 +1,43 @@
+package extService
+
+import "testing"
+
+func TestESPNSport(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    Sport
+		wantErr bool
+	}{
+		{key: "", want: SportCBB},
+		{key: "cbb", want: SportCBB},
+		{key: "nfl", want: SportNFL},
+		{key: "nba", want: SportNBA},
+		{key: "cfb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ESPNSport(tt.key)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ESPNSport(%q) expected an error", tt.key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ESPNSport(%q) unexpected error: %v", tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ESPNSport(%q) = %s, want %s", tt.key, got.Name, tt.want.Name)
+		}
+	}
+}
+
+func TestSportURLs(t *testing.T) {
+	if got := SportNFL.scoreboardURL(); got != "https://site.api.espn.com/apis/site/v2/sports/football/nfl/scoreboard" {
+		t.Errorf("unexpected NFL scoreboard URL: %s", got)
+	}
+	if got := SportNBA.oddsURL(401); got != "https://sports.core.api.espn.com/v2/sports/basketball/leagues/nba/events/401/competitions/401/odds" {
+		t.Errorf("unexpected NBA odds URL: %s", got)
+	}
+}
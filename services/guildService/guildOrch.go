@@ -2,9 +2,12 @@ package guildService
 
 import (
 	"fmt"
+	"math"
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -32,6 +35,7 @@ func GetGuildInfo(s *discordgo.Session, db *gorm.DB, guildID string, channelId s
 			RestrictedDrawEnabled:   false,
 			RestrictedDrawUserIDs:   "[]",
 			RestrictedDrawCardIDs:   "[]",
+			Timezone:                common.DefaultTimezone,
 		}
 		newGuildResult := db.Create(newGuild)
 		if newGuildResult.Error != nil {
@@ -239,3 +243,111 @@ func ToggleCardDrawing(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return
 	}
 }
+
+func SetLineAlerts(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		return
+	}
+
+	guild, err := GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "points":
+			guild.LineAlertThreshold = math.Max(opt.FloatValue(), 0)
+		case "odds":
+			guild.LineAlertOddsThreshold = int(math.Max(float64(opt.IntValue()), 0))
+		}
+	}
+	db.Save(&guild)
+
+	points := "off"
+	if guild.LineAlertThreshold > 0 {
+		points = fmt.Sprintf("%.1f points", guild.LineAlertThreshold)
+	}
+	odds := "off"
+	if guild.LineAlertOddsThreshold > 0 {
+		odds = fmt.Sprintf("%d cents", guild.LineAlertOddsThreshold)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Line alerts set. Spreads and totals: %s. Moneylines: %s.", points, odds),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+}
+
+func SetTimezone(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		return
+	}
+
+	timezone := strings.TrimSpace(i.ApplicationCommandData().Options[0].StringValue())
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || strings.EqualFold(timezone, "Local") {
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("`%s` is not a timezone. Use a name from the IANA timezone database, such as `America/Chicago` or `Europe/London`.", timezone),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
+	guild, err := GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	guild.Timezone = loc.String()
+	db.Save(&guild)
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Timezone set to %s. It is now %s there.", guild.Timezone, common.FormatGameTime(time.Now(), loc)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"perfectOddsBot/services/betService"
 	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
 	"strconv"
 	"strings"
 
@@ -11,7 +12,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+func HandleESPNGamePagination(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, customID string) error {
 	if i.Type != discordgo.InteractionMessageComponent {
 		return nil
 	}
@@ -20,8 +21,10 @@ func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 	var currentPage int
 	var err error
 
-	if strings.HasPrefix(customID, "create_cbb_bet_previous_page_") {
-		rest := strings.TrimPrefix(customID, "create_cbb_bet_previous_page_")
+	previousPrefix := fmt.Sprintf("create_%s_bet_previous_page_", sport.Key)
+	nextPrefix := fmt.Sprintf("create_%s_bet_next_page_", sport.Key)
+	if strings.HasPrefix(customID, previousPrefix) {
+		rest := strings.TrimPrefix(customID, previousPrefix)
 		parts := strings.SplitN(rest, "_", 2)
 		if len(parts) == 2 {
 			currentPage, err = strconv.Atoi(parts[0])
@@ -34,8 +37,8 @@ func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 			currentPage, _ = strconv.Atoi(rest)
 			currentPage--
 		}
-	} else if strings.HasPrefix(customID, "create_cbb_bet_next_page_") {
-		rest := strings.TrimPrefix(customID, "create_cbb_bet_next_page_")
+	} else if strings.HasPrefix(customID, nextPrefix) {
+		rest := strings.TrimPrefix(customID, nextPrefix)
 		parts := strings.SplitN(rest, "_", 2)
 		if len(parts) == 2 {
 			currentPage, err = strconv.Atoi(parts[0])
@@ -54,7 +57,7 @@ func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 		return fmt.Errorf("session ID not found in custom ID")
 	}
 
-	paginatedOptions, exists := betService.GetCBBPaginatedOptions(sessionID)
+	paginatedOptions, exists := betService.GetESPNPaginatedOptions(sessionID)
 	if !exists {
 		return fmt.Errorf("paginated options not found for session %s", sessionID)
 	}
@@ -77,7 +80,7 @@ func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 					Components: []discordgo.MessageComponent{
 						discordgo.SelectMenu{
 							MenuType:    discordgo.StringSelectMenu,
-							CustomID:    fmt.Sprintf("create_cbb_bet_submit_%s", sessionID),
+							CustomID:    fmt.Sprintf("create_%s_bet_submit_%s", sport.Key, sessionID),
 							Placeholder: "Select a game",
 							MinValues:   &minValues,
 							MaxValues:   1,
@@ -89,19 +92,19 @@ func HandleCBBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 					Components: []discordgo.MessageComponent{
 						discordgo.Button{
 							Label:    "Previous",
-							CustomID: fmt.Sprintf("create_cbb_bet_previous_page_%d_%s", currentPage, sessionID),
+							CustomID: fmt.Sprintf("create_%s_bet_previous_page_%d_%s", sport.Key, currentPage, sessionID),
 							Style:    discordgo.PrimaryButton,
 							Disabled: currentPage == 0,
 						},
 						discordgo.Button{
 							Label:    "Next",
-							CustomID: fmt.Sprintf("create_cbb_bet_next_page_%d_%s", currentPage, sessionID),
+							CustomID: fmt.Sprintf("create_%s_bet_next_page_%d_%s", sport.Key, currentPage, sessionID),
 							Style:    discordgo.PrimaryButton,
 							Disabled: currentPage == len(paginatedOptions)-1,
 						},
 						discordgo.Button{
 							Label:    "Cancel",
-							CustomID: fmt.Sprintf("create_cbb_bet_cancel_%s", sessionID),
+							CustomID: fmt.Sprintf("create_%s_bet_cancel_%s", sport.Key, sessionID),
 							Style:    discordgo.DangerButton,
 						},
 					},
@@ -221,12 +224,13 @@ func HandleCFBGamePagination(s *discordgo.Session, i *discordgo.InteractionCreat
 	return nil
 }
 
-func HandleCBBGameSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+func HandleESPNGameSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, customID string) error {
 	selectedGameID := i.MessageComponentData().Values[0]
 
-	if strings.HasPrefix(customID, "create_cbb_bet_submit_") {
-		sessionID := strings.TrimPrefix(customID, "create_cbb_bet_submit_")
-		betService.CleanupCBBPaginatedOptions(sessionID)
+	submitPrefix := fmt.Sprintf("create_%s_bet_submit_", sport.Key)
+	if strings.HasPrefix(customID, submitPrefix) {
+		sessionID := strings.TrimPrefix(customID, submitPrefix)
+		betService.CleanupESPNPaginatedOptions(sessionID)
 	}
 
 	gameIDInt, err := strconv.Atoi(selectedGameID)
@@ -235,7 +239,7 @@ func HandleCBBGameSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, d
 		return err
 	}
 
-	err = betService.ShowCBBBetTypeSelection(s, i, db, gameIDInt)
+	err = betService.ShowESPNBetTypeSelection(s, i, db, sport, gameIDInt)
 	if err != nil {
 		common.SendError(s, i, err, db)
 		return err
@@ -267,10 +271,10 @@ func HandleCFBGameSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, d
 	return nil
 }
 
-func HandleCBBGameCancel(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
-	sessionID := strings.TrimPrefix(customID, "create_cbb_bet_cancel_")
+func HandleESPNGameCancel(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, customID string) error {
+	sessionID := strings.TrimPrefix(customID, fmt.Sprintf("create_%s_bet_cancel_", sport.Key))
 
-	betService.CleanupCBBPaginatedOptions(sessionID)
+	betService.CleanupESPNPaginatedOptions(sessionID)
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseUpdateMessage,
@@ -305,7 +309,7 @@ func HandleCFBGameCancel(s *discordgo.Session, i *discordgo.InteractionCreate, d
 	return nil
 }
 
-func HandleCBBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+func HandleESPNBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, sport extService.Sport, customID string) error {
 	parts := strings.Split(customID, "_")
 	if len(parts) < 5 {
 		return fmt.Errorf("invalid bet type selection custom ID format: %s", customID)
@@ -334,9 +338,11 @@ func HandleCBBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCre
 	betType := "ats"
 	if betTypeStr == "ml" {
 		betType = "moneyline"
+	} else if betTypeStr == "total" {
+		betType = "total"
 	}
 
-	err = betService.CreateCBBBetFromGameID(s, i, db, betID, betType)
+	err = betService.CreateESPNBetFromGameID(s, i, db, sport, betID, betType)
 	if err != nil {
 		common.SendError(s, i, err, db)
 		return err
@@ -374,6 +380,8 @@ func HandleCFBBetTypeSelection(s *discordgo.Session, i *discordgo.InteractionCre
 	betType := "ats"
 	if betTypeStr == "ml" {
 		betType = "moneyline"
+	} else if betTypeStr == "total" {
+		betType = "total"
 	}
 
 	err = betService.CreateCFBBetFromGameID(s, i, db, betID, betType)
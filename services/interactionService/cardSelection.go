@@ -6,9 +6,11 @@ import (
 	"perfectOddsBot/models"
 	cardService "perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/cardService/cards"
+	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/historyService"
 	cardSelection "perfectOddsBot/services/interactionService/cardSelection"
+	"perfectOddsBot/services/ledgerService"
 	"strconv"
 	"strings"
 
@@ -312,7 +314,7 @@ func HandleCardOptionSelection(s *discordgo.Session, i *discordgo.InteractionCre
 			var result *models.CardResult
 
 			if selectedOptionID == cards.WheelOptionDeflation {
-				poolLoss := guild.Pool * 0.5
+				poolLoss := common.RoundPoints(guild.Pool * 0.5)
 				guild.Pool -= poolLoss
 				if guild.Pool < 0 {
 					guild.Pool = 0
@@ -343,12 +345,7 @@ func HandleCardOptionSelection(s *discordgo.Session, i *discordgo.InteractionCre
 						pointsChange = -pointsChange
 					}
 
-					allUsers[i].Points += pointsChange
-					if allUsers[i].Points < 0 {
-						allUsers[i].Points = 0
-					}
-
-					if err := tx.Save(&allUsers[i]).Error; err != nil {
+					if _, err := ledgerService.ApplyFloored(tx, &allUsers[i], pointsChange, ledgerService.Card(cards.TheWheelOfFortuneCardID)); err != nil {
 						return err
 					}
 
@@ -475,7 +472,7 @@ func HandlePlayCardSelection(s *discordgo.Session, i *discordgo.InteractionCreat
 	}
 	selectedCardID := uint(selectedCardIDInt)
 
-	if selectedCardID == cards.StopTheStealCardID {
+	if selectedCardID == cards.StopTheStealCardID || selectedCardID == cards.UnoReverseCardID {
 		cardSelection.ShowBetSelectMenuForPlayCard(s, i, db, selectedCardID, userID, guildID)
 		return nil
 	}
@@ -539,6 +536,10 @@ func HandlePlayCardBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 		return fmt.Errorf("card not found: %d", cardID)
 	}
 
+	if cardID == cards.UnoReverseCardID {
+		return handleRestoredUnoReversePlay(s, i, db, card, userID, guildID, betID)
+	}
+
 	// Validate and get data (with locking for consistency)
 	var user models.User
 	var bet models.Bet
@@ -625,8 +626,7 @@ func HandlePlayCardBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 			return fmt.Errorf("user not found: %v", err)
 		}
 
-		txUser.Points += float64(refundAmount)
-		if err := tx.Save(&txUser).Error; err != nil {
+		if err := ledgerService.Apply(tx, &txUser, float64(refundAmount), ledgerService.Card(cardID).WithBet(betID)); err != nil {
 			return fmt.Errorf("error refunding points: %v", err)
 		}
 
@@ -635,7 +635,7 @@ func HandlePlayCardBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 			return fmt.Errorf("error soft deleting bet entries: %v", result.Error)
 		}
 
-		if err := cardService.PlayCardFromInventoryInTransaction(tx, txUser, cardID); err != nil {
+		if err := cardService.PlayCardOnBetInTransaction(tx, txUser, cardID, betID); err != nil {
 			return fmt.Errorf("error consuming card: %v", err)
 		}
 
@@ -651,3 +651,66 @@ func HandlePlayCardBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 
 	return nil
 }
+
+// handleRestoredUnoReversePlay points an Uno Reverse that was handed back by a
+// voided bet at another of the user's active bets.
+func handleRestoredUnoReversePlay(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, card *models.Card, userID string, guildID string, betID uint) error {
+	var user models.User
+	var bet models.Bet
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("discord_id = ? AND guild_id = ?", userID, guildID).
+			First(&user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ? AND guild_id = ? AND active = ? AND paid = ? AND deleted_at IS NULL", betID, guildID, true, false).
+			First(&bet).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("selected bet is no longer eligible")
+			}
+			return err
+		}
+
+		var inventory models.UserInventory
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND guild_id = ? AND card_id = ? AND target_bet_id IS NULL", user.ID, guildID, cards.UnoReverseCardID).
+			First(&inventory).Error; err != nil {
+			return err
+		}
+
+		inventory.TargetBetID = &bet.ID
+		return tx.Save(&inventory).Error
+	})
+	if err != nil {
+		if err.Error() == "selected bet is no longer eligible" {
+			return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: "That bet is no longer active or has already been paid. Please select an active unpaid bet.",
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			})
+		}
+		return err
+	}
+
+	guild, err := guildService.GetGuildInfo(s, db, guildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	embed := cardSelection.BuildCardResultEmbed(card, &models.CardResult{
+		Message:     fmt.Sprintf("<@%s> has the Uno Reverse card active! If they lose on '%s', they win (and vice versa)!", user.DiscordID, bet.Description),
+		PointsDelta: 0,
+		PoolDelta:   0,
+	}, user, "", guild.Pool)
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
 	"perfectOddsBot/services/historyService"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/limitService"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -58,7 +60,7 @@ func recordAffectedTargetHistory(tx *gorm.DB, guildID string, playedByUserID str
 
 func HandlePettyTheftSelection(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, userID string, targetUserID string, guildID string) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		result, err := cards.ExecutePickpocketSteal(tx, userID, targetUserID, guildID, 50.0)
+		result, err := cards.ExecutePickpocketSteal(tx, userID, targetUserID, guildID, 50.0, cards.PettyTheftCardID)
 		if err != nil {
 			return err
 		}
@@ -178,7 +180,7 @@ func HandleBetFreezeSelection(s *discordgo.Session, i *discordgo.InteractionCrea
 
 func HandleGrandLarcenySelection(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, userID string, targetUserID string, guildID string) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		result, err := cards.ExecutePickpocketSteal(tx, userID, targetUserID, guildID, 150.0)
+		result, err := cards.ExecutePickpocketSteal(tx, userID, targetUserID, guildID, 150.0, cards.GrandLarcenyCardID)
 		if err != nil {
 			return err
 		}
@@ -589,7 +591,7 @@ func HandleBracketBusterSelection(s *discordgo.Session, i *discordgo.Interaction
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("guild_id = ?", guildID).First(&guild).Error; err != nil {
 			return err
 		}
-		guild.Pool += wagerAmount
+		guild.Pool = common.RoundPoints(guild.Pool + wagerAmount)
 		if err := tx.Save(&guild).Error; err != nil {
 			return err
 		}
@@ -727,6 +729,11 @@ func HandleAntiAntiBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 			return err
 		}
 
+		guild, err := guildService.GetGuildInfo(s, tx, guildID, i.ChannelID)
+		if err != nil {
+			return err
+		}
+
 		var betAmount float64
 		if user.Points >= 100.0 {
 			betAmount = 100.0
@@ -734,12 +741,32 @@ func HandleAntiAntiBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 			betAmount = math.Round(user.Points / 2.0)
 		}
 
-		user.Points -= betAmount
-		if user.Points < 0 {
-			user.Points = 0
+		// The card's bet is a stake like any other, so it is held to the
+		// server's bet limits.
+		allowed, reason, err := limitService.AllowedStake(tx, *guild, user, nil)
+		if err != nil {
+			return err
+		}
+		limitNote := ""
+		if betAmount > allowed {
+			betAmount = allowed
+			limitNote = " (reduced to fit the server's bet limits)"
+		}
+		if betAmount <= 0 {
+			if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: fmt.Sprintf("Error: You can't stake anything on an Anti-Anti-Bet right now. %s Your card has not been used.", reason),
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			}); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("no stake allowed for anti-anti-bet")
 		}
 
-		if err := tx.Save(&user).Error; err != nil {
+		if _, err := ledgerService.ApplyFloored(tx, &user, -betAmount, ledgerService.Card(cards.AntiAntiBetCardID)); err != nil {
 			return err
 		}
 
@@ -755,11 +782,6 @@ func HandleAntiAntiBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 			return err
 		}
 
-		guild, err := guildService.GetGuildInfo(s, tx, guildID, i.ChannelID)
-		if err != nil {
-			return err
-		}
-
 		targetUsername := common.GetUsernameWithDB(tx, s, guildID, targetUserID)
 
 		card := cardService.GetCardByID(cards.AntiAntiBetCardID)
@@ -768,7 +790,7 @@ func HandleAntiAntiBetSelection(s *discordgo.Session, i *discordgo.InteractionCr
 		}
 
 		embed := BuildCardResultEmbed(card, &models.CardResult{
-			Message:     fmt.Sprintf("Anti-Anti-Bet active! <@%s> bet %.0f points that <@%s> will lose their next bet. If they lose, they'll get %.0f points at even odds (+100).", user.DiscordID, betAmount, targetUserID, betAmount*2),
+			Message:     fmt.Sprintf("Anti-Anti-Bet active! <@%s> bet %.0f points%s that <@%s> will lose their next bet. If they lose, they'll get %.0f points at even odds (+100).", user.DiscordID, betAmount, limitNote, targetUserID, betAmount*2),
 			PointsDelta: -betAmount,
 			PoolDelta:   0,
 		}, user, targetUsername, guild.Pool)
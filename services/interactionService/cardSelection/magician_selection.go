@@ -9,6 +9,7 @@ import (
 	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/historyService"
+	"perfectOddsBot/services/ledgerService"
 	"strconv"
 	"strings"
 	"sync"
@@ -250,16 +251,15 @@ func HandleMagicianCardSelection(s *discordgo.Session, i *discordgo.InteractionC
 			return fmt.Errorf("error executing borrowed card: %v", err)
 		}
 
-		drawerUser.Points += cardResult.PointsDelta
-		if drawerUser.Points < 0 {
-			drawerUser.Points = 0
+		if _, err := ledgerService.ApplyFloored(tx, &drawerUser, cardResult.PointsDelta, ledgerService.Card(card.ID)); err != nil {
+			return err
 		}
-		guild.Pool += cardResult.PoolDelta
+		guild.Pool = common.RoundPoints(guild.Pool + cardResult.PoolDelta)
 		if guild.Pool < 0 {
 			guild.Pool = 0
 		}
 
-		if err := tx.Save(&drawerUser).Error; err != nil {
+		if err := tx.Omit("points").Save(&drawerUser).Error; err != nil {
 			return err
 		}
 		if err := tx.Save(&guild).Error; err != nil {
@@ -272,11 +272,7 @@ func HandleMagicianCardSelection(s *discordgo.Session, i *discordgo.InteractionC
 				Where("discord_id = ? AND guild_id = ?", *cardResult.TargetUserID, guildID).
 				First(&targetUser).Error; err == nil {
 				targetPointsBefore := targetUser.Points
-				targetUser.Points += cardResult.TargetPointsDelta
-				if targetUser.Points < 0 {
-					targetUser.Points = 0
-				}
-				if err := tx.Save(&targetUser).Error; err != nil {
+				if _, err := ledgerService.ApplyFloored(tx, &targetUser, cardResult.TargetPointsDelta, ledgerService.Card(card.ID)); err != nil {
 					return err
 				}
 				if targetUser.DiscordID != drawerUserID {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"perfectOddsBot/models"
 	cardService "perfectOddsBot/services/cardService"
+	"perfectOddsBot/services/cardService/cards"
 	"perfectOddsBot/services/common"
 	"time"
 
@@ -67,11 +68,16 @@ func ShowBetSelectMenuForPlayCard(s *discordgo.Session, i *discordgo.Interaction
 		cardName = card.Name
 	}
 
+	prompt := "Select an active bet to cancel:"
+	if cardID == cards.UnoReverseCardID {
+		prompt = "Select an active bet to reverse:"
+	}
+
 	minValues := 1
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("🎴 Playing **%s**\n\nSelect an active bet to cancel:", cardName),
+			Content: fmt.Sprintf("🎴 Playing **%s**\n\n%s", cardName, prompt),
 			Components: []discordgo.MessageComponent{
 				discordgo.ActionsRow{
 					Components: []discordgo.MessageComponent{
@@ -1,8 +1,10 @@
 package interactionService
 
 import (
+	"fmt"
 	"perfectOddsBot/services/betService"
 	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
 	cardSelection "perfectOddsBot/services/interactionService/cardSelection"
 	"strings"
 
@@ -21,56 +23,56 @@ func HandleComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
-	if strings.HasPrefix(customID, "resolve_bet_") {
-		err := ResolveBet(s, i, customID)
+	if strings.HasPrefix(customID, "resolve_apply_") {
+		err := ResolveBetApply(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "lock_bet_") {
-		err := LockBet(s, i, db, customID)
+	if strings.HasPrefix(customID, "resolve_cancel_") {
+		err := ResolveBetCancel(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "subscribe_to_team_next_") || strings.HasPrefix(customID, "subscribe_to_team_previous_") {
-		err := HandlePagination(s, i, db, customID)
+	if strings.HasPrefix(customID, "resolve_bet_") {
+		err := ResolveBet(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "subscribe_to_team_submit") {
-		err := HandleTeamSubscribeSubmit(s, i, db, customID)
+	if strings.HasPrefix(customID, "lock_bet_") {
+		err := LockBet(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "create_cbb_bet_next_") || strings.HasPrefix(customID, "create_cbb_bet_previous_") {
-		err := HandleCBBGamePagination(s, i, db, customID)
+	if strings.HasPrefix(customID, "void_bet_") {
+		err := VoidBet(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "create_cfb_bet_next_") || strings.HasPrefix(customID, "create_cfb_bet_previous_") {
-		err := HandleCFBGamePagination(s, i, db, customID)
+	if strings.HasPrefix(customID, "subscriptions_") {
+		err := HandleSubscriptionComponent(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
 		return
 	}
 
-	if strings.HasPrefix(customID, "create_cbb_bet_submit") {
-		err := HandleCBBGameSubmit(s, i, db, customID)
+	if strings.HasPrefix(customID, "create_cfb_bet_next_") || strings.HasPrefix(customID, "create_cfb_bet_previous_") {
+		err := HandleCFBGamePagination(s, i, db, customID)
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
@@ -85,14 +87,6 @@ func HandleComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
-	if strings.HasPrefix(customID, "create_cbb_bet_cancel_") {
-		err := HandleCBBGameCancel(s, i, db, customID)
-		if err != nil {
-			common.SendError(s, i, err, db)
-		}
-		return
-	}
-
 	if strings.HasPrefix(customID, "create_cfb_bet_cancel_") {
 		err := HandleCFBGameCancel(s, i, db, customID)
 		if err != nil {
@@ -101,8 +95,20 @@ func HandleComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
-	if strings.HasPrefix(customID, "cbb_bet_type_") {
-		err := HandleCBBBetTypeSelection(s, i, db, customID)
+	for _, sport := range extService.ESPNSports {
+		var err error
+		switch {
+		case strings.HasPrefix(customID, fmt.Sprintf("create_%s_bet_next_", sport.Key)) || strings.HasPrefix(customID, fmt.Sprintf("create_%s_bet_previous_", sport.Key)):
+			err = HandleESPNGamePagination(s, i, db, sport, customID)
+		case strings.HasPrefix(customID, fmt.Sprintf("create_%s_bet_submit", sport.Key)):
+			err = HandleESPNGameSubmit(s, i, db, sport, customID)
+		case strings.HasPrefix(customID, fmt.Sprintf("create_%s_bet_cancel_", sport.Key)):
+			err = HandleESPNGameCancel(s, i, db, sport, customID)
+		case strings.HasPrefix(customID, fmt.Sprintf("%s_bet_type_", sport.Key)):
+			err = HandleESPNBetTypeSelection(s, i, db, sport, customID)
+		default:
+			continue
+		}
 		if err != nil {
 			common.SendError(s, i, err, db)
 		}
@@ -141,6 +147,14 @@ func HandleComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
+	if strings.HasPrefix(customID, "cashout_") {
+		err := betService.HandleCashOut(s, i, db, customID)
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
 	if strings.HasPrefix(customID, "parlay_cancel_") {
 		err := betService.HandleParlayCancel(s, i, db, customID)
 		if err != nil {
@@ -241,6 +255,14 @@ func HandleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, db
 		return
 	}
 
+	if strings.HasPrefix(customID, "void_bet_confirm_") {
+		err := VoidBetConfirm(s, i, db, customID)
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
 	if strings.HasPrefix(customID, "submit_bet_") {
 		err := SubmitBet(s, i, db, customID)
 		if err != nil {
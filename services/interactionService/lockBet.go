@@ -57,7 +57,7 @@ func LockBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB,
 		Channel: i.ChannelID,
 		Components: &[]discordgo.MessageComponent{
 			discordgo.ActionsRow{
-				Components: []discordgo.MessageComponent{messageService.GetResolveButton(bet.ID)},
+				Components: []discordgo.MessageComponent{messageService.GetResolveButton(bet.ID), messageService.GetVoidButton(bet.ID)},
 			},
 		},
 	})
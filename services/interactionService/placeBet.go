@@ -5,6 +5,8 @@ import (
 	"math"
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/limitService"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -19,6 +21,14 @@ func PlaceBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB,
 		return err
 	}
 
+	if option == "select" {
+		values := i.MessageComponentData().Values
+		if len(values) == 0 {
+			return fmt.Errorf("no option selected for bet %d", betID)
+		}
+		option = fmt.Sprintf("option%s", values[0])
+	}
+
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: i.Member.User.ID, GuildID: i.GuildID})
 	if result.Error != nil {
@@ -39,6 +49,31 @@ func PlaceBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB,
 		})
 	}
 
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+	var side *limitService.Side
+	var optionVal int
+	if _, err := fmt.Sscanf(option, "option%d", &optionVal); err == nil {
+		side = &limitService.Side{BetID: betID, Option: optionVal}
+	}
+	// This only sizes the modal; SubmitBet checks the stake again under the
+	// user's lock before taking it.
+	maxAmount, reason, err := limitService.AllowedStake(db, *guild, user, side)
+	if err != nil {
+		return err
+	}
+	if maxAmount < 1 {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("🚫 Bet rejected. %s", reason),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
@@ -49,7 +84,7 @@ func PlaceBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB,
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "bet_amount",
-							Label:       fmt.Sprintf("Bet Amount (Max: %.0f)", math.Floor(user.Points)),
+							Label:       fmt.Sprintf("Bet Amount (Max: %.0f)", math.Floor(maxAmount)),
 							Style:       discordgo.TextInputShort,
 							Placeholder: "Enter amount",
 							Required:    true,
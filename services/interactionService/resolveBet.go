@@ -3,14 +3,16 @@ package interactionService
 import (
 	"errors"
 	"fmt"
+	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
 	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
 )
 
-func ResolveBet(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) error {
+func ResolveBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
 	betID, err := strconv.Atoi(strings.TrimPrefix(customID, "resolve_bet_"))
 	if err != nil {
 		return err
@@ -30,6 +32,22 @@ func ResolveBet(s *discordgo.Session, i *discordgo.InteractionCreate, customID s
 		return nil
 	}
 
+	var bet models.Bet
+	result := db.Preload("Options").First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID)
+	if result.Error != nil {
+		return errors.New(fmt.Sprintf("Error finding bet: %v", result.Error))
+	}
+
+	betOptions := common.GetBetOptions(bet)
+	var optionLabels []string
+	for _, option := range betOptions {
+		optionLabels = append(optionLabels, fmt.Sprintf("%d = %s", option.OptionNumber, option.Name))
+	}
+	placeholder := strings.Join(optionLabels, ", ")
+	if len(placeholder) > 100 {
+		placeholder = placeholder[:97] + "..."
+	}
+
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
@@ -40,9 +58,9 @@ func ResolveBet(s *discordgo.Session, i *discordgo.InteractionCreate, customID s
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "winning_option",
-							Label:       "Enter Winning Option (1 or 2)",
+							Label:       fmt.Sprintf("Enter Winning Option (1-%d, 0 = push)", len(betOptions)),
 							Style:       discordgo.TextInputShort,
-							Placeholder: "1 or 2",
+							Placeholder: placeholder,
 							Required:    true,
 						},
 					},
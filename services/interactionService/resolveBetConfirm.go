@@ -7,10 +7,14 @@ import (
 	"gorm.io/gorm"
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/betService"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/messageService"
 	"strconv"
 	"strings"
 )
 
+// ResolveBetConfirm handles the winning-option modal. It shows the admin a
+// dry-run of the settlement; nothing is paid until they confirm it.
 func ResolveBetConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
 	betIDStr := strings.TrimPrefix(customID, "resolve_bet_confirm_")
 	betID, err := strconv.Atoi(betIDStr)
@@ -19,20 +23,119 @@ func ResolveBetConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, db
 	}
 
 	selectedOption := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
-	winningOption, err := strconv.Atoi(selectedOption)
+	winningOption, err := strconv.Atoi(strings.TrimSpace(selectedOption))
 	if err != nil {
 		return errors.New(fmt.Sprintf("Error parsing selected option: %v", err))
 	}
 
-	betService.ResolveBetByID(s, i, betID, winningOption, db)
+	var bet models.Bet
+	result := db.Preload("Options").First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID)
+	if result.Error == nil && winningOption != betService.PushOption && !common.IsValidBetOption(bet, winningOption) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("Invalid winning option. Enter a number between 1 and %d, or 0 to push the bet.", len(common.GetBetOptions(bet))),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending invalid option message: %v", err))
+		}
+		return nil
+	}
 
-	_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-		ID:         i.Message.ID,
-		Channel:    i.ChannelID,
-		Components: &[]discordgo.MessageComponent{},
+	var preview *betService.Settlement
+	if result.Error == nil {
+		preview, err = betService.PreviewSettlement(s, db, bet.ID, betService.ManualResult(winningOption))
+		if err != nil && !errors.Is(err, betService.ErrBetAlreadySettled) {
+			return errors.New(fmt.Sprintf("Error previewing settlement: %v", err))
+		}
+	}
+	if preview == nil {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "Bet not found or already resolved.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending bet not found message: %v", err))
+		}
+		return nil
+	}
+
+	embed := messageService.BuildSettlementPreviewEmbed(
+		preview.Bet.Description,
+		betService.ResultSubtitle(preview.Bet, winningOption),
+		preview.TotalPayout,
+		preview.PoolDelta,
+		preview.BasePayouts,
+		preview.Winners,
+		preview.Losers,
+		preview.Pushes,
+		preview.CardsPlayed,
+		preview.ParlayLegs,
+	)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: messageService.GetResolvePreviewButtons(bet.ID, winningOption),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
 	})
 	if err != nil {
-		return errors.New(fmt.Sprintf("Error removing buttons from the message: %v", err))
+		return errors.New(fmt.Sprintf("Error sending settlement preview: %v", err))
+	}
+	return nil
+}
+
+// ResolveBetApply settles a bet once the admin has confirmed the preview.
+func ResolveBetApply(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	parts := strings.Split(strings.TrimPrefix(customID, "resolve_apply_"), "_")
+	if len(parts) != 2 {
+		return errors.New(fmt.Sprintf("Invalid resolve custom ID: %s", customID))
+	}
+	betID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error parsing bet ID: %v", err))
+	}
+	winningOption, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error parsing selected option: %v", err))
+	}
+
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending unauthorized message: %v", err))
+		}
+		return nil
+	}
+
+	betService.ResolveBetByID(s, i, betID, winningOption, db)
+
+	var bet models.Bet
+	if err := db.First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID).Error; err != nil || !bet.Paid {
+		return nil
+	}
+
+	if bet.MessageID != nil {
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         *bet.MessageID,
+			Channel:    bet.ChannelID,
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error removing buttons from the message: %v", err))
+		}
 	}
 
 	var secondaryMsgs []models.BetMessage
@@ -58,3 +161,19 @@ func ResolveBetConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, db
 
 	return nil
 }
+
+// ResolveBetCancel dismisses a settlement preview without resolving the bet.
+func ResolveBetCancel(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    "Resolution cancelled. Nothing was paid out.",
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error cancelling resolution: %v", err))
+	}
+	return nil
+}
@@ -6,12 +6,20 @@ import (
 	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
+	"perfectOddsBot/services/limitService"
 	"strconv"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errBetClosed is returned from SubmitBet's transaction when the bet stopped
+// taking entries after it was first checked.
+var errBetClosed = errors.New("bet closed")
+
 func SubmitBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
 	var betID uint
 	var option string
@@ -25,8 +33,10 @@ func SubmitBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 	_, err = fmt.Sscanf(customID, "submit_bet_%d_%s", &betID, &option)
 	if err != nil {
 		return errors.New(fmt.Sprintf("Error parsing modal customID for placing a bet: %v", err))
-	} else {
-		_, err = fmt.Sscanf(option, "option%d", &optionVal)
+	}
+	_, err = fmt.Sscanf(option, "option%d", &optionVal)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error parsing bet option: %v", err))
 	}
 
 	amountStr := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
@@ -52,16 +62,14 @@ func SubmitBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: userID, GuildID: guildID})
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			return errors.New(fmt.Sprintf("Error setting starting points: %v", err))
+		}
 	}
 
 	username := common.GetUsernameFromUser(i.Member.User)
 	common.UpdateUserUsername(db, &user, username)
 
-	if result.RowsAffected == 1 {
-		db.Save(&user)
-	}
-
 	if user.Points < float64(amount) {
 		response := "You do not have enough points to place this bet."
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -77,14 +85,11 @@ func SubmitBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return nil
 	}
 
-	var bet models.Bet
-	result = db.First(&bet, "id = ? AND guild_id = ? AND active = ?", betID, guildID, true)
-	if result.Error != nil || bet.ID == 0 {
-		response := "This bet is closed."
+	closed := func() error {
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: response,
+				Content: "This bet is closed.",
 				Flags:   discordgo.MessageFlagsEphemeral,
 			},
 		})
@@ -94,24 +99,94 @@ func SubmitBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return nil
 	}
 
-	betEntry := models.BetEntry{
-		UserID: user.ID,
-		BetID:  betID,
-		Option: optionVal,
-		Amount: amount,
+	var bet models.Bet
+	result = db.Preload("Options").First(&bet, "id = ? AND guild_id = ? AND active = ?", betID, guildID, true)
+	if result.Error != nil || bet.ID == 0 || !common.BetOpen(bet, time.Now()) {
+		return closed()
+	}
+
+	if !common.IsValidBetOption(bet, optionVal) {
+		return errors.New(fmt.Sprintf("Invalid option %d for bet %d", optionVal, bet.ID))
+	}
+
+	side := &limitService.Side{BetID: bet.ID, Option: optionVal}
+	rejected := func(limitErr *limitService.LimitError) error {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("🚫 Bet rejected. %s", limitErr.Reason),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending message: %v", err))
+		}
+		return nil
+	}
+	err = limitService.CheckStake(db, *guild, user, float64(amount), side)
+	var limitErr *limitService.LimitError
+	if errors.As(err, &limitErr) {
+		return rejected(limitErr)
 	}
-	if bet.Spread != nil {
-		betEntry.Spread = bet.Spread
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error checking bet limits: %v", err))
 	}
-	db.Create(&betEntry)
 
-	user.Points -= float64(amount)
-	db.Save(&user)
+	// The bet and the user are locked and checked again, as the bet may have
+	// closed or settled and another bet may have been placed since the checks
+	// above. The entry takes its line from the locked bet.
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Options").First(&bet, "id = ? AND guild_id = ?", betID, guildID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errBetClosed
+			}
+			return err
+		}
+		if !common.BetOpen(bet, time.Now()) {
+			return errBetClosed
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, user.ID).Error; err != nil {
+			return err
+		}
+		if user.Points < float64(amount) {
+			return &limitService.LimitError{Reason: "You do not have enough points to place this bet."}
+		}
+		if err := limitService.CheckStake(tx, *guild, user, float64(amount), side); err != nil {
+			return err
+		}
 
-	optionName := bet.Option1
-	if optionVal == 2 {
-		optionName = bet.Option2
+		odds := common.GetOddsFromBet(bet, optionVal)
+		betEntry := models.BetEntry{
+			UserID: user.ID,
+			BetID:  bet.ID,
+			Option: optionVal,
+			Amount: amount,
+			Odds:   &odds,
+			Spread: bet.Spread,
+			Total:  bet.Total,
+		}
+		if err := tx.Create(&betEntry).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BetOption{}).
+			Where("bet_id = ? AND option_number = ?", bet.ID, optionVal).
+			UpdateColumn("bet_count", gorm.Expr("bet_count + 1")).Error; err != nil {
+			return err
+		}
+		return ledgerService.Apply(tx, &user, -float64(amount), ledgerService.Bet(ledgerService.ReasonBetPlaced, bet.ID))
+	})
+	if errors.Is(err, errBetClosed) {
+		return closed()
+	}
+	if errors.As(err, &limitErr) {
+		return rejected(limitErr)
 	}
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error placing bet: %v", err))
+	}
+
+	optionName := common.GetOptionName(bet, optionVal)
 
 	potentialPayout := common.CalculatePayout(amount, optionVal, bet)
 
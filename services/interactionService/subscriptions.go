@@ -0,0 +1,552 @@
+package interactionService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"perfectOddsBot/services/guildService"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// MaxGuildSubscriptions keeps every subscription removable from one select menu.
+const MaxGuildSubscriptions = 25
+
+// subscriptionDraft is a subscription being added through /manage-subscriptions.
+type subscriptionDraft struct {
+	Kind        string
+	Sport       string
+	Target      string
+	ChannelID   string
+	BetType     string
+	HoursBefore int
+	Page        int
+	// Targets are the pages of teams or conferences to pick from.
+	Targets [][]discordgo.SelectMenuOption
+}
+
+var (
+	subscriptionDrafts   = make(map[string]*subscriptionDraft)
+	subscriptionDraftsMu sync.Mutex
+)
+
+// subscriptionSports are the sports a guild can subscribe to.
+var subscriptionSports = []struct {
+	Key  string
+	Name string
+}{
+	{models.SportCFB, "College Football"},
+	{models.SportCBB, "College Basketball"},
+}
+
+var subscriptionBetTypes = []struct {
+	Key  string
+	Name string
+}{
+	{models.BetTypeSpread, "ATS"},
+	{models.BetTypeMoneyline, "Moneyline"},
+	{models.BetTypeTotal, "Over/Under"},
+}
+
+var subscriptionWindows = []int{0, models.HoursBeforeGameDay, 48, 24, 12, 6, 2}
+
+func subscriptionDraftKey(i *discordgo.InteractionCreate) string {
+	return fmt.Sprintf("%s:%s", i.GuildID, i.Member.User.ID)
+}
+
+// ManageSubscriptions shows the guild's subscriptions with controls to add and
+// remove them.
+func ManageSubscriptions(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		return
+	}
+
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+	if !guild.PremiumEnabled {
+		common.SendError(s, i, fmt.Errorf("Your server must have the premium subscription in order to enable this feature"), db)
+		return
+	}
+
+	data, err := subscriptionListView(db, guild, "")
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+	data.Flags = discordgo.MessageFlagsEphemeral
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
+
+// HandleSubscriptionComponent handles the buttons and menus of the
+// /manage-subscriptions message.
+func HandleSubscriptionComponent(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	if !common.IsAdmin(s, i) {
+		return fmt.Errorf("You are not authorized to manage subscriptions.")
+	}
+
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	key := subscriptionDraftKey(i)
+	subscriptionDraftsMu.Lock()
+	draft := subscriptionDrafts[key]
+	subscriptionDraftsMu.Unlock()
+
+	var data *discordgo.InteractionResponseData
+	switch {
+	case strings.HasPrefix(customID, "subscriptions_remove"):
+		data, err = removeSubscription(db, guild, i.MessageComponentData().Values)
+	case strings.HasPrefix(customID, "subscriptions_add_"):
+		draft = &subscriptionDraft{
+			Kind:    strings.TrimPrefix(customID, "subscriptions_add_"),
+			Sport:   models.SportCFB,
+			BetType: models.BetTypeSpread,
+		}
+		if err = loadSubscriptionTargets(draft); err == nil {
+			subscriptionDraftsMu.Lock()
+			subscriptionDrafts[key] = draft
+			subscriptionDraftsMu.Unlock()
+			data = subscriptionTargetView(draft, "")
+		}
+	case strings.HasPrefix(customID, "subscriptions_cancel"):
+		subscriptionDraftsMu.Lock()
+		delete(subscriptionDrafts, key)
+		subscriptionDraftsMu.Unlock()
+		data, err = subscriptionListView(db, guild, "")
+	case draft == nil:
+		data, err = subscriptionListView(db, guild, "That subscription has expired. Start again below.")
+	case strings.HasPrefix(customID, "subscriptions_sport"):
+		draft.Sport = i.MessageComponentData().Values[0]
+		data = subscriptionTargetView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_target_previous"):
+		draft.Page = max(draft.Page-1, 0)
+		data = subscriptionTargetView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_target_next"):
+		draft.Page = min(draft.Page+1, len(draft.Targets)-1)
+		data = subscriptionTargetView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_target"):
+		draft.Target = i.MessageComponentData().Values[0]
+		data = subscriptionTargetView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_settings"):
+		if draft.Kind != models.SubscriptionRanked && draft.Target == "" {
+			data = subscriptionTargetView(draft, "Pick a team or conference first.")
+		} else {
+			data = subscriptionSettingsView(draft, "")
+		}
+	case strings.HasPrefix(customID, "subscriptions_back"):
+		data = subscriptionTargetView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_channel"):
+		draft.ChannelID = ""
+		if values := i.MessageComponentData().Values; len(values) > 0 {
+			draft.ChannelID = values[0]
+		}
+		data = subscriptionSettingsView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_bet_type"):
+		draft.BetType = i.MessageComponentData().Values[0]
+		data = subscriptionSettingsView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_hours"):
+		draft.HoursBefore, _ = strconv.Atoi(i.MessageComponentData().Values[0])
+		data = subscriptionSettingsView(draft, "")
+	case strings.HasPrefix(customID, "subscriptions_save"):
+		data, err = saveSubscription(db, guild, draft)
+		if err == nil {
+			subscriptionDraftsMu.Lock()
+			delete(subscriptionDrafts, key)
+			subscriptionDraftsMu.Unlock()
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	})
+}
+
+func removeSubscription(db *gorm.DB, guild *models.Guild, values []string) (*discordgo.InteractionResponseData, error) {
+	if len(values) == 0 {
+		return subscriptionListView(db, guild, "")
+	}
+	result := db.Where("id = ? AND guild_id = ?", values[0], guild.GuildID).Delete(&models.GuildSubscription{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subscriptionListView(db, guild, "Subscription removed.")
+}
+
+func saveSubscription(db *gorm.DB, guild *models.Guild, draft *subscriptionDraft) (*discordgo.InteractionResponseData, error) {
+	var count int64
+	if err := db.Model(&models.GuildSubscription{}).Where("guild_id = ?", guild.GuildID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= MaxGuildSubscriptions {
+		return subscriptionSettingsView(draft, fmt.Sprintf("This server already has the maximum of %d subscriptions. Remove one first.", MaxGuildSubscriptions)), nil
+	}
+
+	var existing int64
+	err := db.Model(&models.GuildSubscription{}).
+		Where("guild_id = ? AND sport = ? AND kind = ? AND target = ? AND bet_type = ?", guild.GuildID, draft.Sport, draft.Kind, draft.Target, draft.BetType).
+		Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return subscriptionSettingsView(draft, "This server already has that subscription."), nil
+	}
+
+	subscription := models.GuildSubscription{
+		GuildID:     guild.GuildID,
+		Sport:       draft.Sport,
+		Kind:        draft.Kind,
+		Target:      draft.Target,
+		ChannelID:   draft.ChannelID,
+		BetType:     draft.BetType,
+		HoursBefore: draft.HoursBefore,
+	}
+	if err := db.Create(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscriptionListView(db, guild, fmt.Sprintf("Subscribed to %s.", describeSubscriptionTarget(subscription)))
+}
+
+// loadSubscriptionTargets fills in the teams or conferences a draft can pick
+// from.
+func loadSubscriptionTargets(draft *subscriptionDraft) error {
+	if draft.Kind == models.SubscriptionRanked {
+		return nil
+	}
+	if draft.Kind != models.SubscriptionTeam && draft.Kind != models.SubscriptionConference {
+		return fmt.Errorf("unknown subscription kind %q", draft.Kind)
+	}
+
+	teamList, err := extService.GetTeamList()
+	if err != nil {
+		return err
+	}
+
+	var options []discordgo.SelectMenuOption
+	if draft.Kind == models.SubscriptionTeam {
+		for _, team := range teamList {
+			options = append(options, discordgo.SelectMenuOption{
+				Label:       team.Name,
+				Value:       team.Name,
+				Description: fmt.Sprintf("%s %s", team.Name, team.Mascot),
+			})
+		}
+	} else {
+		seen := make(map[string]bool)
+		var conferences []string
+		for _, team := range teamList {
+			if team.Conference == "" || seen[team.Conference] {
+				continue
+			}
+			seen[team.Conference] = true
+			conferences = append(conferences, team.Conference)
+		}
+		sort.Strings(conferences)
+		for _, conference := range conferences {
+			options = append(options, discordgo.SelectMenuOption{
+				Label: conference,
+				Value: conference,
+			})
+		}
+	}
+
+	for start := 0; start < len(options); start += 25 {
+		end := min(start+25, len(options))
+		draft.Targets = append(draft.Targets, options[start:end])
+	}
+	return nil
+}
+
+func subscriptionListView(db *gorm.DB, guild *models.Guild, notice string) (*discordgo.InteractionResponseData, error) {
+	var subscriptions []models.GuildSubscription
+	if err := db.Where("guild_id = ?", guild.GuildID).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	description := "This server has no subscriptions. Add one below to have bets created automatically."
+	var removeOptions []discordgo.SelectMenuOption
+	if len(subscriptions) > 0 {
+		var lines []string
+		for idx, subscription := range subscriptions {
+			lines = append(lines, fmt.Sprintf("%d. %s", idx+1, describeSubscription(subscription, guild)))
+			removeOptions = append(removeOptions, discordgo.SelectMenuOption{
+				Label: fmt.Sprintf("%d. %s", idx+1, describeSubscriptionTarget(subscription)),
+				Value: strconv.Itoa(int(subscription.ID)),
+			})
+		}
+		description = strings.Join(lines, "\n")
+	}
+
+	var components []discordgo.MessageComponent
+	if len(removeOptions) > 0 {
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    "subscriptions_remove",
+					Placeholder: "Remove a subscription",
+					Options:     removeOptions,
+				},
+			},
+		})
+	}
+	components = append(components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Add Team", CustomID: "subscriptions_add_team", Style: discordgo.PrimaryButton},
+			discordgo.Button{Label: "Add Conference", CustomID: "subscriptions_add_conference", Style: discordgo.PrimaryButton},
+			discordgo.Button{Label: "Add Ranked Matchups", CustomID: "subscriptions_add_ranked", Style: discordgo.PrimaryButton},
+		},
+	})
+
+	return &discordgo.InteractionResponseData{
+		Content: notice,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "📬 Subscriptions",
+				Description: description,
+				Color:       0x3498db,
+			},
+		},
+		Components: components,
+	}, nil
+}
+
+func subscriptionTargetView(draft *subscriptionDraft, notice string) *discordgo.InteractionResponseData {
+	var sportOptions []discordgo.SelectMenuOption
+	for _, sport := range subscriptionSports {
+		sportOptions = append(sportOptions, discordgo.SelectMenuOption{
+			Label:   sport.Name,
+			Value:   sport.Key,
+			Default: sport.Key == draft.Sport,
+		})
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    "subscriptions_sport",
+					Placeholder: "Sport",
+					Options:     sportOptions,
+				},
+			},
+		},
+	}
+
+	navigation := []discordgo.MessageComponent{}
+	if len(draft.Targets) > 0 {
+		page := draft.Targets[draft.Page]
+		targetOptions := make([]discordgo.SelectMenuOption, len(page))
+		copy(targetOptions, page)
+		for idx := range targetOptions {
+			targetOptions[idx].Default = targetOptions[idx].Value == draft.Target
+		}
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    "subscriptions_target",
+					Placeholder: fmt.Sprintf("Select a %s (Page %d/%d)", draft.Kind, draft.Page+1, len(draft.Targets)),
+					Options:     targetOptions,
+				},
+			},
+		})
+		if len(draft.Targets) > 1 {
+			navigation = append(navigation,
+				discordgo.Button{Label: "Previous", CustomID: "subscriptions_target_previous", Style: discordgo.SecondaryButton, Disabled: draft.Page == 0},
+				discordgo.Button{Label: "Next", CustomID: "subscriptions_target_next", Style: discordgo.SecondaryButton, Disabled: draft.Page == len(draft.Targets)-1},
+			)
+		}
+	}
+	navigation = append(navigation,
+		discordgo.Button{Label: "Continue", CustomID: "subscriptions_settings", Style: discordgo.PrimaryButton},
+		discordgo.Button{Label: "Cancel", CustomID: "subscriptions_cancel", Style: discordgo.DangerButton},
+	)
+	components = append(components, discordgo.ActionsRow{Components: navigation})
+
+	return &discordgo.InteractionResponseData{
+		Content: notice,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "📬 Add Subscription",
+				Description: describeDraft(draft),
+				Color:       0x3498db,
+			},
+		},
+		Components: components,
+	}
+}
+
+func subscriptionSettingsView(draft *subscriptionDraft, notice string) *discordgo.InteractionResponseData {
+	var betTypeOptions []discordgo.SelectMenuOption
+	for _, betType := range subscriptionBetTypes {
+		betTypeOptions = append(betTypeOptions, discordgo.SelectMenuOption{
+			Label:   betType.Name,
+			Value:   betType.Key,
+			Default: betType.Key == draft.BetType,
+		})
+	}
+
+	var windowOptions []discordgo.SelectMenuOption
+	for _, hours := range subscriptionWindows {
+		windowOptions = append(windowOptions, discordgo.SelectMenuOption{
+			Label:   describeWindow(hours),
+			Value:   strconv.Itoa(hours),
+			Default: hours == draft.HoursBefore,
+		})
+	}
+
+	minValues := 0
+	channelMenu := discordgo.SelectMenu{
+		MenuType:     discordgo.ChannelSelectMenu,
+		CustomID:     "subscriptions_channel",
+		Placeholder:  "Post in the server's bet channel",
+		MinValues:    &minValues,
+		MaxValues:    1,
+		ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+	}
+	if draft.ChannelID != "" {
+		channelMenu.DefaultValues = []discordgo.SelectMenuDefaultValue{{ID: draft.ChannelID, Type: discordgo.SelectMenuDefaultValueChannel}}
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: notice,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "📬 Add Subscription",
+				Description: describeDraft(draft),
+				Color:       0x3498db,
+			},
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{channelMenu}},
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.SelectMenu{
+						MenuType:    discordgo.StringSelectMenu,
+						CustomID:    "subscriptions_bet_type",
+						Placeholder: "Bet type",
+						Options:     betTypeOptions,
+					},
+				},
+			},
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.SelectMenu{
+						MenuType:    discordgo.StringSelectMenu,
+						CustomID:    "subscriptions_hours",
+						Placeholder: "When to post",
+						Options:     windowOptions,
+					},
+				},
+			},
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "Back", CustomID: "subscriptions_back", Style: discordgo.SecondaryButton},
+					discordgo.Button{Label: "Save", CustomID: "subscriptions_save", Style: discordgo.SuccessButton},
+					discordgo.Button{Label: "Cancel", CustomID: "subscriptions_cancel", Style: discordgo.DangerButton},
+				},
+			},
+		},
+	}
+}
+
+func sportName(key string) string {
+	for _, sport := range subscriptionSports {
+		if sport.Key == key {
+			return sport.Name
+		}
+	}
+	return strings.ToUpper(key)
+}
+
+func betTypeName(key string) string {
+	for _, betType := range subscriptionBetTypes {
+		if betType.Key == key {
+			return betType.Name
+		}
+	}
+	return "ATS"
+}
+
+func describeWindow(hours int) string {
+	switch hours {
+	case 0:
+		return "As soon as lines are posted"
+	case models.HoursBeforeGameDay:
+		return fmt.Sprintf("Morning of game day (%d am server time)", models.GameDayPostHour)
+	}
+	return fmt.Sprintf("%d hours before kickoff", hours)
+}
+
+func describeSubscriptionTarget(subscription models.GuildSubscription) string {
+	target := "Ranked matchups"
+	if subscription.Kind != models.SubscriptionRanked {
+		target = subscription.Target
+	}
+	return fmt.Sprintf("%s (%s %s)", target, sportName(subscription.Sport), betTypeName(subscription.BetType))
+}
+
+func describeSubscription(subscription models.GuildSubscription, guild *models.Guild) string {
+	channel := "bet channel"
+	if subscription.ChannelID != "" {
+		channel = fmt.Sprintf("<#%s>", subscription.ChannelID)
+	} else if guild.BetChannelID != "" {
+		channel = fmt.Sprintf("<#%s>", guild.BetChannelID)
+	}
+	return fmt.Sprintf("**%s** in %s, %s", describeSubscriptionTarget(subscription), channel, strings.ToLower(describeWindow(subscription.HoursBefore)))
+}
+
+func describeDraft(draft *subscriptionDraft) string {
+	target := draft.Target
+	switch {
+	case draft.Kind == models.SubscriptionRanked:
+		target = "Every game between two ranked teams"
+	case target == "":
+		target = fmt.Sprintf("_Select a %s_", draft.Kind)
+	}
+
+	channel := "Server's bet channel"
+	if draft.ChannelID != "" {
+		channel = fmt.Sprintf("<#%s>", draft.ChannelID)
+	}
+
+	return fmt.Sprintf("**Sport:** %s\n**Games:** %s\n**Channel:** %s\n**Bet type:** %s\n**Posted:** %s",
+		sportName(draft.Sport), target, channel, betTypeName(draft.BetType), describeWindow(draft.HoursBefore))
+}
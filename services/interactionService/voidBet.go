@@ -0,0 +1,117 @@
+package interactionService
+
+import (
+	"errors"
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/betService"
+	"perfectOddsBot/services/common"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+func VoidBet(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	betID, err := strconv.Atoi(strings.TrimPrefix(customID, "void_bet_"))
+	if err != nil {
+		return err
+	}
+
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending unauthorized message: %v", err))
+		}
+		return nil
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			Title:    "Void Bet",
+			CustomID: fmt.Sprintf("void_bet_confirm_%d", betID),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "void_reason",
+							Label:       "Reason (all stakes will be refunded)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "e.g. Game postponed",
+							Required:    false,
+							MaxLength:   200,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func VoidBetConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB, customID string) error {
+	betID, err := strconv.Atoi(strings.TrimPrefix(customID, "void_bet_confirm_"))
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error parsing bet ID: %v", err))
+	}
+
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending unauthorized message: %v", err))
+		}
+		return nil
+	}
+
+	reason := strings.TrimSpace(i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value)
+
+	var bet models.Bet
+	result := db.First(&bet, "id = ? AND guild_id = ?", betID, i.GuildID)
+	if result.Error != nil || bet.Paid {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "This bet has already been resolved or no longer exists.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error sending bet not found message: %v", err))
+		}
+		return nil
+	}
+
+	embed, err := betService.VoidBet(s, db, bet, reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error sending bet voided message: %v", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package ledgerService
+
+import (
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reason says why a user's points moved.
+type Reason string
+
+const (
+	ReasonStartingBalance    Reason = "starting_balance"
+	ReasonMessage            Reason = "message"
+	ReasonReaction           Reason = "reaction"
+	ReasonAdminGrant         Reason = "admin_grant"
+	ReasonAdminReset         Reason = "admin_reset"
+	ReasonBetPlaced          Reason = "bet_placed"
+	ReasonBetPayout          Reason = "bet_payout"
+	ReasonBetRefund          Reason = "bet_refund"
+	ReasonParlayPlaced       Reason = "parlay_placed"
+	ReasonParlayPayout       Reason = "parlay_payout"
+	ReasonParlayRefund       Reason = "parlay_refund"
+	ReasonBetCashOut         Reason = "bet_cash_out"
+	ReasonParlayCashOut      Reason = "parlay_cash_out"
+	ReasonSettlementReversal Reason = "settlement_reversal"
+	ReasonCardDraw           Reason = "card_draw"
+	ReasonCardPurchase       Reason = "card_purchase"
+	ReasonCardEffect         Reason = "card_effect"
+	ReasonCardRoyalty        Reason = "card_royalty"
+	ReasonScheduledJob       Reason = "scheduled_job"
+	ReasonMigration          Reason = "migration"
+)
+
+// Entry describes a points movement: the reason and whatever caused it.
+type Entry struct {
+	Reason   Reason
+	BetID    uint
+	ParlayID uint
+	CardID   uint
+	Job      string
+	Note     string
+}
+
+// Bet is an entry caused by a bet.
+func Bet(reason Reason, betID uint) Entry {
+	return Entry{Reason: reason, BetID: betID}
+}
+
+// Parlay is an entry caused by a parlay.
+func Parlay(reason Reason, parlayID uint) Entry {
+	return Entry{Reason: reason, ParlayID: parlayID}
+}
+
+// Card is an entry caused by a card's effect.
+func Card(cardID uint) Entry {
+	return Entry{Reason: ReasonCardEffect, CardID: cardID}
+}
+
+// Job is an entry made by a scheduled job, such as the Loan Shark collection.
+func Job(job string, cardID uint) Entry {
+	return Entry{Reason: ReasonScheduledJob, Job: job, CardID: cardID}
+}
+
+// WithBet returns a copy of the entry that also references a bet.
+func (e Entry) WithBet(betID uint) Entry {
+	e.BetID = betID
+	return e
+}
+
+// Apply moves a user's balance by delta and records the movement. The user's
+// row is locked and the balance and its ledger row are written in one
+// transaction, db's own when it is one. delta is rounded to minor units, and
+// user.Points is set to the balance after the movement.
+func Apply(db *gorm.DB, user *models.User, delta float64, entry Entry) error {
+	delta = common.RoundPoints(delta)
+	if delta == 0 {
+		return nil
+	}
+	_, err := move(db, user, func(float64) float64 { return delta }, entry)
+	return err
+}
+
+// ApplyFloored is Apply for a movement that may not take the balance below
+// zero. It returns the delta that was actually applied.
+func ApplyFloored(db *gorm.DB, user *models.User, delta float64, entry Entry) (float64, error) {
+	return move(db, user, func(balance float64) float64 {
+		if balance+delta < 0 {
+			return -balance
+		}
+		return delta
+	}, entry)
+}
+
+// ApplyMany moves the balances of users by what delta returns for each user's
+// balance before the movement. The rows are locked in ID order, and users are
+// updated to match.
+func ApplyMany(db *gorm.DB, users []models.User, delta func(before float64) float64, entry Entry) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(users))
+	for idx, user := range users {
+		ids[idx] = user.ID
+	}
+	return transaction(db, func(tx *gorm.DB) error {
+		var locked []models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "points").Where("id IN ?", ids).Order("id").Find(&locked).Error; err != nil {
+			return err
+		}
+		balances := make(map[uint]float64, len(locked))
+		for _, user := range locked {
+			balances[user.ID] = user.Points
+		}
+
+		for idx := range users {
+			before, found := balances[users[idx].ID]
+			if !found {
+				continue
+			}
+			d := common.RoundPoints(delta(before))
+			users[idx].Points = before
+			if d == 0 {
+				continue
+			}
+			if err := write(tx, &users[idx], d, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetBalance sets a user's balance outright and records the difference.
+func SetBalance(db *gorm.DB, user *models.User, balance float64, entry Entry) error {
+	balance = common.RoundPoints(balance)
+	_, err := move(db, user, func(before float64) float64 { return balance - before }, entry)
+	return err
+}
+
+// SetStartingBalance gives a newly created user the guild's starting points.
+func SetStartingBalance(db *gorm.DB, user *models.User, startingPoints float64) error {
+	return SetBalance(db, user, startingPoints, Entry{Reason: ReasonStartingBalance})
+}
+
+// SetGuildBalances sets every user in a guild to the same balance, recording a
+// row for each user whose balance changed.
+func SetGuildBalances(db *gorm.DB, guildID string, balance float64, entry Entry) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var users []models.User
+		if err := tx.Where("guild_id = ? AND points <> ?", guildID, balance).Find(&users).Error; err != nil {
+			return err
+		}
+		for idx := range users {
+			if err := SetBalance(tx, &users[idx], balance, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// History returns a user's most recent ledger rows, newest first.
+func History(db *gorm.DB, userID uint, limit int) ([]models.PointsLedgerEntry, error) {
+	var rows []models.PointsLedgerEntry
+	err := db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&rows).Error
+	return rows, err
+}
+
+// transaction runs fn in db's transaction when it is already in one, and in a
+// new one otherwise, so a balance never commits without its ledger row.
+func transaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if committer, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok && committer != nil {
+		return fn(db)
+	}
+	return db.Transaction(fn)
+}
+
+// move locks a user's row and moves their balance by what delta returns for
+// it. It returns the delta that was applied.
+func move(db *gorm.DB, user *models.User, delta func(balance float64) float64, entry Entry) (float64, error) {
+	var applied float64
+	err := transaction(db, func(tx *gorm.DB) error {
+		var locked models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "points").First(&locked, user.ID).Error; err != nil {
+			return err
+		}
+		user.Points = locked.Points
+		applied = common.RoundPoints(delta(locked.Points))
+		if applied == 0 {
+			return nil
+		}
+		return write(tx, user, applied, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return applied, nil
+}
+
+// write moves a locked user's balance from user.Points by delta and records
+// the movement with the balance it leaves.
+func write(tx *gorm.DB, user *models.User, delta float64, entry Entry) error {
+	balance := common.RoundPoints(user.Points + delta)
+	if err := tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("points", balance).Error; err != nil {
+		return err
+	}
+	user.Points = balance
+	return record(tx, *user, delta, entry)
+}
+
+func record(db *gorm.DB, user models.User, delta float64, entry Entry) error {
+	row := models.PointsLedgerEntry{
+		UserID:       user.ID,
+		GuildID:      user.GuildID,
+		Delta:        delta,
+		BalanceAfter: user.Points,
+		Reason:       string(entry.Reason),
+		BetID:        optionalID(entry.BetID),
+		ParlayID:     optionalID(entry.ParlayID),
+		CardID:       optionalID(entry.CardID),
+		Job:          entry.Job,
+		Note:         entry.Note,
+	}
+	return db.Create(&row).Error
+}
+
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,434 @@
+package ledgerService
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open gorm db: %v", err)
+	}
+
+	return gormDB, mock
+}
+
+func expectLockedBalance(mock sqlmock.Sqlmock, userID uint, points float64) {
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE `users`.`id` = \\? .* FOR UPDATE").
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(userID, points))
+}
+
+func TestApply(t *testing.T) {
+	t.Run("moves the locked balance and records the movement", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		user := models.User{DiscordID: "user1", GuildID: "guild1", Points: 100}
+		user.ID = 7
+
+		mock.ExpectBegin()
+		expectLockedBalance(mock, 7, 100)
+		mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+			WithArgs(60.0, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WithArgs(sqlmock.AnyArg(), 7, "guild1", -40.0, 60.0, string(ReasonBetPlaced), 12, nil, nil, "", "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		if err := Apply(db, &user, -40, Bet(ReasonBetPlaced, 12)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.Points != 60 {
+			t.Errorf("expected user.Points to be 60, got %.1f", user.Points)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("records the balance from the locked row, not a stale read", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		user := models.User{GuildID: "guild1", Points: 100}
+		user.ID = 7
+
+		mock.ExpectBegin()
+		expectLockedBalance(mock, 7, 150)
+		mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+			WithArgs(110.0, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WithArgs(sqlmock.AnyArg(), 7, "guild1", -40.0, 110.0, string(ReasonBetPlaced), 12, nil, nil, "", "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		if err := Apply(db, &user, -40, Bet(ReasonBetPlaced, 12)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.Points != 110 {
+			t.Errorf("expected user.Points to be 110, got %.1f", user.Points)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("rounds the delta to minor units", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		user := models.User{GuildID: "guild1", Points: 10.1}
+		user.ID = 4
+
+		mock.ExpectBegin()
+		expectLockedBalance(mock, 4, 10.1)
+		mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+			WithArgs(10.3, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WithArgs(sqlmock.AnyArg(), 4, "guild1", 0.2, 10.3, string(ReasonCardEffect), nil, nil, 9, "", "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		if err := Apply(db, &user, 0.1+0.1+0.0001, Card(9)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.Points != 10.3 {
+			t.Errorf("expected user.Points to be 10.3, got %v", user.Points)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("joins the caller's transaction", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		user := models.User{GuildID: "guild1", Points: 20}
+		user.ID = 5
+
+		mock.ExpectBegin()
+		expectLockedBalance(mock, 5, 20)
+		mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+			WithArgs(25.0, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return Apply(tx, &user, 5, Entry{Reason: ReasonMessage})
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("zero delta writes nothing", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		user := models.User{Points: 100}
+
+		if err := Apply(db, &user, 0, Card(1)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+}
+
+func TestApplyFloored(t *testing.T) {
+	db, mock := newMockDB(t)
+	user := models.User{GuildID: "guild1", Points: 80}
+	user.ID = 3
+
+	// The floor is taken from the locked balance, which has dropped to 30.
+	mock.ExpectBegin()
+	expectLockedBalance(mock, 3, 30)
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+		WithArgs(0.0, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+		WithArgs(sqlmock.AnyArg(), 3, "guild1", -30.0, 0.0, string(ReasonScheduledJob), nil, nil, 37, "loan_shark", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	applied, err := ApplyFloored(db, &user, -50, Job("loan_shark", 37))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied != -30 {
+		t.Errorf("expected applied delta -30, got %.1f", applied)
+	}
+	if user.Points != 0 {
+		t.Errorf("expected balance to stop at 0, got %.1f", user.Points)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestApplyMany(t *testing.T) {
+	db, mock := newMockDB(t)
+	users := []models.User{{GuildID: "guild1", Points: 100}, {GuildID: "guild1", Points: 100}}
+	users[0].ID = 1
+	users[1].ID = 2
+
+	// Each movement comes from the locked balance, so the second user's
+	// ledger row matches what the database holds.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id`,`points` FROM `users` WHERE id IN \\(\\?,\\?\\) .* ORDER BY id FOR UPDATE").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "points"}).AddRow(1, 100.0).AddRow(2, 40.0))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+		WithArgs(75.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+		WithArgs(sqlmock.AnyArg(), 1, "guild1", -25.0, 75.0, string(ReasonCardEffect), nil, nil, 5, "", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `users` SET `points`=\\? WHERE id = \\?").
+		WithArgs(30.0, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `points_ledger_entries`").
+		WithArgs(sqlmock.AnyArg(), 2, "guild1", -10.0, 30.0, string(ReasonCardEffect), nil, nil, 5, "", "").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	loss := func(before float64) float64 { return before*0.75 - before }
+	if err := ApplyMany(db, users, loss, Card(5)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users[0].Points != 75 || users[1].Points != 30 {
+		t.Errorf("expected balances 75 and 30, got %.1f and %.1f", users[0].Points, users[1].Points)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestApplyConcurrent moves one balance from many goroutines, each holding a
+// stale copy of the user, and checks the ledger adds up to the final balance.
+func TestApplyConcurrent(t *testing.T) {
+	store := &ledgerStore{points: map[int64]float64{7: 1000}}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(store),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open gorm db: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 20; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			user := models.User{GuildID: "guild1", Points: 1000}
+			user.ID = 7
+			var err error
+			if n%2 == 0 {
+				err = Apply(db, &user, 12.5, Entry{Reason: ReasonMessage})
+			} else {
+				_, err = ApplyFloored(db, &user, -30, Bet(ReasonBetPlaced, 1))
+			}
+			if err != nil {
+				t.Errorf("movement %d: %v", n, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	balance := 1000.0
+	for idx, row := range store.ledger {
+		balance = common.RoundPoints(balance + row.delta)
+		if row.balanceAfter != balance {
+			t.Errorf("ledger row %d: balance_after %.2f, want %.2f", idx, row.balanceAfter, balance)
+		}
+	}
+	if balance != store.points[7] {
+		t.Errorf("ledger adds up to %.2f, but the balance is %.2f", balance, store.points[7])
+	}
+	if len(store.ledger) != 20 {
+		t.Errorf("expected 20 ledger rows, got %d", len(store.ledger))
+	}
+}
+
+// ledgerStore is a database/sql driver holding the users' balances and ledger
+// rows that the ledger writes. A SELECT ... FOR UPDATE takes the row lock and
+// holds it until the transaction ends, as MySQL does.
+type ledgerStore struct {
+	rowLock sync.Mutex
+	mu      sync.Mutex
+	points  map[int64]float64
+	ledger  []ledgerRow
+}
+
+type ledgerRow struct {
+	delta        float64
+	balanceAfter float64
+}
+
+func (s *ledgerStore) Connect(context.Context) (driver.Conn, error) {
+	return &ledgerConn{store: s}, nil
+}
+
+func (s *ledgerStore) Driver() driver.Driver {
+	return nil
+}
+
+type ledgerConn struct {
+	store  *ledgerStore
+	locked bool
+}
+
+var (
+	selectColumns = regexp.MustCompile("^SELECT (.+) FROM `users`")
+	insertColumns = regexp.MustCompile("^INSERT INTO `points_ledger_entries` \\((.+?)\\) VALUES")
+)
+
+func (c *ledgerConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *ledgerConn) Close() error {
+	return nil
+}
+
+func (c *ledgerConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *ledgerConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *ledgerConn) Commit() error {
+	c.unlock()
+	return nil
+}
+
+func (c *ledgerConn) Rollback() error {
+	c.unlock()
+	return nil
+}
+
+func (c *ledgerConn) unlock() {
+	if c.locked {
+		c.locked = false
+		c.store.rowLock.Unlock()
+	}
+}
+
+func (c *ledgerConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	match := selectColumns.FindStringSubmatch(query)
+	if match == nil || !strings.HasSuffix(query, "FOR UPDATE") {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	if !c.locked {
+		c.store.rowLock.Lock()
+		c.locked = true
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	id := args[0].Value.(int64)
+	rows := &ledgerRows{}
+	for _, column := range strings.Split(match[1], ",") {
+		rows.columns = append(rows.columns, strings.Trim(column, "`"))
+	}
+	var values []driver.Value
+	for _, column := range rows.columns {
+		switch column {
+		case "id":
+			values = append(values, id)
+		case "points":
+			values = append(values, c.store.points[id])
+		default:
+			return nil, fmt.Errorf("unexpected column %q", column)
+		}
+	}
+	rows.values = [][]driver.Value{values}
+	return rows, nil
+}
+
+func (c *ledgerConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	if strings.HasPrefix(query, "UPDATE `users` SET `points`=?") {
+		c.store.points[args[1].Value.(int64)] = args[0].Value.(float64)
+		return driver.RowsAffected(1), nil
+	}
+	if match := insertColumns.FindStringSubmatch(query); match != nil {
+		var row ledgerRow
+		for idx, column := range strings.Split(match[1], ",") {
+			switch strings.Trim(column, "`") {
+			case "delta":
+				row.delta = args[idx].Value.(float64)
+			case "balance_after":
+				row.balanceAfter = args[idx].Value.(float64)
+			}
+		}
+		c.store.ledger = append(c.store.ledger, row)
+		return ledgerResult(len(c.store.ledger)), nil
+	}
+	return nil, fmt.Errorf("unexpected statement: %s", query)
+}
+
+type ledgerResult int64
+
+func (r ledgerResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r ledgerResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type ledgerRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *ledgerRows) Columns() []string {
+	return r.columns
+}
+
+func (r *ledgerRows) Close() error {
+	return nil
+}
+
+func (r *ledgerRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
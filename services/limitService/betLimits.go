@@ -0,0 +1,93 @@
+package limitService
+
+import (
+	"fmt"
+	"math"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/guildService"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// BetLimits shows the guild's bet limits, after changing any that were given.
+// A limit set to zero is turned off.
+func BetLimits(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		return
+	}
+
+	guild, err := guildService.GetGuildInfo(s, db, i.GuildID, i.ChannelID)
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	for _, opt := range options {
+		switch opt.Name {
+		case "max-stake":
+			guild.MaxStake = int(math.Max(float64(opt.IntValue()), 0))
+		case "max-percent":
+			guild.MaxStakePercent = math.Min(math.Max(opt.FloatValue(), 0), 100)
+		case "max-exposure":
+			guild.MaxExposure = int(math.Max(float64(opt.IntValue()), 0))
+		case "max-side-stake":
+			guild.MaxSideStake = int(math.Max(float64(opt.IntValue()), 0))
+		}
+	}
+	if len(options) > 0 {
+		db.Save(&guild)
+	}
+
+	heading := "Bet limits for this server:"
+	if len(options) > 0 {
+		heading = "Bet limits updated:"
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: heading + "\n" + describeLimits(*guild) + "\nSet a limit to 0 to turn it off.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+}
+
+// describeLimits lists a guild's bet limits, one per line.
+func describeLimits(guild models.Guild) string {
+	points := func(limit int) string {
+		if limit <= 0 {
+			return "off"
+		}
+		return fmt.Sprintf("%d points", limit)
+	}
+	percent := "off"
+	if guild.MaxStakePercent > 0 {
+		percent = FormatPercent(guild.MaxStakePercent) + " of the bettor's balance"
+	}
+
+	var lines strings.Builder
+	lines.WriteString(fmt.Sprintf("* Max stake per bet: %s\n", points(guild.MaxStake)))
+	lines.WriteString(fmt.Sprintf("* Max stake as a share of balance: %s\n", percent))
+	lines.WriteString(fmt.Sprintf("* Max total riding on open bets: %s\n", points(guild.MaxExposure)))
+	lines.WriteString(fmt.Sprintf("* Max stake on one side of a bet: %s\n", points(guild.MaxSideStake)))
+	return lines.String()
+}
@@ -0,0 +1,158 @@
+package limitService
+
+import (
+	"fmt"
+	"math"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService/cards"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// LimitError is a stake that breaks one of the guild's bet limits. Its message
+// is the reason shown to the bettor.
+type LimitError struct {
+	Reason string
+}
+
+func (e *LimitError) Error() string {
+	return e.Reason
+}
+
+// Side is the option of a bet a stake is placed on.
+type Side struct {
+	BetID  uint
+	Option int
+}
+
+// Exposure is what a user has riding on bets that haven't settled.
+type Exposure struct {
+	// Open is every unsettled stake: bet entries, parlays and Anti-Anti-Bets.
+	Open float64
+	// Side is what the user already has on the side being bet.
+	Side float64
+}
+
+// CheckStake returns a *LimitError when staking stake would break one of the
+// guild's limits. side is nil for stakes that aren't on one side of a bet,
+// such as parlays.
+func CheckStake(db *gorm.DB, guild models.Guild, user models.User, stake float64, side *Side) error {
+	allowed, reason, err := AllowedStake(db, guild, user, side)
+	if err != nil {
+		return err
+	}
+	if stake > allowed {
+		return &LimitError{Reason: reason}
+	}
+	return nil
+}
+
+// AllowedStake returns the most a user can stake under the guild's limits, and
+// the reason a larger stake is turned away. It is never more than the user's
+// balance.
+func AllowedStake(db *gorm.DB, guild models.Guild, user models.User, side *Side) (float64, string, error) {
+	if !limited(guild) {
+		allowed, reason := allowedStake(guild, user.Points, Exposure{}, false)
+		return allowed, reason, nil
+	}
+
+	exposure, err := UserExposure(db, user, side)
+	if err != nil {
+		return 0, "", err
+	}
+	allowed, reason := allowedStake(guild, user.Points, exposure, side != nil)
+	return allowed, reason, nil
+}
+
+func limited(guild models.Guild) bool {
+	return guild.MaxStake > 0 || guild.MaxStakePercent > 0 || guild.MaxExposure > 0 || guild.MaxSideStake > 0
+}
+
+// allowedStake applies the guild's limits to a balance and exposure. The
+// reason names the tightest limit, so the bettor knows which one they hit.
+func allowedStake(guild models.Guild, balance float64, exposure Exposure, onSide bool) (float64, string) {
+	allowed := math.Max(balance, 0)
+	reason := ""
+	limit := func(most float64, why string) {
+		most = math.Max(math.Floor(most), 0)
+		if most < allowed {
+			allowed = most
+			reason = why
+		}
+	}
+
+	if guild.MaxStake > 0 {
+		limit(float64(guild.MaxStake), fmt.Sprintf("This server caps a single bet at %d points.", guild.MaxStake))
+	}
+	if guild.MaxStakePercent > 0 {
+		most := balance * guild.MaxStakePercent / 100
+		limit(most, fmt.Sprintf("This server caps a single bet at %s of your balance, which is %.0f points right now.",
+			FormatPercent(guild.MaxStakePercent), math.Max(math.Floor(most), 0)))
+	}
+	if guild.MaxExposure > 0 {
+		most := float64(guild.MaxExposure) - exposure.Open
+		limit(most, fmt.Sprintf("This server caps what you can have riding on open bets at %d points. You have %.0f riding, so you can add %.0f more.",
+			guild.MaxExposure, exposure.Open, math.Max(math.Floor(most), 0)))
+	}
+	if onSide && guild.MaxSideStake > 0 {
+		most := float64(guild.MaxSideStake) - exposure.Side
+		limit(most, fmt.Sprintf("This server caps what you can have on one side of a bet at %d points. You have %.0f on this side, so you can add %.0f more.",
+			guild.MaxSideStake, exposure.Side, math.Max(math.Floor(most), 0)))
+	}
+	if reason == "" {
+		reason = "You do not have enough points to place this bet."
+	}
+	return allowed, reason
+}
+
+// FormatPercent formats a percent limit like "25%" or "12.5%".
+func FormatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
+
+// UserExposure totals a user's unsettled stakes, and what they have on side
+// when it isn't nil.
+func UserExposure(db *gorm.DB, user models.User, side *Side) (Exposure, error) {
+	var exposure Exposure
+
+	var entries float64
+	err := db.Model(&models.BetEntry{}).
+		Joins("JOIN bets ON bets.id = bet_entries.bet_id").
+		Where("bet_entries.user_id = ? AND bets.paid = ? AND bets.deleted_at IS NULL", user.ID, false).
+		Select("COALESCE(SUM(bet_entries.amount), 0)").
+		Scan(&entries).Error
+	if err != nil {
+		return Exposure{}, err
+	}
+
+	var parlays float64
+	err = db.Model(&models.Parlay{}).
+		Where("user_id = ? AND status IN ?", user.ID, []string{"pending", "partial"}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&parlays).Error
+	if err != nil {
+		return Exposure{}, err
+	}
+
+	var antiAntiBets float64
+	err = db.Model(&models.UserInventory{}).
+		Where("user_id = ? AND card_id = ?", user.ID, cards.AntiAntiBetCardID).
+		Select("COALESCE(SUM(bet_amount), 0)").
+		Scan(&antiAntiBets).Error
+	if err != nil {
+		return Exposure{}, err
+	}
+	exposure.Open = entries + parlays + antiAntiBets
+
+	if side != nil {
+		err = db.Model(&models.BetEntry{}).
+			Where("user_id = ? AND bet_id = ? AND `option` = ?", user.ID, side.BetID, side.Option).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&exposure.Side).Error
+		if err != nil {
+			return Exposure{}, err
+		}
+	}
+	return exposure, nil
+}
@@ -0,0 +1,50 @@
+package limitService
+
+import (
+	"perfectOddsBot/models"
+	"strings"
+	"testing"
+)
+
+func TestAllowedStake(t *testing.T) {
+	tests := []struct {
+		name     string
+		guild    models.Guild
+		balance  float64
+		exposure Exposure
+		onSide   bool
+		allowed  float64
+		reason   string
+	}{
+		{"No limits", models.Guild{}, 1000, Exposure{}, true, 1000, "enough points"},
+		{"Max stake", models.Guild{MaxStake: 250}, 1000, Exposure{}, true, 250, "single bet at 250 points"},
+		{"Percent of balance", models.Guild{MaxStakePercent: 12.5}, 1000, Exposure{}, true, 125, "12.5% of your balance"},
+		{"Exposure left", models.Guild{MaxExposure: 1500}, 1000, Exposure{Open: 1200}, true, 300, "You have 1200 riding"},
+		{"Exposure used up", models.Guild{MaxExposure: 1000}, 1000, Exposure{Open: 1200}, true, 0, "add 0 more"},
+		{"Side stake left", models.Guild{MaxSideStake: 400}, 1000, Exposure{Side: 150}, true, 250, "150 on this side"},
+		{"Side limit skipped for parlays", models.Guild{MaxSideStake: 400}, 1000, Exposure{Side: 150}, false, 1000, "enough points"},
+		{"Tightest limit wins", models.Guild{MaxStake: 500, MaxStakePercent: 25, MaxExposure: 2000}, 1000, Exposure{Open: 1800}, true, 200, "riding on open bets"},
+		{"Balance below every limit", models.Guild{MaxStake: 500}, 80, Exposure{}, true, 80, "enough points"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := allowedStake(tt.guild, tt.balance, tt.exposure, tt.onSide)
+			if allowed != tt.allowed {
+				t.Errorf("allowed = %.1f, want %.1f", allowed, tt.allowed)
+			}
+			if !strings.Contains(reason, tt.reason) {
+				t.Errorf("reason %q does not mention %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestDescribeLimits(t *testing.T) {
+	description := describeLimits(models.Guild{MaxStake: 500, MaxStakePercent: 20})
+	for _, expected := range []string{"per bet: 500 points", "20% of the bettor's balance", "open bets: off", "side of a bet: off"} {
+		if !strings.Contains(description, expected) {
+			t.Errorf("expected %q in %q", expected, description)
+		}
+	}
+}
@@ -2,34 +2,64 @@ package messageService
 
 import (
 	"fmt"
+	"perfectOddsBot/models"
 	"perfectOddsBot/services/common"
+	"strconv"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func GetAllButtonList(s *discordgo.Session, i *discordgo.InteractionCreate, opt1 string, opt2 string, betId uint) []discordgo.MessageComponent {
-	var buttons []discordgo.MessageComponent
+// maxBetButtons is the number of option buttons that fit on one action row alongside the
+// admin lock/resolve buttons. Bets with more options are rendered as a select menu.
+const maxBetButtons = 3
 
-	betButtons := GetBetButtons(opt1, opt2, betId)
+// maxRowComponents is the number of buttons Discord allows on one action row.
+const maxRowComponents = 5
 
-	for _, betButton := range betButtons {
-		buttons = append(buttons, betButton)
+func GetAllButtonList(s *discordgo.Session, i *discordgo.InteractionCreate, options []models.BetOption, betId uint) []discordgo.MessageComponent {
+	var adminButtons []discordgo.MessageComponent
+	if common.IsAdmin(s, i) {
+		adminButtons = append(adminButtons, GetLockButton(betId), GetResolveButton(betId), GetVoidButton(betId))
 	}
 
-	if common.IsAdmin(s, i) {
-		resolveBtn := GetResolveButton(betId)
-		lockBtn := GetLockButton(betId)
-		buttons = append(buttons, lockBtn)
-		buttons = append(buttons, resolveBtn)
+	if len(options) > maxBetButtons {
+		rows := []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{GetBetOptionSelect(options, betId)},
+			},
+		}
+		if len(adminButtons) > 0 {
+			rows = append(rows, discordgo.ActionsRow{Components: adminButtons})
+		}
+		return rows
 	}
 
-	return buttons
+	var buttons []discordgo.MessageComponent
+	for _, betButton := range GetBetButtons(options, betId) {
+		buttons = append(buttons, betButton)
+	}
+	if len(buttons)+len(adminButtons) > maxRowComponents {
+		return []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: buttons},
+			discordgo.ActionsRow{Components: adminButtons},
+		}
+	}
+	buttons = append(buttons, adminButtons...)
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: buttons,
+		},
+	}
 }
 
 func GetBetOnlyButtonsList(opt1 string, opt2 string, betId uint) []discordgo.MessageComponent {
 	var buttons []discordgo.MessageComponent
 
-	betButtons := GetBetButtons(opt1, opt2, betId)
+	betButtons := GetBetButtons([]models.BetOption{
+		{OptionNumber: 1, Name: opt1},
+		{OptionNumber: 2, Name: opt2},
+	}, betId)
 
 	for _, betButton := range betButtons {
 		buttons = append(buttons, betButton)
@@ -38,24 +68,54 @@ func GetBetOnlyButtonsList(opt1 string, opt2 string, betId uint) []discordgo.Mes
 	return buttons
 }
 
-func GetBetButtons(opt1 string, opt2 string, betId uint) []discordgo.Button {
-	return []discordgo.Button{
-		{
-			Label:    opt1,
-			Style:    discordgo.PrimaryButton,
-			CustomID: fmt.Sprintf("bet_%d_option1", betId),
-			Emoji: &discordgo.ComponentEmoji{
-				Name: "🟡",
-			},
-		},
-		{
-			Label:    opt2,
-			Style:    discordgo.SuccessButton,
-			CustomID: fmt.Sprintf("bet_%d_option2", betId),
+func GetBetButtons(options []models.BetOption, betId uint) []discordgo.Button {
+	var buttons []discordgo.Button
+	for idx, option := range options {
+		style := discordgo.PrimaryButton
+		if idx%2 == 1 {
+			style = discordgo.SuccessButton
+		}
+
+		label := option.Name
+		if len(label) > 80 {
+			label = label[:77] + "..."
+		}
+
+		buttons = append(buttons, discordgo.Button{
+			Label:    label,
+			Style:    style,
+			CustomID: fmt.Sprintf("bet_%d_option%d", betId, option.OptionNumber),
 			Emoji: &discordgo.ComponentEmoji{
 				Name: "🟡",
 			},
-		},
+		})
+	}
+	return buttons
+}
+
+func GetBetOptionSelect(options []models.BetOption, betId uint) discordgo.SelectMenu {
+	var selectOptions []discordgo.SelectMenuOption
+	for _, option := range options {
+		label := fmt.Sprintf("%d. %s", option.OptionNumber, option.Name)
+		if len(label) > 100 {
+			label = label[:97] + "..."
+		}
+
+		selectOptions = append(selectOptions, discordgo.SelectMenuOption{
+			Label:       label,
+			Value:       strconv.Itoa(option.OptionNumber),
+			Description: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(option.Odds))),
+		})
+	}
+
+	minValues := 1
+	return discordgo.SelectMenu{
+		MenuType:    discordgo.StringSelectMenu,
+		CustomID:    fmt.Sprintf("bet_%d_select", betId),
+		Placeholder: "Choose an option to bet on...",
+		MinValues:   &minValues,
+		MaxValues:   1,
+		Options:     selectOptions,
 	}
 }
 
@@ -81,7 +141,18 @@ func GetResolveButton(betId uint) discordgo.Button {
 	}
 }
 
-func BuildBetResolutionEmbed(betDescription string, subtitle string, totalPayout float64, winners string, losers string) *discordgo.MessageEmbed {
+func GetVoidButton(betId uint) discordgo.Button {
+	return discordgo.Button{
+		Label:    "Void Bet",
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("void_bet_%d", betId),
+		Emoji: &discordgo.ComponentEmoji{
+			Name: "🚫",
+		},
+	}
+}
+
+func BuildBetResolutionEmbed(betDescription string, subtitle string, totalPayout float64, winners string, losers string, pushes string) *discordgo.MessageEmbed {
 	if winners == "" {
 		winners = "_No winners_"
 	}
@@ -89,24 +160,224 @@ func BuildBetResolutionEmbed(betDescription string, subtitle string, totalPayout
 		losers = "_No losers_"
 	}
 
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Total Payout",
+			Value:  fmt.Sprintf("**%.1f** points", totalPayout),
+			Inline: true,
+		},
+		{
+			Name:  "Winners",
+			Value: winners,
+		},
+		{
+			Name:  "Losers",
+			Value: losers,
+		},
+	}
+	if pushes != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Pushes (Refunded)",
+			Value: truncateFieldValue(pushes + "\n" + PushCardsNote),
+		})
+	}
+
 	return &discordgo.MessageEmbed{
 		Title:       fmt.Sprintf("🏁 Bet Resolved: %s", betDescription),
 		Description: subtitle,
 		Color:       0x57F287,
+		Fields:      fields,
+	}
+}
+
+func BuildBetVoidEmbed(betDescription string, reason string, refunds string, returnedCards string) *discordgo.MessageEmbed {
+	if refunds == "" {
+		refunds = "_No entries_"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Refunds",
+			Value: refunds,
+		},
+	}
+	if returnedCards != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Cards Returned",
+			Value: returnedCards,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🚫 Bet Voided: %s", betDescription),
+		Description: reason,
+		Color:       0x95A5A6,
+		Fields:      fields,
+	}
+}
+
+func BuildLineMovementEmbed(betDescription string, from string, to string, since string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📈 Line Moved: %s", betDescription),
+		Description: fmt.Sprintf("The line has moved since %s.", since),
+		Color:       0xE67E22,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:   "Total Payout",
-				Value:  fmt.Sprintf("**%.1f** points", totalPayout),
+				Name:   "Was",
+				Value:  from,
+				Inline: true,
+			},
+			{
+				Name:   "Now",
+				Value:  to,
 				Inline: true,
 			},
+		},
+	}
+}
+
+// BuildLiveGameEmbed shows a closed game bet with its game's live score, or its
+// final score once the game is over.
+func BuildLiveGameEmbed(bet models.Bet, score string, status string, covering string, final bool) *discordgo.MessageEmbed {
+	title := "🔴 LIVE - Bet has been CLOSED (Will Auto Resolve)"
+	color := 0xE74C3C
+	if final {
+		title = "🏁 FINAL - Bet has been CLOSED (Resolving)"
+		color = 0x3498db
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: common.GameBetDescription(bet),
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  fmt.Sprintf("1️⃣ %s", bet.Option1),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds1))),
+			},
 			{
-				Name:  "Winners",
-				Value: winners,
+				Name:  fmt.Sprintf("2️⃣ %s", bet.Option2),
+				Value: fmt.Sprintf("Odds: %s", common.FormatOdds(float64(bet.Odds2))),
 			},
 			{
-				Name:  "Losers",
-				Value: losers,
+				Name:  "Score",
+				Value: fmt.Sprintf("%s\n%s", score, status),
+			},
+			{
+				Name:  "Currently",
+				Value: covering,
+			},
+		},
+	}
+}
+
+func BuildSettlementReversalEmbed(betDescription string, subtitle string, poolDelta float64, corrections string, returnedCards string) *discordgo.MessageEmbed {
+	if corrections == "" {
+		corrections = "_No point changes_"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Pool Change",
+			Value:  fmt.Sprintf("**%+.1f** points", poolDelta),
+			Inline: true,
+		},
+		{
+			Name:  "Point Corrections",
+			Value: truncateFieldValue(corrections),
+		},
+	}
+	if returnedCards != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Cards",
+			Value: truncateFieldValue(returnedCards),
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("↩️ Bet Reversed: %s", betDescription),
+		Description: subtitle,
+		Color:       0xE67E22,
+		Fields:      fields,
+	}
+}
+
+// GetResolvePreviewButtons are shown under a settlement preview so the admin can
+// commit the resolution or back out.
+func GetResolvePreviewButtons(betId uint, winningOption int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Confirm Resolution",
+					Style:    discordgo.SuccessButton,
+					CustomID: fmt.Sprintf("resolve_apply_%d_%d", betId, winningOption),
+					Emoji: &discordgo.ComponentEmoji{
+						Name: "✅",
+					},
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("resolve_cancel_%d", betId),
+				},
 			},
 		},
 	}
 }
+
+// maxEmbedFieldValue is the longest value Discord accepts for an embed field.
+const maxEmbedFieldValue = 1024
+
+// PushCardsNote tells bettors what happened to their cards on a push.
+const PushCardsNote = "_Cards don't trigger on a push. Win and loss cards stay in your inventory for your next bet._"
+
+func truncateFieldValue(value string) string {
+	if len(value) <= maxEmbedFieldValue {
+		return value
+	}
+	return value[:maxEmbedFieldValue-4] + "\n…"
+}
+
+func BuildSettlementPreviewEmbed(betDescription string, subtitle string, totalPayout float64, poolDelta float64, basePayouts string, winners string, losers string, pushes string, cardsPlayed string, parlayLegs string) *discordgo.MessageEmbed {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Total Payout",
+			Value:  fmt.Sprintf("**%.1f** points", totalPayout),
+			Inline: true,
+		},
+		{
+			Name:   "Pool Change",
+			Value:  fmt.Sprintf("**%+.1f** points", poolDelta),
+			Inline: true,
+		},
+	}
+
+	sections := []struct {
+		name  string
+		value string
+	}{
+		{"Base Payouts", basePayouts},
+		{"Winners (after cards)", winners},
+		{"Losers", losers},
+		{"Pushes (Refunded)", pushes},
+		{"Cards Triggered", cardsPlayed},
+		{"Parlay Legs", parlayLegs},
+	}
+	for _, section := range sections {
+		if section.value == "" {
+			continue
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  section.name,
+			Value: truncateFieldValue(section.value),
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🔍 Resolution Preview: %s", betDescription),
+		Description: subtitle + "\n\nNothing has been paid yet. Review the outcome below and confirm to settle the bet.",
+		Color:       0xFEE75C,
+		Fields:      fields,
+	}
+}
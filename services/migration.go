@@ -419,6 +419,164 @@ func RunUserInventoryCardCodeBackfill(db *gorm.DB) error {
 	return nil
 }
 
+// fixedPointColumns are the points columns that moved from DOUBLE to
+// DECIMAL(20,2).
+var fixedPointColumns = map[string][]string{
+	"users":                      {"points", "total_points_won", "total_points_lost"},
+	"guilds":                     {"points_per_message", "starting_points", "pool", "card_draw_cost"},
+	"user_inventories":           {"bet_amount"},
+	"points_ledger_entries":      {"delta", "balance_after"},
+	"settlement_journals":        {"pool_delta"},
+	"settlement_journal_entries": {"points_delta", "total_points_won_delta", "total_points_lost_delta"},
+}
+
+// RunFixedPointPointsMigration rounds existing balances to minor units before
+// AutoMigrate changes their columns to DECIMAL(20,2). Values are cast to
+// DECIMAL first so ROUND goes half away from zero, the same as
+// common.RoundPoints, rather than whatever the C library does for doubles.
+// It has to run before AutoMigrate, so it creates the migrations table itself.
+func RunFixedPointPointsMigration(db *gorm.DB) error {
+	const migrationName = "fixed_point_points_migration"
+	if err := db.AutoMigrate(&models.Migration{}); err != nil {
+		return fmt.Errorf("error creating migrations table: %w", err)
+	}
+	var existing models.Migration
+	if err := db.Where("name = ?", migrationName).First(&existing).Error; err == nil && existing.ID != 0 {
+		log.Println("Fixed-point points migration already executed. Skipping.")
+		return nil
+	}
+
+	log.Println("Rounding points balances to minor units...")
+	for table, columns := range fixedPointColumns {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		for _, column := range columns {
+			if !db.Migrator().HasColumn(table, column) {
+				continue
+			}
+			res := db.Exec(fmt.Sprintf("UPDATE %s SET %s = ROUND(CAST(%s AS DECIMAL(30,10)), 2) WHERE %s IS NOT NULL", table, column, column, column))
+			if res.Error != nil {
+				return fmt.Errorf("fixed-point points migration on %s.%s: %w", table, column, res.Error)
+			}
+			log.Printf("Rounded %s.%s. Rows updated: %d", table, column, res.RowsAffected)
+		}
+	}
+
+	if err := db.Create(&models.Migration{Name: migrationName, ExecutedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("error recording fixed_point_points_migration migration: %w", err)
+	}
+	return nil
+}
+
+func RunBetSportBackfill(db *gorm.DB) error {
+	const migrationName = "bet_sport_backfill"
+	var existing models.Migration
+	if err := db.Where("name = ?", migrationName).First(&existing).Error; err == nil && existing.ID != 0 {
+		log.Println("Bet sport backfill already executed. Skipping.")
+		return nil
+	}
+
+	log.Println("Backfilling bets.sport for CFB and CBB bets...")
+	res := db.Exec("UPDATE bets SET sport = ? WHERE cfbd_id IS NOT NULL AND (sport IS NULL OR sport = '')", models.SportCFB)
+	if res.Error != nil {
+		return fmt.Errorf("cfb bet sport backfill: %w", res.Error)
+	}
+	cfbUpdated := res.RowsAffected
+
+	res = db.Exec("UPDATE bets SET sport = ? WHERE espn_id IS NOT NULL AND (sport IS NULL OR sport = '')", models.SportCBB)
+	if res.Error != nil {
+		return fmt.Errorf("cbb bet sport backfill: %w", res.Error)
+	}
+	cbbUpdated := res.RowsAffected
+
+	log.Printf("Bet sport backfill completed. CFB: %d rows, CBB: %d rows.", cfbUpdated, cbbUpdated)
+
+	if err := db.Create(&models.Migration{Name: migrationName, ExecutedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("error recording bet_sport_backfill migration: %w", err)
+	}
+	return nil
+}
+
+// RunBetEntryOddsBackfill records the odds open entries were placed at, taken
+// from their bet, so refreshing a line doesn't change what they pay.
+func RunBetEntryOddsBackfill(db *gorm.DB) error {
+	const migrationName = "bet_entry_odds_backfill"
+	var existing models.Migration
+	if err := db.Where("name = ?", migrationName).First(&existing).Error; err == nil && existing.ID != 0 {
+		log.Println("Bet entry odds backfill already executed. Skipping.")
+		return nil
+	}
+
+	log.Println("Backfilling bet_entries.odds from their bets...")
+	res := db.Exec("UPDATE bet_entries JOIN bets ON bets.id = bet_entries.bet_id " +
+		"LEFT JOIN bet_options ON bet_options.bet_id = bets.id AND bet_options.option_number = bet_entries.`option` AND bet_options.deleted_at IS NULL " +
+		"SET bet_entries.odds = COALESCE(bet_options.odds, CASE WHEN bet_entries.`option` = 2 THEN bets.odds2 ELSE bets.odds1 END) " +
+		"WHERE bet_entries.odds IS NULL AND bets.paid = 0")
+	if res.Error != nil {
+		return fmt.Errorf("bet entry odds backfill: %w", res.Error)
+	}
+	log.Printf("Bet entry odds backfill completed. Updated %d rows.", res.RowsAffected)
+
+	if err := db.Create(&models.Migration{Name: migrationName, ExecutedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("error recording bet_entry_odds_backfill migration: %w", err)
+	}
+	return nil
+}
+
+// RunParlayEntryOddsBackfill records the odds open parlay legs were placed at,
+// taken from their bet.
+func RunParlayEntryOddsBackfill(db *gorm.DB) error {
+	const migrationName = "parlay_entry_odds_backfill"
+	var existing models.Migration
+	if err := db.Where("name = ?", migrationName).First(&existing).Error; err == nil && existing.ID != 0 {
+		log.Println("Parlay entry odds backfill already executed. Skipping.")
+		return nil
+	}
+
+	log.Println("Backfilling parlay_entries.odds from their bets...")
+	res := db.Exec("UPDATE parlay_entries JOIN bets ON bets.id = parlay_entries.bet_id " +
+		"LEFT JOIN bet_options ON bet_options.bet_id = bets.id AND bet_options.option_number = parlay_entries.selected_option AND bet_options.deleted_at IS NULL " +
+		"SET parlay_entries.odds = COALESCE(bet_options.odds, CASE WHEN parlay_entries.selected_option = 2 THEN bets.odds2 ELSE bets.odds1 END) " +
+		"WHERE parlay_entries.odds IS NULL AND parlay_entries.resolved = 0")
+	if res.Error != nil {
+		return fmt.Errorf("parlay entry odds backfill: %w", res.Error)
+	}
+	log.Printf("Parlay entry odds backfill completed. Updated %d rows.", res.RowsAffected)
+
+	if err := db.Create(&models.Migration{Name: migrationName, ExecutedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("error recording parlay_entry_odds_backfill migration: %w", err)
+	}
+	return nil
+}
+
+func RunGuildSubscriptionBackfill(db *gorm.DB) error {
+	const migrationName = "guild_subscription_backfill"
+	var existing models.Migration
+	if err := db.Where("name = ?", migrationName).First(&existing).Error; err == nil && existing.ID != 0 {
+		log.Println("Guild subscription backfill already executed. Skipping.")
+		return nil
+	}
+
+	if db.Migrator().HasColumn(&models.Guild{}, "subscribed_team") {
+		log.Println("Backfilling guild_subscriptions from guilds.subscribed_team...")
+		res := db.Exec("INSERT INTO guild_subscriptions (created_at, updated_at, guild_id, sport, kind, target, channel_id, bet_type, hours_before) "+
+			"SELECT NOW(), NOW(), guilds.guild_id, sports.sport, ?, guilds.subscribed_team, '', ?, 0 "+
+			"FROM guilds CROSS JOIN (SELECT ? AS sport UNION ALL SELECT ?) AS sports "+
+			"WHERE guilds.subscribed_team IS NOT NULL AND guilds.subscribed_team <> '' AND guilds.deleted_at IS NULL",
+			models.SubscriptionTeam, models.BetTypeSpread, models.SportCFB, models.SportCBB)
+		if res.Error != nil {
+			return fmt.Errorf("guild subscription backfill: %w", res.Error)
+		}
+		log.Printf("Guild subscription backfill completed. Created %d subscriptions.", res.RowsAffected)
+	}
+
+	if err := db.Create(&models.Migration{Name: migrationName, ExecutedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("error recording guild_subscription_backfill migration: %w", err)
+	}
+	return nil
+}
+
 func RunVampireDevilExpiresAtBackfill(db *gorm.DB) error {
 	const migrationName = "vampire_devil_expires_at_backfill"
 	var existing models.Migration
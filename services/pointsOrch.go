@@ -3,8 +3,11 @@ package services
 import (
 	"fmt"
 	"perfectOddsBot/models"
+	"perfectOddsBot/services/cardService"
 	"perfectOddsBot/services/common"
 	"perfectOddsBot/services/guildService"
+	"perfectOddsBot/services/ledgerService"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"gorm.io/gorm"
@@ -23,16 +26,15 @@ func ShowPoints(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.D
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: userID, GuildID: guildID})
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
 	}
 
 	username := common.GetUsernameFromUser(i.Member.User)
 	common.UpdateUserUsername(db, &user, username)
 
-	if result.RowsAffected == 1 {
-		db.Save(&user)
-	}
-
 	response := fmt.Sprintf("You have **%.1f** points.", user.Points)
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -90,14 +92,20 @@ func GivePoints(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.D
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: targetUser.ID, GuildID: guildID})
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
 	}
 
 	username := common.GetUsernameFromUser(targetUser)
 	common.UpdateUserUsername(db, &user, username)
 
-	user.Points += float64(amount)
-	db.Save(&user)
+	entry := ledgerService.Entry{Reason: ledgerService.ReasonAdminGrant, Note: fmt.Sprintf("Given by %s", i.Member.User.ID)}
+	if err := ledgerService.Apply(db, &user, float64(amount), entry); err != nil {
+		common.SendError(s, i, fmt.Errorf("error giving points: %v", err), db)
+		return
+	}
 
 	response := fmt.Sprintf("Successfully gave **%d** points to **%s**.", amount, targetUser.Username)
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -147,7 +155,11 @@ func ResetPoints(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.
 	}
 
 	guildID := i.GuildID
-	db.Model(&models.User{}).Where("guild_id = ?", guildID).Update("points", defaultAmount)
+	entry := ledgerService.Entry{Reason: ledgerService.ReasonAdminReset, Note: fmt.Sprintf("Reset by %s", i.Member.User.ID)}
+	if err := ledgerService.SetGuildBalances(db, guildID, float64(defaultAmount), entry); err != nil {
+		common.SendError(s, i, fmt.Errorf("error resetting points: %v", err), db)
+		return
+	}
 
 	response := fmt.Sprintf("All users' points have been reset to **%d**.", defaultAmount)
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -174,8 +186,10 @@ func ShowStats(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 	var user models.User
 	result := db.FirstOrCreate(&user, models.User{DiscordID: userID, GuildID: guildID})
 	if result.RowsAffected == 1 {
-		user.Points = guild.StartingPoints
-		db.Save(&user)
+		if err := ledgerService.SetStartingBalance(db, &user, guild.StartingPoints); err != nil {
+			common.SendError(s, i, fmt.Errorf("error setting starting points: %v", err), db)
+			return
+		}
 	}
 
 	totalBets := user.TotalBetsWon + user.TotalBetsLost
@@ -250,3 +264,86 @@ func ShowStats(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB
 		return
 	}
 }
+
+// pointsHistoryLimit is how many ledger rows /points-history shows.
+const pointsHistoryLimit = 15
+
+func ShowPointsHistory(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	var user models.User
+	if err := db.Where("discord_id = ? AND guild_id = ?", userID, guildID).First(&user).Error; err != nil {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You don't have any points history yet.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
+	rows, err := ledgerService.History(db, user.ID, pointsHistoryLimit)
+	if err != nil {
+		common.SendError(s, i, fmt.Errorf("error loading points history: %v", err), db)
+		return
+	}
+
+	var lines []string
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("`%+.1f` → **%.1f** · %s · <t:%d:R>", row.Delta, row.BalanceAfter, describeLedgerEntry(row), row.CreatedAt.Unix()))
+	}
+	description := strings.Join(lines, "\n")
+	if description == "" {
+		description = "No points movements recorded yet."
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📒 Your Points History",
+		Description: description,
+		Color:       0xe67e22,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Current points: %.1f · Showing up to %d recent changes", user.Points, pointsHistoryLimit),
+		},
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
+
+// describeLedgerEntry turns a ledger row's reason and references into a short
+// line such as "card effect (The Nuke)" or "bet payout (bet #12)".
+func describeLedgerEntry(row models.PointsLedgerEntry) string {
+	description := strings.ReplaceAll(row.Reason, "_", " ")
+	var refs []string
+	if row.CardID != nil {
+		if card := cardService.GetCardByID(*row.CardID); card != nil {
+			refs = append(refs, card.Name)
+		}
+	}
+	if row.BetID != nil {
+		refs = append(refs, fmt.Sprintf("bet #%d", *row.BetID))
+	}
+	if row.ParlayID != nil {
+		refs = append(refs, fmt.Sprintf("parlay #%d", *row.ParlayID))
+	}
+	if row.Job != "" {
+		refs = append(refs, strings.ReplaceAll(row.Job, "_", " "))
+	}
+	if len(refs) > 0 {
+		description += " (" + strings.Join(refs, ", ") + ")"
+	}
+	return description
+}
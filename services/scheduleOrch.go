@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+	"perfectOddsBot/scheduler"
+	"perfectOddsBot/services/common"
+)
+
+// ShowSeasonStatus shows where each sport is in its season and which scheduled
+// jobs are running for it.
+func ShowSeasonStatus(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+		}
+		return
+	}
+
+	// Season calendars may need fetching, which can outlast the interaction
+	// deadline.
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+
+	now := time.Now()
+	var fields []*discordgo.MessageEmbedField
+	for _, status := range scheduler.Status(now) {
+		season := status.Season
+
+		var value strings.Builder
+		value.WriteString(fmt.Sprintf("**%s**", season.Phase.Label()))
+		if !season.Start.IsZero() && !season.End.IsZero() {
+			value.WriteString(fmt.Sprintf(" (%s – %s)", season.Start.Format("Jan 2, 2006"), season.End.Format("Jan 2, 2006")))
+		}
+		value.WriteString(fmt.Sprintf("\n_%s_\n", season.Reason))
+
+		for _, job := range status.Jobs {
+			if job.Interval == 0 {
+				value.WriteString(fmt.Sprintf("⏸️ %s: off in the %s\n", job.Name, strings.ToLower(season.Phase.Label())))
+				continue
+			}
+			lastRun := "not yet run"
+			if !job.LastRun.IsZero() {
+				lastRun = fmt.Sprintf("last run <t:%d:R>", job.LastRun.Unix())
+			}
+			value.WriteString(fmt.Sprintf("✅ %s: every %s, %s\n", job.Name, formatInterval(job.Interval), lastRun))
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  strings.ToUpper(season.Sport),
+			Value: value.String(),
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🗓️ Season Status",
+		Description: "Scheduled sports jobs poll at a rate set by each sport's season calendar.",
+		Fields:      fields,
+		Color:       0x3498db,
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+	}
+}
+
+// formatInterval shows a polling interval as "5 minutes" or "6 hours".
+func formatInterval(interval time.Duration) string {
+	switch {
+	case interval == time.Hour:
+		return "hour"
+	case interval == 24*time.Hour:
+		return "day"
+	case interval%time.Hour == 0:
+		return fmt.Sprintf("%d hours", int(interval.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(interval.Minutes()))
+}
@@ -0,0 +1,102 @@
+package teamService
+
+import (
+	"fmt"
+	"perfectOddsBot/models"
+	"perfectOddsBot/services/common"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// AddTeamAlias teaches the guild another spelling of a team. The team can be
+// given by any name the registry already knows; a team that isn't on the
+// school list is saved as typed.
+func AddTeamAlias(s *discordgo.Session, i *discordgo.InteractionCreate, db *gorm.DB) {
+	if !common.IsAdmin(s, i) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not authorized to use this command.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		return
+	}
+
+	var teamName, aliasName string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "team":
+			teamName = strings.TrimSpace(opt.StringValue())
+		case "alias":
+			aliasName = strings.TrimSpace(opt.StringValue())
+		}
+	}
+	if teamName == "" || teamKey(aliasName) == "" {
+		common.SendError(s, i, fmt.Errorf("a team and an alias are required"), db)
+		return
+	}
+
+	resolver := ForGuild(db, i.GuildID)
+	team, known := resolver.Lookup(teamName)
+	if !known {
+		team = Team{Name: teamName}
+	}
+
+	var content string
+	if teamKey(aliasName) == teamKey(team.Name) {
+		content = fmt.Sprintf("`%s` already means **%s**.", aliasName, team.Name)
+	} else {
+		alias, err := saveAlias(db, i.GuildID, aliasName, team.Name, i.Member.User.ID)
+		if err != nil {
+			common.SendError(s, i, err, db)
+			return
+		}
+		content = fmt.Sprintf("`%s` now means **%s**.", alias.Alias, alias.Team)
+		if !known {
+			content += " That team isn't on the school list, so its name is used as typed."
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		common.SendError(s, i, err, db)
+		return
+	}
+}
+
+// saveAlias creates the guild's alias, or repoints an alias it already has
+// that is spelled the same apart from case and punctuation.
+func saveAlias(db *gorm.DB, guildID string, aliasName string, teamName string, createdBy string) (models.TeamAlias, error) {
+	var aliases []models.TeamAlias
+	if err := db.Where("guild_id = ?", guildID).Find(&aliases).Error; err != nil {
+		return models.TeamAlias{}, err
+	}
+
+	alias := models.TeamAlias{GuildID: guildID, Alias: aliasName}
+	for _, existing := range aliases {
+		if teamKey(existing.Alias) == teamKey(aliasName) {
+			alias = existing
+			break
+		}
+	}
+	alias.Team = teamName
+	alias.CreatedBy = createdBy
+
+	if err := db.Save(&alias).Error; err != nil {
+		return models.TeamAlias{}, err
+	}
+	return alias, nil
+}
@@ -0,0 +1,335 @@
+package teamService
+
+import (
+	"log"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"perfectOddsBot/services/common"
+	"perfectOddsBot/services/extService"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Team is a school as the bot shows it, whatever a data source calls it.
+type Team struct {
+	Name         string
+	Abbreviation string
+	Conference   string
+	CfbdID       int
+}
+
+// builtinAliases are spellings CFBD and ESPN use that don't normalize to the
+// school's name, mapped to the name on the school list.
+var builtinAliases = map[string]string{
+	"Mississippi":            "Ole Miss",
+	"Connecticut":            "UConn",
+	"Miami OH":               "Miami (OH)",
+	"Miami (FL)":             "Miami",
+	"Massachusetts":          "UMass",
+	"Louisiana Monroe":       "UL Monroe",
+	"Louisiana Lafayette":    "Louisiana",
+	"Appalachian State":      "App State",
+	"Southern Mississippi":   "Southern Miss",
+	"Sam Houston State":      "Sam Houston",
+	"North Carolina State":   "NC State",
+	"Central Florida":        "UCF",
+	"Southern California":    "USC",
+	"Brigham Young":          "BYU",
+	"Florida International":  "FIU",
+	"Texas San Antonio":      "UTSA",
+	"Middle Tennessee State": "Middle Tennessee",
+	"MTSU":                   "Middle Tennessee",
+	"Pitt":                   "Pittsburgh",
+	"UNC":                    "North Carolina",
+	"ECU":                    "East Carolina",
+	"WKU":                    "Western Kentucky",
+	"GA Southern":            "Georgia Southern",
+	"C Michigan":             "Central Michigan",
+	"E Michigan":             "Eastern Michigan",
+	"W Michigan":             "Western Michigan",
+	"N Illinois":             "Northern Illinois",
+	"Coastal":                "Coastal Carolina",
+}
+
+// collegeSports are the sports whose teams are on the school list. Pro teams
+// are passed through as ESPN names them.
+var collegeSports = map[string]bool{
+	models.SportCFB: true,
+	models.SportCBB: true,
+}
+
+// registry maps every known spelling and ID of a school to one Team.
+type registry struct {
+	mu     sync.RWMutex
+	teams  []Team
+	byKey  map[string]int
+	byAbbr map[string]int
+	byCFBD map[int]int
+	// byESPN is learned as ESPN teams are matched by name, keyed by sport and
+	// ESPN team ID.
+	byESPN map[string]int
+}
+
+var (
+	teams   = newRegistry(nil)
+	teamsMu sync.RWMutex
+)
+
+func currentRegistry() *registry {
+	teamsMu.RLock()
+	defer teamsMu.RUnlock()
+	return teams
+}
+
+func newRegistry(teamList external.TeamList) *registry {
+	r := &registry{
+		byKey:  make(map[string]int),
+		byAbbr: make(map[string]int),
+		byCFBD: make(map[int]int),
+		byESPN: make(map[string]int),
+	}
+	for _, school := range teamList {
+		idx := len(r.teams)
+		r.teams = append(r.teams, Team{
+			Name:         school.Name,
+			Abbreviation: school.Abbreviation,
+			Conference:   school.Conference,
+			CfbdID:       school.ApiId,
+		})
+		r.byKey[teamKey(school.Name)] = idx
+		if school.Mascot != "" {
+			r.byKey[teamKey(school.Name+" "+school.Mascot)] = idx
+		}
+		if school.Abbreviation != "" {
+			r.byAbbr[teamKey(school.Abbreviation)] = idx
+		}
+		if school.ApiId != 0 {
+			r.byCFBD[school.ApiId] = idx
+		}
+	}
+	for alias, name := range builtinAliases {
+		if idx, found := r.byKey[teamKey(name)]; found {
+			if _, taken := r.byKey[teamKey(alias)]; !taken {
+				r.byKey[teamKey(alias)] = idx
+			}
+		}
+	}
+	return r
+}
+
+// RefreshTeams reloads the school list the registry is built from. Lookups
+// made before the first refresh leave names as they are.
+func RefreshTeams() error {
+	teamList, err := extService.GetTeamList()
+	if err != nil {
+		return err
+	}
+	next := newRegistry(teamList)
+
+	// Keep the ESPN IDs already learned.
+	previous := currentRegistry()
+	previous.mu.RLock()
+	for key, idx := range previous.byESPN {
+		if newIdx, found := next.byKey[teamKey(previous.teams[idx].Name)]; found {
+			next.byESPN[key] = newIdx
+		}
+	}
+	previous.mu.RUnlock()
+
+	teamsMu.Lock()
+	teams = next
+	teamsMu.Unlock()
+	return nil
+}
+
+func (r *registry) lookup(name string) (Team, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key := teamKey(name)
+	if idx, found := r.byKey[key]; found {
+		return r.teams[idx], true
+	}
+	if idx, found := r.byAbbr[key]; found {
+		return r.teams[idx], true
+	}
+	return Team{}, false
+}
+
+// teamKey normalizes a team name for lookups: case, punctuation and accents
+// are dropped, "&" is "and" and a trailing "St" is "State".
+func teamKey(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "&", " and "))
+	var b strings.Builder
+	for _, r := range name {
+		switch r {
+		case 'á', 'à', 'â', 'ä':
+			r = 'a'
+		case 'é', 'è', 'ê', 'ë':
+			r = 'e'
+		case 'í', 'ì', 'î', 'ï':
+			r = 'i'
+		case 'ó', 'ò', 'ô', 'ö':
+			r = 'o'
+		case 'ú', 'ù', 'û', 'ü':
+			r = 'u'
+		case 'ñ':
+			r = 'n'
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.':
+			b.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(b.String())
+	if len(words) > 1 && words[len(words)-1] == "st" {
+		words[len(words)-1] = "state"
+	}
+	return strings.Join(words, " ")
+}
+
+// Resolver resolves team names for one guild, trying the guild's aliases
+// before the registry.
+type Resolver struct {
+	registry *registry
+	aliases  map[string]string
+}
+
+// ForGuild returns a resolver with the guild's aliases. If they can't be
+// loaded, the resolver uses the registry alone.
+func ForGuild(db *gorm.DB, guildID string) *Resolver {
+	resolver := &Resolver{registry: currentRegistry(), aliases: make(map[string]string)}
+
+	var aliases []models.TeamAlias
+	if err := db.Where("guild_id = ?", guildID).Find(&aliases).Error; err != nil {
+		log.Printf("Error loading team aliases for guild %s: %v\n", guildID, err)
+		return resolver
+	}
+	for _, alias := range aliases {
+		resolver.aliases[teamKey(alias.Alias)] = alias.Team
+	}
+	return resolver
+}
+
+// Shared returns a resolver with no guild's aliases, for data shared by every
+// guild.
+func Shared() *Resolver {
+	return &Resolver{registry: currentRegistry(), aliases: make(map[string]string)}
+}
+
+// Lookup returns the team a name refers to.
+func (r *Resolver) Lookup(name string) (Team, bool) {
+	if r == nil {
+		return Team{}, false
+	}
+	if team, found := r.aliases[teamKey(name)]; found {
+		if known, found := r.registry.lookup(team); found {
+			return known, true
+		}
+		return Team{Name: team}, true
+	}
+	return r.registry.lookup(name)
+}
+
+// Name returns a team's canonical name, or the name as given if it isn't a
+// known team.
+func (r *Resolver) Name(name string) string {
+	if team, found := r.Lookup(name); found {
+		return team.Name
+	}
+	return strings.TrimSpace(name)
+}
+
+// Same reports whether two names refer to the same team.
+func (r *Resolver) Same(a string, b string) bool {
+	return teamKey(r.Name(a)) == teamKey(r.Name(b))
+}
+
+// Option returns a bet option with its team's canonical name, keeping any
+// spread after it, such as "Ohio State -3.5" for "Ohio St -3.5".
+func (r *Resolver) Option(option string) string {
+	school := common.GetSchoolName(option)
+	name := r.Name(school)
+	if name == school {
+		return option
+	}
+	return name + strings.TrimPrefix(option, school)
+}
+
+// Conference returns the conference of a known team.
+func (r *Resolver) Conference(name string) string {
+	team, _ := r.Lookup(name)
+	return team.Conference
+}
+
+// CFBD returns the canonical name of a CFBD team, by CFBD ID or by name.
+func (r *Resolver) CFBD(id int, name string) string {
+	if r != nil && id != 0 {
+		r.registry.mu.RLock()
+		idx, found := r.registry.byCFBD[id]
+		var team Team
+		if found {
+			team = r.registry.teams[idx]
+		}
+		r.registry.mu.RUnlock()
+		if found {
+			return team.Name
+		}
+	}
+	return r.Name(name)
+}
+
+// ESPN returns the canonical name of an ESPN team. College teams are matched by
+// ESPN ID once seen, then by location, display name, short name and
+// abbreviation. Other sports keep ESPN's short name.
+func (r *Resolver) ESPN(sport string, team external.ESPN_Team) string {
+	if r == nil || !collegeSports[sport] {
+		return team.ShortDisplayName
+	}
+
+	espnKey := sport + ":" + team.ID
+	r.registry.mu.RLock()
+	idx, found := r.registry.byESPN[espnKey]
+	var known Team
+	if found {
+		known = r.registry.teams[idx]
+	}
+	r.registry.mu.RUnlock()
+	if found {
+		return known.Name
+	}
+
+	names := []string{team.ShortDisplayName, team.Location, team.DisplayName, team.Abbreviation}
+	// The guild's aliases come first but aren't learned, since other guilds
+	// don't share them.
+	for _, name := range names {
+		if alias, found := r.aliases[teamKey(name)]; found && name != "" {
+			return r.Name(alias)
+		}
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if match, found := r.registry.lookup(name); found {
+			r.learnESPN(espnKey, match.Name)
+			return match.Name
+		}
+	}
+	return team.ShortDisplayName
+}
+
+func (r *Resolver) learnESPN(espnKey string, name string) {
+	if strings.HasSuffix(espnKey, ":") {
+		return
+	}
+	r.registry.mu.Lock()
+	defer r.registry.mu.Unlock()
+	if idx, found := r.registry.byKey[teamKey(name)]; found {
+		r.registry.byESPN[espnKey] = idx
+	}
+}
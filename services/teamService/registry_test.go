@@ -0,0 +1,115 @@
+package teamService
+
+import (
+	"encoding/json"
+	"perfectOddsBot/models"
+	"perfectOddsBot/models/external"
+	"testing"
+)
+
+func testResolver(t *testing.T, aliases map[string]string) *Resolver {
+	t.Helper()
+	var teamList external.TeamList
+	body := `[
+		{"name":"Ohio State","mascot":"Buckeyes","abbreviation":"OSU","conference":"Big Ten","apiID":194},
+		{"name":"Michigan State","mascot":"Spartans","abbreviation":"MSU","conference":"Big Ten","apiID":127},
+		{"name":"Ole Miss","mascot":"Rebels","abbreviation":"MISS","conference":"SEC","apiID":145},
+		{"name":"San José State","mascot":"Spartans","abbreviation":"SJSU","conference":"Mountain West","apiID":23}
+	]`
+	if err := json.Unmarshal([]byte(body), &teamList); err != nil {
+		t.Fatalf("failed to parse team list: %v", err)
+	}
+	resolver := &Resolver{registry: newRegistry(teamList), aliases: make(map[string]string)}
+	for alias, team := range aliases {
+		resolver.aliases[teamKey(alias)] = team
+	}
+	return resolver
+}
+
+func TestResolverName(t *testing.T) {
+	teams := testResolver(t, map[string]string{"tOSU": "Ohio State", "Zags": "Gonzaga"})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Canonical name", "Ohio State", "Ohio State"},
+		{"Abbreviated State", "Michigan St", "Michigan State"},
+		{"Case and punctuation", "michigan st.", "Michigan State"},
+		{"Accents dropped", "San Jose St", "San José State"},
+		{"Built-in alias", "Mississippi", "Ole Miss"},
+		{"Name with mascot", "Ohio State Buckeyes", "Ohio State"},
+		{"Abbreviation", "MSU", "Michigan State"},
+		{"Guild alias", "TOSU", "Ohio State"},
+		{"Guild alias to a school not on the list", "zags", "Gonzaga"},
+		{"Unknown team", "Gonzaga ", "Gonzaga"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teams.Name(tt.input); got != tt.expected {
+				t.Errorf("Name(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolverESPN(t *testing.T) {
+	teams := testResolver(t, nil)
+
+	espnTeam := external.ESPN_Team{ID: "127", Location: "Michigan State", ShortDisplayName: "Michigan St", DisplayName: "Michigan State Spartans"}
+	if got := teams.ESPN(models.SportCBB, espnTeam); got != "Michigan State" {
+		t.Errorf("expected Michigan State, got %q", got)
+	}
+
+	// Once matched, the ESPN ID alone finds the team.
+	if got := teams.ESPN(models.SportCBB, external.ESPN_Team{ID: "127", ShortDisplayName: "Mich St"}); got != "Michigan State" {
+		t.Errorf("expected the learned ESPN ID to match, got %q", got)
+	}
+
+	// Pro teams keep ESPN's name even when they share a college's location.
+	if got := teams.ESPN(models.SportNBA, external.ESPN_Team{ID: "127", Location: "Michigan State", ShortDisplayName: "Pistons"}); got != "Pistons" {
+		t.Errorf("expected a pro team to keep its name, got %q", got)
+	}
+}
+
+func TestResolverCFBD(t *testing.T) {
+	teams := testResolver(t, nil)
+
+	if got := teams.CFBD(145, "Mississippi"); got != "Ole Miss" {
+		t.Errorf("expected the CFBD ID to match, got %q", got)
+	}
+	if got := teams.CFBD(0, "Ohio St"); got != "Ohio State" {
+		t.Errorf("expected a name match without an ID, got %q", got)
+	}
+}
+
+func TestResolverOption(t *testing.T) {
+	teams := testResolver(t, nil)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Michigan St -3.5", "Michigan State -3.5"},
+		{"Mississippi +7.5", "Ole Miss +7.5"},
+		{"Ohio State", "Ohio State"},
+		{"Over 48.5", "Over 48.5"},
+	}
+	for _, tt := range tests {
+		if got := teams.Option(tt.input); got != tt.expected {
+			t.Errorf("Option(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	if !teams.Same("Michigan St", "Michigan State Spartans") {
+		t.Error("expected two spellings of a team to be the same team")
+	}
+	if teams.Same("Ohio State", "Michigan State") {
+		t.Error("expected different teams not to match")
+	}
+	if conf := teams.Conference("Ole Miss"); conf != "SEC" {
+		t.Errorf("expected SEC, got %q", conf)
+	}
+}